@@ -0,0 +1,60 @@
+// Command grant-role gives a role to an existing user. Sign-up only ever
+// creates viewers, so this is how the first admin is made:
+//
+//	go run ./cmd/grant-role -username alice -role admin
+//
+// The database is configured with the same DB_* variables (or .env file) as
+// the server.
+package main
+
+import (
+	"backend/pkg/database"
+	"backend/pkg/repository"
+	"backend/pkg/service"
+	"flag"
+	"fmt"
+	"github.com/joho/godotenv"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	username := flag.String("username", "", "user to give the role to, must have signed up already")
+	role := flag.String("role", "", "role to give: admin, dispatcher, hr or viewer")
+	flag.Parse()
+	if strings.TrimSpace(*username) == "" || strings.TrimSpace(*role) == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if _, err := os.Stat(".env"); err == nil {
+		err := godotenv.Load()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	connStr := fmt.Sprintf("host=%s port=%s user=%s "+
+		"password=%s dbname=%s sslmode=disable",
+		os.Getenv("DB_HOST"), os.Getenv("DB_PORT"), os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"), os.Getenv("DB_NAME"))
+	db, err := database.NewPostgresDatabase(connStr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	userRepo, err := repository.NewPostgresUserRepository(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+	user, err := userRepo.GetByUsername(strings.TrimSpace(*username))
+	if err != nil {
+		log.Fatalf("%s: %v", *username, err)
+	}
+	// Granting a role needs neither sessions nor token settings.
+	users := service.NewUserService(userRepo, nil, service.TokenSettings{})
+	user, err = users.GrantRole(user.ID, strings.TrimSpace(*role))
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%s now has the roles %s", user.Username, strings.Join(user.Roles, ", "))
+}
@@ -5,8 +5,10 @@ import (
 	"backend/pkg"
 	"backend/pkg/controller"
 	"backend/pkg/database"
+	"backend/pkg/models"
 	"backend/pkg/repository"
 	"backend/pkg/service"
+	"errors"
 	"fmt"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -16,6 +18,7 @@ import (
 	"github.com/swaggo/gin-swagger"
 	"os"
 	"strings"
+	"time"
 )
 
 // @title           Bus manager API
@@ -39,6 +42,14 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	workTimeRules, err := initWorkTimeRules()
+	if err != nil {
+		panic(err)
+	}
+	tokenSettings, err := initTokenSettings()
+	if err != nil {
+		panic(err)
+	}
 	db, err := database.NewPostgresDatabase(connStr)
 	if err != nil {
 		panic(err)
@@ -63,16 +74,103 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	sessionRepo, err := repository.NewPostgresSessionRepository(db)
+	if err != nil {
+		panic(err)
+	}
+	dutyRepo, err := repository.NewPostgresDutyRepository(db)
+	if err != nil {
+		panic(err)
+	}
+	licenseRepo, err := repository.NewPostgresDriverLicenseRepository(db)
+	if err != nil {
+		panic(err)
+	}
+	medicalCheckRepo, err := repository.NewPostgresMedicalCheckRepository(db)
+	if err != nil {
+		panic(err)
+	}
+	accessLogRepo, err := repository.NewPostgresPersonalDataAccessRepository(db)
+	if err != nil {
+		panic(err)
+	}
+	absenceRepo, err := repository.NewPostgresDriverAbsenceRepository(db)
+	if err != nil {
+		panic(err)
+	}
+	qualificationRepo, err := repository.NewPostgresDriverQualificationRepository(db)
+	if err != nil {
+		panic(err)
+	}
+	positionRepo, err := repository.NewPostgresPositionRepository(db)
+	if err != nil {
+		panic(err)
+	}
+	segmentRepo, err := repository.NewPostgresSegmentRepository(db)
+	if err != nil {
+		panic(err)
+	}
+	tripRepo, err := repository.NewPostgresTripRepository(db)
+	if err != nil {
+		panic(err)
+	}
+	incidentRepo, err := repository.NewPostgresIncidentRepository(db)
+	if err != nil {
+		panic(err)
+	}
+	passengerCountRepo, err := repository.NewPostgresPassengerCountRepository(db)
+	if err != nil {
+		panic(err)
+	}
+	alertRepo, err := repository.NewPostgresServiceAlertRepository(db)
+	if err != nil {
+		panic(err)
+	}
+	repairRepo, err := repository.NewPostgresBusRepairRepository(db)
+	if err != nil {
+		panic(err)
+	}
 	busService := service.NewBusService(busRepo)
 	driverService := service.NewDriverService(driverRepo)
 	busStopService := service.NewBusStopService(busStopRepo)
-	routeService := service.NewRouteService(routeRepo, driverRepo, busRepo, busStopRepo)
-	userService := service.NewUserService(userRepo)
+	licenseService := service.NewLicenseService(licenseRepo, driverRepo)
+	absenceService := service.NewAbsenceService(absenceRepo, driverRepo, dutyRepo)
+	qualificationService := service.NewQualificationService(qualificationRepo, driverRepo, busRepo)
+	routeService := service.NewRouteService(routeRepo, driverRepo, busRepo, busStopRepo, licenseService, absenceService, qualificationService, repairRepo)
+	userService := service.NewUserService(userRepo, sessionRepo, tokenSettings)
+	alertService := service.NewAlertService(alertRepo, routeRepo, busStopRepo)
+	privacyService := service.NewPrivacyService(accessLogRepo)
+	arrivalService := service.NewArrivalService(positionRepo, routeRepo, busRepo, busStopRepo, segmentRepo)
+	departureService := service.NewDepartureService(tripRepo, routeRepo, busRepo, busStopRepo, arrivalService, alertService)
+	incidentService := service.NewIncidentService(incidentRepo, busRepo, driverRepo, routeRepo)
+	ridershipService := service.NewRidershipService(passengerCountRepo, busRepo, routeRepo, busStopRepo, tripRepo)
+	scheduleService := service.NewScheduleService(tripRepo, dutyRepo, routeRepo)
+	telemetryService := service.NewTelemetryService(positionRepo, busRepo, routeRepo, service.NewPositionHub(), arrivalService, scheduleService)
+	gtfsRealtimeService := service.NewGtfsRealtimeService(positionRepo, routeRepo, busRepo, segmentRepo, tripRepo)
+	complianceService := service.NewComplianceService(dutyRepo, workTimeRules)
+	medicalCheckService := service.NewMedicalCheckService(medicalCheckRepo, dutyRepo, driverRepo)
+	dispatchService := service.NewDispatchService(repairRepo, routeRepo, busRepo, qualificationService)
+	rosterService := service.NewRosterService(dutyRepo, routeRepo, driverRepo, busRepo, complianceService, licenseService, medicalCheckService, absenceService, qualificationService)
 	busController := controller.NewBusController(*busService)
-	driverController := controller.NewDriverController(*driverService)
-	busStopController := controller.NewBusStopController(*busStopService)
-	routeController := controller.NewRouteController(routeService)
+	driverController := controller.NewDriverController(*driverService, privacyService)
+	busStopController := controller.NewBusStopController(*busStopService, alertService)
+	routeController := controller.NewRouteController(routeService, privacyService, alertService)
 	userController := controller.NewUserController(*userService)
+	rosterController := controller.NewRosterController(rosterService)
+	complianceController := controller.NewComplianceController(complianceService)
+	licenseController := controller.NewLicenseController(licenseService)
+	medicalCheckController := controller.NewMedicalCheckController(medicalCheckService)
+	absenceController := controller.NewAbsenceController(absenceService, privacyService)
+	qualificationController := controller.NewQualificationController(qualificationService)
+	telemetryController := controller.NewTelemetryController(telemetryService, *userService)
+	gtfsRealtimeController := controller.NewGtfsRealtimeController(gtfsRealtimeService)
+	arrivalController := controller.NewArrivalController(arrivalService)
+	departureController := controller.NewDepartureController(departureService)
+	scheduleController := controller.NewScheduleController(scheduleService)
+	incidentController := controller.NewIncidentController(incidentService)
+	ridershipController := controller.NewRidershipController(ridershipService)
+	alertController := controller.NewAlertController(alertService)
+	dispatchController := controller.NewDispatchController(dispatchService)
 
 	router := gin.Default()
 	router.Use(cors.New(cors.Config{
@@ -91,7 +189,7 @@ func main() {
 		buses := api.Group("/buses")
 		buses.Use(func(c *gin.Context) {
 			pkg.UserIdentity(c, *userService)
-		})
+		}, pkg.Authorize(pkg.WriteRoles(models.RoleDispatcher)))
 		{
 			buses.GET("/id/:id", busController.GetById)
 			buses.GET("/number/:number", busController.GetByNumber)
@@ -99,27 +197,59 @@ func main() {
 			buses.POST("/", busController.Add)
 			buses.DELETE("/:id", busController.DeleteById)
 			buses.PUT("/:id", busController.UpdateById)
+			buses.GET("/:id/position", telemetryController.GetLatest)
+		}
+
+		// Группа для телеметрии бортовых блоков
+		telemetry := api.Group("/telemetry")
+		telemetry.Use(func(c *gin.Context) {
+			pkg.UserIdentity(c, *userService)
+		}, pkg.Authorize(pkg.WriteRoles(models.RoleDispatcher)))
+		{
+			telemetry.POST("/positions", telemetryController.Ingest)
+		}
+
+		// Поток позиций: EventSource не умеет передавать заголовки, поэтому
+		// поток открывается и по короткоживущему токену из /auth/stream-token
+		stream := api.Group("/telemetry/stream")
+		stream.Use(func(c *gin.Context) {
+			pkg.StreamIdentity(c, *userService)
+		}, pkg.Authorize(pkg.WriteRoles(models.RoleDispatcher)))
+		{
+			stream.GET("", telemetryController.Stream)
 		}
 
 		// Группа для водителей
 		drivers := api.Group("/drivers")
 		drivers.Use(func(c *gin.Context) {
 			pkg.UserIdentity(c, *userService)
-		})
+		}, pkg.Authorize(pkg.WriteRoles(models.RoleHR)))
 		{
 			drivers.GET("/id/:id", driverController.GetById)
 			drivers.GET("/series/:series", driverController.GetByPassportSeries)
+			drivers.GET("/search", driverController.Search)
 			drivers.GET("/", driverController.GetAll)
 			drivers.POST("/", driverController.Add)
 			drivers.DELETE("/:id", driverController.DeleteById)
 			drivers.PUT("/:id", driverController.UpdateById)
+			drivers.GET("/:id/license", licenseController.GetByDriverId)
+			drivers.PUT("/:id/license", licenseController.Save)
+			drivers.GET("/licenses/expiring", licenseController.GetExpiring)
+			drivers.GET("/available", absenceController.GetAvailable)
+			drivers.GET("/:id/absences", absenceController.GetByDriverId)
+			drivers.POST("/:id/absences", absenceController.Add)
+			drivers.DELETE("/absences/:absenceId", absenceController.DeleteById)
+			drivers.GET("/:id/qualifications", qualificationController.GetByDriverId)
+			drivers.PUT("/:id/qualifications", qualificationController.Save)
+			drivers.DELETE("/:id/qualifications", qualificationController.Delete)
+			drivers.GET("/qualifications/matrix", qualificationController.GetMatrix)
 		}
 
 		// Группа для остановок
 		stops := api.Group("/stops")
 		stops.Use(func(c *gin.Context) {
 			pkg.UserIdentity(c, *userService)
-		})
+		}, pkg.Authorize(pkg.WriteRoles(models.RoleDispatcher)))
 		{
 			stops.GET("/id/:id", busStopController.GetById)
 			stops.GET("/name/:name", busStopController.GetByName)
@@ -127,13 +257,15 @@ func main() {
 			stops.POST("/", busStopController.Add)
 			stops.DELETE("/:id", busStopController.DeleteById)
 			stops.PUT("/:id", busStopController.UpdateById)
+			stops.GET("/:id/arrivals", arrivalController.GetStopArrivals)
+			stops.GET("/:id/departures", departureController.GetDepartures)
 		}
 
 		// Группа для маршрутов
 		routes := api.Group("/routes")
 		routes.Use(func(c *gin.Context) {
 			pkg.UserIdentity(c, *userService)
-		})
+		}, pkg.Authorize(pkg.WriteRoles(models.RoleDispatcher)))
 		{
 			routes.GET("/:id", routeController.GetById)
 			routes.GET("/number/:number", routeController.GetByNumber)
@@ -152,11 +284,156 @@ func main() {
 			routes.DELETE("/:id/buses/:busId", routeController.UnassignBus)
 		}
 
+		// Группа для графика смен
+		roster := api.Group("/roster")
+		roster.Use(func(c *gin.Context) {
+			pkg.UserIdentity(c, *userService)
+		}, pkg.Authorize(pkg.WriteRoles(models.RoleDispatcher)))
+		{
+			roster.GET("/:id", rosterController.GetById)
+			roster.GET("/day/:date", rosterController.GetDay)
+			roster.GET("/week/:date", rosterController.GetWeek)
+			roster.POST("/", rosterController.Add)
+			roster.DELETE("/:id", rosterController.DeleteById)
+			roster.PUT("/:id", rosterController.UpdateById)
+			roster.POST("/:id/start", rosterController.StartDuty)
+		}
+
+		// Группа для расписания рейсов
+		schedule := api.Group("/schedule")
+		schedule.Use(func(c *gin.Context) {
+			pkg.UserIdentity(c, *userService)
+		}, pkg.Authorize(pkg.WriteRoles(models.RoleDispatcher)))
+		{
+			schedule.GET("/trips/:id", scheduleController.GetTrip)
+			schedule.POST("/trips", scheduleController.AddTrip)
+			schedule.DELETE("/trips/:id", scheduleController.DeleteTrip)
+			schedule.GET("/duties/:id/trips", scheduleController.GetTripsByDutyId)
+			schedule.GET("/performance", scheduleController.GetPerformance)
+		}
+
+		// Группа для происшествий
+		incidents := api.Group("/incidents")
+		incidents.Use(func(c *gin.Context) {
+			pkg.UserIdentity(c, *userService)
+		}, pkg.Authorize(pkg.WriteRoles(models.RoleDispatcher)))
+		{
+			incidents.GET("/", incidentController.Find)
+			incidents.GET("/:id", incidentController.GetById)
+			incidents.POST("/", incidentController.Add)
+			incidents.PUT("/:id/status", incidentController.ChangeStatus)
+		}
+
+		// Группа для пассажиропотока
+		ridership := api.Group("/ridership")
+		ridership.Use(func(c *gin.Context) {
+			pkg.UserIdentity(c, *userService)
+		}, pkg.Authorize(pkg.WriteRoles(models.RoleDispatcher)))
+		{
+			ridership.POST("/counts", ridershipController.Ingest)
+			ridership.GET("/report", ridershipController.GetReport)
+		}
+
+		// Группа для замены неисправных автобусов
+		dispatch := api.Group("/dispatch")
+		dispatch.Use(func(c *gin.Context) {
+			pkg.UserIdentity(c, *userService)
+		}, pkg.Authorize(pkg.OnlyRoles(models.RoleDispatcher)))
+		{
+			dispatch.GET("/buses/:id/replacements", dispatchController.ProposeReplacements)
+			dispatch.POST("/buses/:id/repair/finish", dispatchController.FinishRepair)
+			dispatch.POST("/breakdowns", dispatchController.ReportBreakdown)
+		}
+
+		// Группа для публикации объявлений об изменениях движения
+		alerts := api.Group("/alerts")
+		alerts.Use(func(c *gin.Context) {
+			pkg.UserIdentity(c, *userService)
+		}, pkg.Authorize(pkg.WriteRoles(models.RoleDispatcher)))
+		{
+			alerts.POST("/", alertController.Publish)
+			alerts.POST("/:id/end", alertController.End)
+		}
+
+		// Группа для действующих объявлений, открыта для пассажирских каналов
+		publicAlerts := api.Group("/alerts")
+		{
+			publicAlerts.GET("/", alertController.GetActive)
+			publicAlerts.GET("/:id", alertController.GetById)
+		}
+
+		// Группа для предрейсовых медосмотров
+		medical := api.Group("/medical-checks")
+		medical.Use(func(c *gin.Context) {
+			pkg.UserIdentity(c, *userService)
+		}, pkg.Authorize(pkg.OnlyRoles(models.RoleDispatcher, models.RoleHR)))
+		{
+			medical.GET("/:id", medicalCheckController.GetById)
+			medical.POST("/", medicalCheckController.Add)
+			medical.GET("/drivers/:id", medicalCheckController.GetByDriverId)
+			medical.GET("/pending", medicalCheckController.GetPending)
+		}
+
+		// Группа для контроля режима труда и отдыха
+		compliance := api.Group("/compliance")
+		compliance.Use(func(c *gin.Context) {
+			pkg.UserIdentity(c, *userService)
+		})
+		{
+			compliance.GET("/drivers/:id", complianceController.GetDriverReport)
+		}
+
+		// Группа для конфликтов назначений
+		conflicts := api.Group("/conflicts")
+		conflicts.Use(func(c *gin.Context) {
+			pkg.UserIdentity(c, *userService)
+		})
+		{
+			conflicts.GET("/buses", routeController.GetBusConflicts)
+		}
+
+		// Группа для GTFS-Realtime, открыта для сторонних приложений
+		gtfsRealtime := api.Group("/gtfs-rt")
+		{
+			gtfsRealtime.GET("/vehicle-positions", gtfsRealtimeController.GetVehiclePositions)
+			gtfsRealtime.GET("/trip-updates", gtfsRealtimeController.GetTripUpdates)
+		}
+
+		// Группа для табло на остановках, открыта для дисплеев без авторизации
+		board := api.Group("/board")
+		{
+			board.GET("/stops/:id", departureController.GetBoardPage)
+		}
+
 		// Группа для пользователей
 		users := api.Group("/auth")
 		{
 			users.POST("/sign-in", userController.Signin)
 			users.POST("/sign-up", userController.Signup)
+			users.POST("/refresh", userController.Refresh)
+		}
+
+		// Группа для сессий пользователя: выход и токен для потока позиций
+		sessions := api.Group("/auth")
+		sessions.Use(func(c *gin.Context) {
+			pkg.UserIdentity(c, *userService)
+		})
+		{
+			sessions.POST("/logout", userController.Logout)
+			sessions.POST("/logout-all", userController.LogoutAll)
+			sessions.POST("/stream-token", userController.StreamToken)
+		}
+
+		// Группа для управления ролями пользователей, только для администраторов
+		accounts := api.Group("/users")
+		accounts.Use(func(c *gin.Context) {
+			pkg.UserIdentity(c, *userService)
+		}, pkg.Authorize(pkg.OnlyRoles(models.RoleAdmin)))
+		{
+			accounts.GET("/", userController.GetAll)
+			accounts.POST("/:id/roles/:role", userController.GrantRole)
+			accounts.DELETE("/:id/roles/:role", userController.RevokeRole)
+			accounts.DELETE("/:id/sessions", userController.RevokeSessions)
 		}
 	}
 
@@ -183,3 +460,76 @@ func initConfig() (error, string, string) {
 	fmt.Println(connStr)
 	return nil, connStr, serverConn
 }
+
+// initWorkTimeRules reads driver working time limits from the environment,
+// e.g. WORKTIME_MAX_DAILY_DRIVING=9h. Unset variables keep the defaults.
+func initWorkTimeRules() (service.WorkTimeRules, error) {
+	rules := service.DefaultWorkTimeRules()
+	limits := map[string]*time.Duration{
+		"WORKTIME_MAX_DAILY_DRIVING":  &rules.MaxDailyDriving,
+		"WORKTIME_MIN_DAILY_REST":     &rules.MinDailyRest,
+		"WORKTIME_MAX_WEEKLY_DRIVING": &rules.MaxWeeklyDriving,
+		"WORKTIME_BREAK_AFTER":        &rules.BreakAfter,
+		"WORKTIME_MIN_BREAK":          &rules.MinBreak,
+	}
+	for name, limit := range limits {
+		value := strings.TrimSpace(os.Getenv(name))
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return rules, fmt.Errorf("%s: %w", name, err)
+		}
+		*limit = d
+	}
+	return rules, nil
+}
+
+// initTokenSettings reads the JWT signing keys from the environment as
+// comma separated kid:secret pairs, oldest first, e.g.
+// JWT_SIGNING_KEYS=2025-01:<secret>,2025-07:<secret>. New tokens are signed
+// with the last key, so a key is rotated by appending a new one and dropped
+// once the tokens it signed have expired. JWT_TOKEN_TTL sets the access token
+// lifetime and JWT_REFRESH_TTL how long a session lasts without a refresh.
+func initTokenSettings() (service.TokenSettings, error) {
+	settings := service.TokenSettings{TTL: service.DefaultTokenTTL(), RefreshTTL: service.DefaultRefreshTTL()}
+	durations := map[string]*time.Duration{
+		"JWT_TOKEN_TTL":   &settings.TTL,
+		"JWT_REFRESH_TTL": &settings.RefreshTTL,
+	}
+	for name, duration := range durations {
+		value := strings.TrimSpace(os.Getenv(name))
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return settings, fmt.Errorf("%s: %w", name, err)
+		}
+		*duration = d
+	}
+	ids := map[string]bool{}
+	for _, pair := range strings.Split(os.Getenv("JWT_SIGNING_KEYS"), ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(pair, ":")
+		if !ok || id == "" {
+			return settings, errors.New("JWT_SIGNING_KEYS: expected kid:secret pairs")
+		}
+		if ids[id] {
+			return settings, fmt.Errorf("JWT_SIGNING_KEYS: key %s is listed twice", id)
+		}
+		if len(secret) < service.MinSigningKeyLength {
+			return settings, fmt.Errorf("JWT_SIGNING_KEYS: key %s is shorter than %d bytes", id, service.MinSigningKeyLength)
+		}
+		ids[id] = true
+		settings.Keys = append(settings.Keys, service.SigningKey{ID: id, Secret: []byte(secret)})
+	}
+	if len(settings.Keys) == 0 {
+		return settings, errors.New("JWT_SIGNING_KEYS is not set")
+	}
+	return settings, nil
+}
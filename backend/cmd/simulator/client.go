@@ -0,0 +1,132 @@
+package main
+
+import (
+	"backend/pkg/models"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// apiClient talks to the bus manager API on behalf of the simulator
+type apiClient struct {
+	baseUrl string
+	token   string
+	// refreshToken renews the token shortly before expiresAt, it is empty
+	// when the token was given on the command line
+	refreshToken string
+	expiresAt    time.Time
+	http         *http.Client
+}
+
+type tokensResponse struct {
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+func newApiClient(baseUrl string) *apiClient {
+	return &apiClient{
+		baseUrl: strings.TrimRight(baseUrl, "/"),
+		http:    &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (c *apiClient) signIn(username, password string) error {
+	var response tokensResponse
+	err := c.do(http.MethodPost, "/auth/sign-in", models.User{Username: username, Password: password}, &response)
+	if err != nil {
+		return err
+	}
+	c.setTokens(response)
+	return nil
+}
+
+func (c *apiClient) refresh() error {
+	var response tokensResponse
+	err := c.do(http.MethodPost, "/auth/refresh", map[string]string{"refresh_token": c.refreshToken}, &response)
+	if err != nil {
+		return err
+	}
+	c.setTokens(response)
+	return nil
+}
+
+func (c *apiClient) setTokens(response tokensResponse) {
+	c.token = response.Token
+	c.refreshToken = response.RefreshToken
+	c.expiresAt = response.ExpiresAt
+}
+
+func (c *apiClient) getRoutes() ([]models.Route, error) {
+	var routes []models.Route
+	err := c.do(http.MethodGet, "/routes/", nil, &routes)
+	return routes, err
+}
+
+func (c *apiClient) getRouteStops(routeId string) ([]models.BusStop, error) {
+	var stops []models.BusStop
+	err := c.do(http.MethodGet, "/routes/"+routeId+"/stops", nil, &stops)
+	return stops, err
+}
+
+func (c *apiClient) getRouteBuses(routeId string) ([]models.Bus, error) {
+	var buses []models.Bus
+	err := c.do(http.MethodGet, "/routes/"+routeId+"/buses", nil, &buses)
+	return buses, err
+}
+
+func (c *apiClient) getBuses() ([]models.Bus, error) {
+	var buses []models.Bus
+	err := c.do(http.MethodGet, "/buses/", nil, &buses)
+	return buses, err
+}
+
+func (c *apiClient) pushPositions(positions []models.VehiclePosition) (*models.PositionBatchResult, error) {
+	result := &models.PositionBatchResult{}
+	err := c.do(http.MethodPost, "/telemetry/positions", positions, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// do sends the request with the JSON body and decodes the JSON response
+// into out. Error responses of the API are returned as errors.
+func (c *apiClient) do(method, path string, body, out interface{}) error {
+	if c.refreshToken != "" && !strings.HasPrefix(path, "/auth/") && time.Until(c.expiresAt) < time.Minute {
+		if err := c.refresh(); err != nil {
+			return err
+		}
+	}
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, c.baseUrl+path, &payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var apiError map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&apiError)
+		return fmt.Errorf("%s %s: %s %v", method, path, resp.Status, apiError)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,129 @@
+// Command simulator moves buses along their routes and pushes the positions
+// to the telemetry API, for demos and for testing real-time features
+// without on-board units.
+//
+//	go run ./cmd/simulator -api http://localhost:8080/api -username admin -password secret -buses 20
+package main
+
+import (
+	"backend/pkg/models"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"os/signal"
+	"time"
+)
+
+func main() {
+	apiUrl := flag.String("api", "http://localhost:8080/api", "bus manager API address")
+	username := flag.String("username", os.Getenv("SIMULATOR_USERNAME"), "dispatcher to push positions as")
+	password := flag.String("password", os.Getenv("SIMULATOR_PASSWORD"), "password of the user")
+	token := flag.String("token", os.Getenv("SIMULATOR_TOKEN"), "API token, used instead of signing in")
+	buses := flag.Int("buses", 10, "number of buses to simulate")
+	interval := flag.Duration("interval", 5*time.Second, "time between two position reports of a bus")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "random seed, repeat it to replay a simulation")
+	flag.Parse()
+
+	client := newApiClient(*apiUrl)
+	if *token != "" {
+		client.token = *token
+	} else {
+		err := client.signIn(*username, *password)
+		if err != nil {
+			log.Fatalf("sign in: %v", err)
+		}
+	}
+	rnd := rand.New(rand.NewSource(*seed))
+	vehicles, err := loadVehicles(client, *buses, rnd)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("simulating %d buses, seed %d", len(vehicles), *seed)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	last := time.Now()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			positions := make([]models.VehiclePosition, 0, len(vehicles))
+			for _, v := range vehicles {
+				v.advance(now, now.Sub(last))
+				positions = append(positions, v.position(now))
+			}
+			last = now
+			result, err := client.pushPositions(positions)
+			if err != nil {
+				log.Printf("push positions: %v", err)
+				continue
+			}
+			for _, rejection := range result.Rejected {
+				log.Printf("position of bus %s rejected: %s", rejection.BusID, rejection.Error)
+			}
+			log.Printf("pushed %d positions", result.Accepted)
+		}
+	}
+}
+
+// loadVehicles puts the buses on routes with at least two stops. Buses
+// assigned to a route drive it, the remaining ones are spread over the
+// routes in turn.
+func loadVehicles(client *apiClient, count int, rnd *rand.Rand) ([]*vehicle, error) {
+	routes, err := client.getRoutes()
+	if err != nil {
+		return nil, err
+	}
+	var routeIds []string
+	stops := make(map[string][]models.BusStop)
+	taken := make(map[string]bool)
+	var vehicles []*vehicle
+	for _, route := range routes {
+		routeStops, err := client.getRouteStops(route.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(routeStops) < 2 {
+			continue
+		}
+		routeIds = append(routeIds, route.ID)
+		stops[route.ID] = routeStops
+		assigned, err := client.getRouteBuses(route.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, bus := range assigned {
+			if taken[bus.ID] || len(vehicles) == count {
+				continue
+			}
+			taken[bus.ID] = true
+			vehicles = append(vehicles, newVehicle(bus.ID, routeStops, rnd))
+		}
+	}
+	if len(routeIds) == 0 {
+		return nil, fmt.Errorf("no route has two or more stops")
+	}
+	if len(vehicles) < count {
+		all, err := client.getBuses()
+		if err != nil {
+			return nil, err
+		}
+		for _, bus := range all {
+			if taken[bus.ID] || len(vehicles) == count {
+				continue
+			}
+			taken[bus.ID] = true
+			routeId := routeIds[len(vehicles)%len(routeIds)]
+			vehicles = append(vehicles, newVehicle(bus.ID, stops[routeId], rnd))
+		}
+	}
+	if len(vehicles) < count {
+		log.Printf("only %d buses found, simulating all of them", len(vehicles))
+	}
+	return vehicles, nil
+}
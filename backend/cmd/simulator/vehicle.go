@@ -0,0 +1,129 @@
+package main
+
+import (
+	"backend/pkg/models"
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	earthRadius = 6371000.0
+	// city buses cruise between these speeds in km/h, slower in traffic
+	minCruiseSpeed = 18.0
+	maxCruiseSpeed = 45.0
+	minDwell       = 15 * time.Second
+	maxDwell       = 45 * time.Second
+	// layover at the terminal before the bus heads back
+	minLayover = 2 * time.Minute
+	maxLayover = 5 * time.Minute
+)
+
+// vehicle is a simulated bus going back and forth along the stops of its
+// route
+type vehicle struct {
+	busId string
+	stops []models.BusStop
+	// the bus is between stops[leg] and stops[leg+1], travelled meters
+	// from stops[leg]
+	leg        int
+	travelled  float64
+	speed      float64
+	dwellUntil time.Time
+	rnd        *rand.Rand
+}
+
+// newVehicle puts the bus at a random point of its route, heading either way
+func newVehicle(busId string, stops []models.BusStop, rnd *rand.Rand) *vehicle {
+	v := &vehicle{busId: busId, rnd: rnd}
+	v.stops = append([]models.BusStop(nil), stops...)
+	if rnd.Intn(2) == 0 {
+		reverseStops(v.stops)
+	}
+	v.leg = rnd.Intn(len(v.stops) - 1)
+	v.travelled = rnd.Float64() * v.legLength()
+	v.speed = v.cruiseSpeed()
+	return v
+}
+
+// advance moves the bus by the time elapsed since the previous step. A bus
+// reaching a stop waits there, at the last stop it turns around.
+func (v *vehicle) advance(now time.Time, elapsed time.Duration) {
+	if now.Before(v.dwellUntil) {
+		return
+	}
+	v.travelled += v.speed / 3.6 * elapsed.Seconds()
+	if v.travelled < v.legLength() {
+		return
+	}
+	v.leg++
+	v.travelled = 0
+	v.speed = v.cruiseSpeed()
+	if v.leg == len(v.stops)-1 {
+		reverseStops(v.stops)
+		v.leg = 0
+		v.dwellUntil = now.Add(v.between(minLayover, maxLayover))
+		return
+	}
+	v.dwellUntil = now.Add(v.between(minDwell, maxDwell))
+}
+
+// position reports where the bus is now, as an on-board unit would
+func (v *vehicle) position(now time.Time) models.VehiclePosition {
+	from, to := v.stops[v.leg], v.stops[v.leg+1]
+	fraction := 0.0
+	if length := v.legLength(); length > 0 {
+		fraction = math.Min(v.travelled/length, 1)
+	}
+	speed := 0.0
+	if !now.Before(v.dwellUntil) {
+		// traffic makes the speed jump around the cruise speed
+		speed = math.Max(0, v.speed+v.rnd.NormFloat64()*3)
+	}
+	return models.VehiclePosition{
+		BusID:      v.busId,
+		RecordedAt: now.UTC(),
+		Lat:        from.Lat + (to.Lat-from.Lat)*fraction,
+		Long:       from.Long + (to.Long-from.Long)*fraction,
+		Speed:      math.Round(speed*10) / 10,
+		Heading:    math.Mod(math.Round(bearing(from.Lat, from.Long, to.Lat, to.Long)), 360),
+	}
+}
+
+func (v *vehicle) legLength() float64 {
+	from, to := v.stops[v.leg], v.stops[v.leg+1]
+	return distance(from.Lat, from.Long, to.Lat, to.Long)
+}
+
+func (v *vehicle) cruiseSpeed() float64 {
+	return minCruiseSpeed + v.rnd.Float64()*(maxCruiseSpeed-minCruiseSpeed)
+}
+
+func (v *vehicle) between(min, max time.Duration) time.Duration {
+	return min + time.Duration(v.rnd.Int63n(int64(max-min)))
+}
+
+func reverseStops(stops []models.BusStop) {
+	for i, j := 0, len(stops)-1; i < j; i, j = i+1, j-1 {
+		stops[i], stops[j] = stops[j], stops[i]
+	}
+}
+
+// distance is the great-circle distance in meters
+func distance(lat1, long1, lat2, long2 float64) float64 {
+	phi1, phi2 := lat1*math.Pi/180, lat2*math.Pi/180
+	dPhi := (lat2 - lat1) * math.Pi / 180
+	dLambda := (long2 - long1) * math.Pi / 180
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+// bearing is the initial course from the first point to the second in
+// degrees clockwise from north, in [0, 360)
+func bearing(lat1, long1, lat2, long2 float64) float64 {
+	phi1, phi2 := lat1*math.Pi/180, lat2*math.Pi/180
+	dLambda := (long2 - long1) * math.Pi / 180
+	y := math.Sin(dLambda) * math.Cos(phi2)
+	x := math.Cos(phi1)*math.Sin(phi2) - math.Sin(phi1)*math.Cos(phi2)*math.Cos(dLambda)
+	return math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"backend/pkg/models"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// testStops lie on one meridian, about 1112 m apart, so the bus heads
+// north along them
+func testStops() []models.BusStop {
+	return []models.BusStop{
+		{ID: "A", Lat: 55.00, Long: 37.0},
+		{ID: "B", Lat: 55.01, Long: 37.0},
+		{ID: "C", Lat: 55.02, Long: 37.0},
+	}
+}
+
+func newTestVehicle(leg int, travelled float64, dwellUntil time.Time) *vehicle {
+	return &vehicle{
+		busId:      "bus-1",
+		stops:      testStops(),
+		leg:        leg,
+		travelled:  travelled,
+		speed:      36, // 10 m/s
+		dwellUntil: dwellUntil,
+		rnd:        rand.New(rand.NewSource(1)),
+	}
+}
+
+func TestVehicle_Advance(t *testing.T) {
+	now := time.Date(2025, 5, 12, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		leg           int
+		travelled     float64
+		dwellUntil    time.Time
+		wantLeg       int
+		wantTravelled float64
+		wantFirstStop string
+		// zero when the bus should not start waiting
+		wantMinDwell time.Duration
+		wantMaxDwell time.Duration
+	}{
+		{
+			name:          "Moves along the leg",
+			leg:           0,
+			travelled:     100,
+			wantLeg:       0,
+			wantTravelled: 200,
+			wantFirstStop: "A",
+		},
+		{
+			name:          "Waits at the stop",
+			leg:           1,
+			travelled:     100,
+			dwellUntil:    now.Add(time.Second),
+			wantLeg:       1,
+			wantTravelled: 100,
+			wantFirstStop: "A",
+		},
+		{
+			name:          "Moves on after the dwell",
+			leg:           1,
+			travelled:     100,
+			dwellUntil:    now,
+			wantLeg:       1,
+			wantTravelled: 200,
+			wantFirstStop: "A",
+		},
+		{
+			name:          "Reaches the next stop",
+			leg:           0,
+			travelled:     1100,
+			wantLeg:       1,
+			wantTravelled: 0,
+			wantFirstStop: "A",
+			wantMinDwell:  minDwell,
+			wantMaxDwell:  maxDwell,
+		},
+		{
+			name:          "Turns around at the terminal",
+			leg:           1,
+			travelled:     1100,
+			wantLeg:       0,
+			wantTravelled: 0,
+			wantFirstStop: "C",
+			wantMinDwell:  minLayover,
+			wantMaxDwell:  maxLayover,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newTestVehicle(tt.leg, tt.travelled, tt.dwellUntil)
+			v.advance(now, 10*time.Second)
+
+			if v.leg != tt.wantLeg {
+				t.Errorf("Expected leg %d, got %d", tt.wantLeg, v.leg)
+			}
+			if math.Abs(v.travelled-tt.wantTravelled) > 1e-9 {
+				t.Errorf("Expected %.1f m travelled, got %.1f", tt.wantTravelled, v.travelled)
+			}
+			if v.stops[0].ID != tt.wantFirstStop {
+				t.Errorf("Expected the route to start at %s, got %s", tt.wantFirstStop, v.stops[0].ID)
+			}
+			if tt.wantMaxDwell == 0 {
+				if v.speed != 36 {
+					t.Errorf("Expected the speed to stay at 36, got %.1f", v.speed)
+				}
+				if !v.dwellUntil.Equal(tt.dwellUntil) {
+					t.Errorf("Expected the dwell to stay at %v, got %v", tt.dwellUntil, v.dwellUntil)
+				}
+				return
+			}
+			if v.speed < minCruiseSpeed || v.speed > maxCruiseSpeed {
+				t.Errorf("Expected a new cruise speed, got %.1f", v.speed)
+			}
+			dwell := v.dwellUntil.Sub(now)
+			if dwell < tt.wantMinDwell || dwell >= tt.wantMaxDwell {
+				t.Errorf("Expected a dwell in [%v, %v), got %v", tt.wantMinDwell, tt.wantMaxDwell, dwell)
+			}
+		})
+	}
+}
+
+func TestVehicle_Position(t *testing.T) {
+	now := time.Date(2025, 5, 12, 8, 0, 0, 0, time.UTC)
+	legLength := distance(55.00, 37.0, 55.01, 37.0)
+	tests := []struct {
+		name        string
+		reversed    bool
+		leg         int
+		travelled   float64
+		dwelling    bool
+		wantLat     float64
+		wantHeading float64
+	}{
+		{
+			name:        "At the start of the leg",
+			leg:         0,
+			travelled:   0,
+			wantLat:     55.00,
+			wantHeading: 0,
+		},
+		{
+			name:        "Halfway along the second leg",
+			leg:         1,
+			travelled:   legLength / 2,
+			wantLat:     55.015,
+			wantHeading: 0,
+		},
+		{
+			name:        "Overshoot stays at the stop",
+			leg:         0,
+			travelled:   legLength * 2,
+			wantLat:     55.01,
+			wantHeading: 0,
+		},
+		{
+			name:        "Heading back after the terminal",
+			reversed:    true,
+			leg:         0,
+			travelled:   legLength / 2,
+			wantLat:     55.015,
+			wantHeading: 180,
+		},
+		{
+			name:        "Standing at a stop",
+			leg:         1,
+			travelled:   0,
+			dwelling:    true,
+			wantLat:     55.01,
+			wantHeading: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dwellUntil time.Time
+			if tt.dwelling {
+				dwellUntil = now.Add(time.Minute)
+			}
+			v := newTestVehicle(tt.leg, tt.travelled, dwellUntil)
+			if tt.reversed {
+				reverseStops(v.stops)
+			}
+			position := v.position(now)
+
+			if position.BusID != "bus-1" || !position.RecordedAt.Equal(now) {
+				t.Errorf("Expected bus-1 at %v, got %s at %v", now, position.BusID, position.RecordedAt)
+			}
+			if math.Abs(position.Lat-tt.wantLat) > 1e-6 || math.Abs(position.Long-37.0) > 1e-6 {
+				t.Errorf("Expected position (%.4f, 37.0), got (%.4f, %.4f)", tt.wantLat, position.Lat, position.Long)
+			}
+			if position.Heading != tt.wantHeading {
+				t.Errorf("Expected heading %.0f, got %.0f", tt.wantHeading, position.Heading)
+			}
+			if tt.dwelling && position.Speed != 0 {
+				t.Errorf("Expected a standing bus, got speed %.1f", position.Speed)
+			}
+			if !tt.dwelling && position.Speed <= 0 {
+				t.Errorf("Expected a moving bus, got speed %.1f", position.Speed)
+			}
+		})
+	}
+}
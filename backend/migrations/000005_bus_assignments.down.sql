@@ -0,0 +1,2 @@
+ALTER TABLE "routes_buses" DROP COLUMN "ends_at";
+ALTER TABLE "routes_buses" DROP COLUMN "starts_at";
@@ -0,0 +1,2 @@
+ALTER TABLE "routes_buses" ADD COLUMN "starts_at" TIMESTAMP NOT NULL DEFAULT now();
+ALTER TABLE "routes_buses" ADD COLUMN "ends_at" TIMESTAMP;
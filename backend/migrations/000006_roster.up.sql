@@ -0,0 +1,10 @@
+CREATE TABLE "duties" (
+                          "id"	TEXT UNIQUE,
+                          "date"	DATE NOT NULL,
+                          "driver_id"	TEXT NOT NULL,
+                          "bus_id"	TEXT NOT NULL,
+                          "route_id"	TEXT NOT NULL,
+                          "shift_start"	TIMESTAMP NOT NULL,
+                          "shift_end"	TIMESTAMP NOT NULL,
+                          PRIMARY KEY("id")
+);
@@ -0,0 +1,2 @@
+ALTER TABLE "duties" DROP COLUMN "compliance_override";
+ALTER TABLE "duties" DROP COLUMN "break_minutes";
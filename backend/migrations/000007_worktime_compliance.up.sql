@@ -0,0 +1,2 @@
+ALTER TABLE "duties" ADD COLUMN "break_minutes" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "duties" ADD COLUMN "compliance_override" BOOLEAN NOT NULL DEFAULT false;
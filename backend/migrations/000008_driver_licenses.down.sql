@@ -0,0 +1,3 @@
+DROP TABLE driver_license_categories;
+DROP TABLE driver_licenses;
+ALTER TABLE "buses" DROP COLUMN "vehicle_class";
@@ -0,0 +1,17 @@
+ALTER TABLE "buses" ADD COLUMN "vehicle_class" TEXT NOT NULL DEFAULT 'D';
+
+CREATE TABLE "driver_licenses" (
+                                   "driver_id"	TEXT UNIQUE,
+                                   "issue_date"	TIMESTAMP NOT NULL,
+                                   "expiry_date"	TIMESTAMP NOT NULL,
+                                   PRIMARY KEY("driver_id")
+);
+
+CREATE TABLE "driver_license_categories" (
+                                             "driver_id"	TEXT NOT NULL,
+                                             "category"	TEXT NOT NULL,
+                                             "issue_date"	TIMESTAMP NOT NULL,
+                                             "expiry_date"	TIMESTAMP NOT NULL,
+                                             "restrictions"	TEXT NOT NULL DEFAULT '',
+                                             PRIMARY KEY("driver_id", "category")
+);
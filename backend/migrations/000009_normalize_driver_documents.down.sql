@@ -0,0 +1,2 @@
+-- the original spelling of the documents is not kept, nothing to revert
+SELECT 1;
@@ -0,0 +1,11 @@
+UPDATE "drivers"
+SET "passport_series" = regexp_replace(regexp_replace("passport_series", '[\s-]', '', 'g'), '^(\d{4})(\d{6})$', '\1 \2')
+WHERE regexp_replace("passport_series", '[\s-]', '', 'g') ~ '^\d{10}$';
+
+UPDATE "drivers"
+SET "snils" = regexp_replace(regexp_replace("snils", '[\s-]', '', 'g'), '^(\d{3})(\d{3})(\d{3})(\d{2})$', '\1-\2-\3 \4')
+WHERE regexp_replace("snils", '[\s-]', '', 'g') ~ '^\d{11}$';
+
+UPDATE "drivers"
+SET "license_series" = regexp_replace(translate(upper(regexp_replace("license_series", '[\s-]', '', 'g')), 'ABEKMHOPCTYX', 'АВЕКМНОРСТУХ'), '^(\d{2})(\d{2}|[АВЕКМНОРСТУХ]{2})(\d{6})$', '\1 \2 \3')
+WHERE translate(upper(regexp_replace("license_series", '[\s-]', '', 'g')), 'ABEKMHOPCTYX', 'АВЕКМНОРСТУХ') ~ '^\d{2}(\d{2}|[АВЕКМНОРСТУХ]{2})\d{6}$';
@@ -0,0 +1,2 @@
+ALTER TABLE "duties" DROP COLUMN "started_at";
+DROP TABLE "medical_checks";
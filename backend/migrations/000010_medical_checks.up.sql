@@ -0,0 +1,13 @@
+CREATE TABLE "medical_checks" (
+                                  "id"	TEXT UNIQUE,
+                                  "driver_id"	TEXT NOT NULL,
+                                  "checked_at"	TIMESTAMP NOT NULL,
+                                  "passed"	BOOLEAN NOT NULL,
+                                  "systolic"	INTEGER NOT NULL,
+                                  "diastolic"	INTEGER NOT NULL,
+                                  "alcohol"	DOUBLE PRECISION NOT NULL DEFAULT 0,
+                                  "examiner"	TEXT NOT NULL,
+                                  PRIMARY KEY("id")
+);
+
+ALTER TABLE "duties" ADD COLUMN "started_at" TIMESTAMP;
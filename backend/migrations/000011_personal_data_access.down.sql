@@ -0,0 +1,2 @@
+DROP TABLE "personal_data_access_log";
+ALTER TABLE "users" DROP COLUMN "role";
@@ -0,0 +1,10 @@
+ALTER TABLE "users" ADD COLUMN "role" TEXT NOT NULL DEFAULT 'viewer';
+
+CREATE TABLE "personal_data_access_log" (
+                                            "id"	TEXT UNIQUE,
+                                            "user_id"	TEXT NOT NULL,
+                                            "driver_id"	TEXT NOT NULL,
+                                            "action"	TEXT NOT NULL,
+                                            "accessed_at"	TIMESTAMP NOT NULL,
+                                            PRIMARY KEY("id")
+);
@@ -0,0 +1,9 @@
+CREATE TABLE "driver_absences" (
+                                   "id"	TEXT UNIQUE,
+                                   "driver_id"	TEXT NOT NULL,
+                                   "kind"	TEXT NOT NULL,
+                                   "starts_on"	DATE NOT NULL,
+                                   "ends_on"	DATE NOT NULL,
+                                   "note"	TEXT NOT NULL DEFAULT '',
+                                   PRIMARY KEY("id")
+);
@@ -0,0 +1,8 @@
+CREATE TABLE "driver_qualifications" (
+                                         "driver_id"	TEXT NOT NULL,
+                                         "brand"	TEXT NOT NULL,
+                                         "bus_model"	TEXT NOT NULL,
+                                         "trained_on"	DATE NOT NULL,
+                                         "trainer"	TEXT NOT NULL,
+                                         PRIMARY KEY("driver_id", "brand", "bus_model")
+);
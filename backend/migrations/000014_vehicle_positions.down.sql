@@ -0,0 +1,2 @@
+DROP TABLE "bus_position_history";
+DROP TABLE "bus_positions";
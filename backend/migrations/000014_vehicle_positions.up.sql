@@ -0,0 +1,20 @@
+CREATE TABLE "bus_positions" (
+                                 "bus_id"	TEXT NOT NULL,
+                                 "recorded_at"	TIMESTAMP NOT NULL,
+                                 "lat"	DOUBLE PRECISION NOT NULL,
+                                 "long"	DOUBLE PRECISION NOT NULL,
+                                 "speed"	DOUBLE PRECISION NOT NULL,
+                                 "heading"	DOUBLE PRECISION NOT NULL,
+                                 PRIMARY KEY("bus_id")
+);
+
+CREATE TABLE "bus_position_history" (
+                                        "id"	TEXT UNIQUE,
+                                        "bus_id"	TEXT NOT NULL,
+                                        "recorded_at"	TIMESTAMP NOT NULL,
+                                        "lat"	DOUBLE PRECISION NOT NULL,
+                                        "long"	DOUBLE PRECISION NOT NULL,
+                                        "speed"	DOUBLE PRECISION NOT NULL,
+                                        "heading"	DOUBLE PRECISION NOT NULL,
+                                        PRIMARY KEY("id")
+);
@@ -0,0 +1 @@
+ALTER TABLE "routes_bus_stops" DROP COLUMN "stop_sequence";
@@ -0,0 +1,10 @@
+ALTER TABLE "routes_bus_stops" ADD COLUMN "stop_sequence" INTEGER NOT NULL DEFAULT 0;
+
+-- existing stops keep the order they were assigned in
+UPDATE "routes_bus_stops" rs
+SET "stop_sequence" = s.seq
+FROM (
+    SELECT ctid, row_number() OVER (PARTITION BY "route_id" ORDER BY ctid) AS seq
+    FROM "routes_bus_stops"
+) s
+WHERE rs.ctid = s.ctid;
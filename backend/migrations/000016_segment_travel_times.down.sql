@@ -0,0 +1,2 @@
+DROP TABLE "bus_last_stops";
+DROP TABLE "segment_travel_times";
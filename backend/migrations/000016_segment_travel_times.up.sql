@@ -0,0 +1,17 @@
+CREATE TABLE "segment_travel_times" (
+                                        "route_id"	TEXT NOT NULL,
+                                        "from_stop_id"	TEXT NOT NULL,
+                                        "to_stop_id"	TEXT NOT NULL,
+                                        "hour"	INTEGER NOT NULL,
+                                        "avg_seconds"	DOUBLE PRECISION NOT NULL,
+                                        "samples"	INTEGER NOT NULL,
+                                        PRIMARY KEY("route_id", "from_stop_id", "to_stop_id", "hour")
+);
+
+CREATE TABLE "bus_last_stops" (
+                                  "bus_id"	TEXT NOT NULL,
+                                  "route_id"	TEXT NOT NULL,
+                                  "stop_id"	TEXT NOT NULL,
+                                  "passed_at"	TIMESTAMP NOT NULL,
+                                  PRIMARY KEY("bus_id")
+);
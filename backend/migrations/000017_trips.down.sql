@@ -0,0 +1,2 @@
+DROP TABLE "trip_stop_times";
+DROP TABLE "trips";
@@ -0,0 +1,19 @@
+CREATE TABLE "trips" (
+                         "id"	TEXT UNIQUE,
+                         "duty_id"	TEXT NOT NULL,
+                         "route_id"	TEXT NOT NULL,
+                         "starts_at"	TIMESTAMP NOT NULL,
+                         "ends_at"	TIMESTAMP NOT NULL,
+                         PRIMARY KEY("id")
+);
+
+CREATE TABLE "trip_stop_times" (
+                                   "trip_id"	TEXT NOT NULL,
+                                   "stop_sequence"	INTEGER NOT NULL,
+                                   "stop_id"	TEXT NOT NULL,
+                                   "arrival"	TIMESTAMP NOT NULL,
+                                   "departure"	TIMESTAMP NOT NULL,
+                                   "actual_arrival"	TIMESTAMP,
+                                   "actual_departure"	TIMESTAMP,
+                                   PRIMARY KEY("trip_id", "stop_sequence")
+);
@@ -0,0 +1,18 @@
+CREATE TABLE "incidents" (
+                             "id"	TEXT UNIQUE,
+                             "type"	TEXT NOT NULL,
+                             "severity"	TEXT NOT NULL,
+                             "status"	TEXT NOT NULL,
+                             "occurred_at"	TIMESTAMP NOT NULL,
+                             "location"	TEXT NOT NULL,
+                             "lat"	DOUBLE PRECISION,
+                             "long"	DOUBLE PRECISION,
+                             "bus_id"	TEXT NOT NULL,
+                             "driver_id"	TEXT NOT NULL,
+                             "route_id"	TEXT NOT NULL,
+                             "description"	TEXT NOT NULL,
+                             "reported_by"	TEXT NOT NULL,
+                             "resolution"	TEXT NOT NULL,
+                             "closed_at"	TIMESTAMP,
+                             PRIMARY KEY("id")
+);
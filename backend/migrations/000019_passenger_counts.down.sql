@@ -0,0 +1 @@
+DROP TABLE "passenger_counts";
@@ -0,0 +1,11 @@
+CREATE TABLE "passenger_counts" (
+                                    "bus_id"	TEXT NOT NULL,
+                                    "counted_at"	TIMESTAMP NOT NULL,
+                                    "stop_id"	TEXT NOT NULL,
+                                    "trip_id"	TEXT NOT NULL,
+                                    "route_id"	TEXT NOT NULL,
+                                    "bucket_minutes"	INTEGER NOT NULL,
+                                    "boardings"	INTEGER NOT NULL,
+                                    "alightings"	INTEGER NOT NULL,
+                                    PRIMARY KEY("bus_id", "counted_at", "stop_id", "trip_id")
+);
@@ -0,0 +1,3 @@
+DROP TABLE "service_alert_stops";
+DROP TABLE "service_alert_routes";
+DROP TABLE "service_alerts";
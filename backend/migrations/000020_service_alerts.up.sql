@@ -0,0 +1,22 @@
+CREATE TABLE "service_alerts" (
+                                  "id"	TEXT UNIQUE,
+                                  "title"	TEXT NOT NULL,
+                                  "description"	TEXT NOT NULL,
+                                  "cause"	TEXT NOT NULL,
+                                  "effect"	TEXT NOT NULL,
+                                  "severity"	TEXT NOT NULL,
+                                  "starts_at"	TIMESTAMP NOT NULL,
+                                  "ends_at"	TIMESTAMP,
+                                  "published_by"	TEXT NOT NULL,
+                                  PRIMARY KEY("id")
+);
+
+CREATE TABLE "service_alert_routes" (
+                                        "alert_id"	TEXT NOT NULL,
+                                        "route_id"	TEXT NOT NULL
+);
+
+CREATE TABLE "service_alert_stops" (
+                                       "alert_id"	TEXT NOT NULL,
+                                       "stop_id"	TEXT NOT NULL
+);
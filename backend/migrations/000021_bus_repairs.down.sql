@@ -0,0 +1 @@
+DROP TABLE "bus_repairs";
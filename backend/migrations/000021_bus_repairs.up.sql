@@ -0,0 +1,9 @@
+CREATE TABLE "bus_repairs" (
+                               "id"	TEXT UNIQUE,
+                               "bus_id"	TEXT NOT NULL,
+                               "reason"	TEXT NOT NULL,
+                               "incident_id"	TEXT NOT NULL DEFAULT '',
+                               "started_at"	TIMESTAMP NOT NULL,
+                               "finished_at"	TIMESTAMP,
+                               PRIMARY KEY("id")
+);
@@ -0,0 +1,7 @@
+ALTER TABLE "users" ADD COLUMN "role" TEXT NOT NULL DEFAULT 'viewer';
+
+UPDATE "users" SET "role" = r."role"
+FROM "user_roles" r
+WHERE r."user_id" = "users"."id" AND r."role" IN ('dispatcher', 'hr');
+
+DROP TABLE "user_roles";
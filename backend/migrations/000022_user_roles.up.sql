@@ -0,0 +1,10 @@
+CREATE TABLE "user_roles" (
+                              "user_id"	TEXT NOT NULL,
+                              "role"	TEXT NOT NULL,
+                              PRIMARY KEY("user_id", "role")
+);
+
+INSERT INTO "user_roles" ("user_id", "role")
+SELECT "id", "role" FROM "users";
+
+ALTER TABLE "users" DROP COLUMN "role";
@@ -0,0 +1,11 @@
+CREATE TABLE "user_sessions" (
+                                 "id"	TEXT UNIQUE,
+                                 "user_id"	TEXT NOT NULL,
+                                 "refresh_token_hash"	TEXT NOT NULL UNIQUE,
+                                 "created_at"	TIMESTAMP NOT NULL,
+                                 "expires_at"	TIMESTAMP NOT NULL,
+                                 "revoked_at"	TIMESTAMP,
+                                 PRIMARY KEY("id")
+);
+
+CREATE INDEX "user_sessions_user_id" ON "user_sessions" ("user_id");
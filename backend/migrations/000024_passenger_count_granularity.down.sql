@@ -0,0 +1 @@
+ALTER TABLE "passenger_counts" DROP COLUMN "granularity";
@@ -0,0 +1,9 @@
+ALTER TABLE "passenger_counts" ADD COLUMN "granularity" TEXT NOT NULL DEFAULT '';
+
+-- existing counts get the granularity the service derives for new ones
+UPDATE "passenger_counts"
+SET "granularity" = CASE
+    WHEN "stop_id" <> '' THEN 'stop'
+    WHEN "bucket_minutes" > 0 THEN 'bucket'
+    ELSE 'trip'
+END;
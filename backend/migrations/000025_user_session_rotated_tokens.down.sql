@@ -0,0 +1 @@
+DROP TABLE "user_session_rotated_tokens";
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- refresh tokens a session has already exchanged, to catch their reuse
+CREATE TABLE "user_session_rotated_tokens" (
+                                 "refresh_token_hash"	TEXT NOT NULL,
+                                 "session_id"	TEXT NOT NULL,
+                                 PRIMARY KEY("refresh_token_hash")
+);
+
+CREATE INDEX "user_session_rotated_tokens_session_id" ON "user_session_rotated_tokens" ("session_id");
@@ -0,0 +1 @@
+DROP INDEX "bus_position_history_bus_id_recorded_at";
\ No newline at end of file
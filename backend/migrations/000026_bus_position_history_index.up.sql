@@ -0,0 +1,2 @@
+-- history is read by bus and period, see GetHistory
+CREATE INDEX "bus_position_history_bus_id_recorded_at" ON "bus_position_history" ("bus_id", "recorded_at");
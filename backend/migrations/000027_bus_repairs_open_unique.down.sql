@@ -0,0 +1 @@
+DROP INDEX "bus_repairs_open_bus_id";
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- a bus is in at most one repair at a time, see ReplaceBus
+CREATE UNIQUE INDEX "bus_repairs_open_bus_id" ON "bus_repairs" ("bus_id") WHERE "finished_at" IS NULL;
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"backend/pkg"
+	"backend/pkg/models"
+	"backend/pkg/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+type AbsenceController struct {
+	as service.IAbsenceService
+	ps service.IPrivacyService
+}
+
+func NewAbsenceController(as service.IAbsenceService, ps service.IPrivacyService) *AbsenceController {
+	return &AbsenceController{as, ps}
+}
+
+// @Summary      Get driver absences
+// @Description  Get vacations, sick leaves and training days of a driver
+// @Tags         drivers
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Driver ID"
+// @Success      200  {array}  models.DriverAbsence
+// @Failure      400  {object}  string
+// @Router       /drivers/{id}/absences/ [get]
+func (ac AbsenceController) GetByDriverId(c *gin.Context) {
+	id := c.Param("id")
+	data, err := ac.as.GetByDriverId(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      Add driver absence
+// @Description  Add vacation (vacation), sick leave (sick_leave) or training (training) days of a driver. Both dates are inclusive
+// @Tags         drivers
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Driver ID"
+// @Param absence body models.DriverAbsence required "absence model"
+// @Success      200  {object}  models.DriverAbsence
+// @Failure      400  {object}  string
+// @Router       /drivers/{id}/absences/ [post]
+func (ac AbsenceController) Add(c *gin.Context) {
+	var absence models.DriverAbsence
+	if err := c.ShouldBindJSON(&absence); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	absence.DriverID = c.Param("id")
+	err := ac.as.Add(&absence)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, absence)
+}
+
+// @Summary      Delete driver absence
+// @Description  Delete driver absence by ID
+// @Tags         drivers
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        absenceId   path      string  true  "Absence ID"
+// @Success      200  {object}  string
+// @Failure      400  {object}  string
+// @Router       /drivers/absences/{absenceId}/ [delete]
+func (ac AbsenceController) DeleteById(c *gin.Context) {
+	id := c.Param("absenceId")
+	err := ac.as.DeleteById(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": id})
+}
+
+// @Summary      Get available drivers
+// @Description  Get drivers without an absence on the date. Identity documents are masked unless the caller has the hr role
+// @Tags         drivers
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        date   query      string  false  "Date (YYYY-MM-DD), today by default"
+// @Success      200  {array}  models.Driver
+// @Failure      400  {object}  string
+// @Router       /drivers/available/ [get]
+func (ac AbsenceController) GetAvailable(c *gin.Context) {
+	date := time.Now()
+	if value := c.Query("date"); value != "" {
+		parsed, err := parseTime(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		date = parsed
+	}
+	data, err := ac.as.GetAvailable(date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId, roles := pkg.GetUserIdentity(c)
+	err = ac.ps.ProtectDrivers(data, userId, roles, c.Request.Method+" "+c.FullPath())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
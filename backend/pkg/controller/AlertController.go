@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"backend/pkg"
+	"backend/pkg/models"
+	"backend/pkg/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+type AlertController struct {
+	als service.IAlertService
+}
+
+func NewAlertController(als service.IAlertService) *AlertController {
+	return &AlertController{als}
+}
+
+// @Summary      Get active service alerts
+// @Description  Get service alerts in effect now, most recently started first. Open for passenger channels
+// @Tags         alerts
+// @Produce      json
+// @Param        routeId   query      string  false  "Only alerts affecting the route"
+// @Param        stopId   query      string  false  "Only alerts affecting the bus stop"
+// @Success      200  {array}  models.ServiceAlert
+// @Failure      400  {object}  string
+// @Router       /alerts/ [get]
+func (ac AlertController) GetActive(c *gin.Context) {
+	data, err := ac.als.GetActive(c.Query("routeId"), c.Query("stopId"), time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if data == nil {
+		data = []models.ServiceAlert{}
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      Get service alert
+// @Description  Get service alert by ID
+// @Tags         alerts
+// @Produce      json
+// @Param        id   path      string  true  "Service alert ID"
+// @Success      200  {object}  models.ServiceAlert
+// @Failure      400  {object}  string
+// @Router       /alerts/{id}/ [get]
+func (ac AlertController) GetById(c *gin.Context) {
+	id := c.Param("id")
+	data, err := ac.als.GetById(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      Publish service alert
+// @Description  Publish an alert about a diversion, cancellation or delay on routes and stops. Dispatchers only
+// @Tags         alerts
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param alert body models.ServiceAlert required "service alert"
+// @Success      200  {object}  models.ServiceAlert
+// @Failure      400  {object}  string
+// @Router       /alerts/ [post]
+func (ac AlertController) Publish(c *gin.Context) {
+	var alert models.ServiceAlert
+	if err := c.ShouldBindJSON(&alert); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId, _ := pkg.GetUserIdentity(c)
+	alert.PublishedBy = userId
+	err := ac.als.Publish(&alert)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, alert)
+}
+
+// @Summary      End service alert
+// @Description  End a service alert now. Dispatchers only
+// @Tags         alerts
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Service alert ID"
+// @Success      200  {object}  models.ServiceAlert
+// @Failure      400  {object}  string
+// @Router       /alerts/{id}/end/ [post]
+func (ac AlertController) End(c *gin.Context) {
+	id := c.Param("id")
+	data, err := ac.als.End(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
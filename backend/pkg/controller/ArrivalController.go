@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"backend/pkg/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+type ArrivalController struct {
+	as service.IArrivalService
+}
+
+func NewArrivalController(as service.IArrivalService) *ArrivalController {
+	return &ArrivalController{as}
+}
+
+// @Summary      Get stop arrivals
+// @Description  Get the next buses of every route serving the stop with predicted minutes to arrival
+// @Tags         stops
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Bus stop ID"
+// @Success      200  {array}  models.RouteArrivals
+// @Failure      400  {object}  string
+// @Router       /stops/{id}/arrivals/ [get]
+func (ac ArrivalController) GetStopArrivals(c *gin.Context) {
+	id := c.Param("id")
+	data, err := ac.as.GetStopArrivals(id, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
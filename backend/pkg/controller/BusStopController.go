@@ -5,18 +5,20 @@ import (
 	"backend/pkg/service"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"time"
 )
 
 type BusStopController struct {
 	bss service.IBusStopService
+	als service.IAlertService
 }
 
-func NewBusStopController(bss service.IBusStopService) *BusStopController {
-	return &BusStopController{bss}
+func NewBusStopController(bss service.IBusStopService, als service.IAlertService) *BusStopController {
+	return &BusStopController{bss, als}
 }
 
 // @Summary      Get bus stop
-// @Description  Get bus stop by ID
+// @Description  Get bus stop by ID with its active service alerts
 // @Tags         stops
 // @Security ApiKeyAuth
 // @Produce      json
@@ -31,11 +33,17 @@ func (bsc BusStopController) GetById(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, data)
+	stops := []models.BusStop{*data}
+	err = bsc.als.AttachToStops(stops, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, stops[0])
 }
 
 // @Summary      Get bus stop
-// @Description  Get bus stop by name
+// @Description  Get bus stop by name with its active service alerts
 // @Tags         stops
 // @Security ApiKeyAuth
 // @Produce      json
@@ -50,11 +58,17 @@ func (bsc BusStopController) GetByName(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, data)
+	stops := []models.BusStop{*data}
+	err = bsc.als.AttachToStops(stops, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, stops[0])
 }
 
 // @Summary      Get bus stop list
-// @Description  Get bus stop list
+// @Description  Get bus stop list with active service alerts
 // @Tags         stops
 // @Security ApiKeyAuth
 // @Produce      json
@@ -67,6 +81,11 @@ func (bsc BusStopController) GetAll(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	err = bsc.als.AttachToStops(data, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	c.JSON(http.StatusOK, data)
 }
 
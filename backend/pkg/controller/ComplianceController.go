@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"backend/pkg/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type ComplianceController struct {
+	cs service.IComplianceService
+}
+
+func NewComplianceController(cs service.IComplianceService) *ComplianceController {
+	return &ComplianceController{cs}
+}
+
+// @Summary      Get driver compliance report
+// @Description  Get driving time per day and working time rule violations for a driver
+// @Tags         compliance
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Driver ID"
+// @Param        from   query      string  true  "Period start (YYYY-MM-DD)"
+// @Param        to   query      string  true  "Period end, exclusive (YYYY-MM-DD)"
+// @Success      200  {object}  models.ComplianceReport
+// @Failure      400  {object}  string
+// @Router       /compliance/drivers/{id}/ [get]
+func (cc ComplianceController) GetDriverReport(c *gin.Context) {
+	id := c.Param("id")
+	from, err := parseTime(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	to, err := parseTime(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	data, err := cc.cs.GetDriverReport(id, from, to)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
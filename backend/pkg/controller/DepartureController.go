@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"backend/pkg/service"
+	"embed"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+	"html/template"
+	"net/http"
+	"time"
+)
+
+// boardRefreshSeconds is how often the kiosk page reloads itself
+const boardRefreshSeconds = 30
+
+//go:embed templates/board.html
+var boardFiles embed.FS
+
+var boardTemplate = template.Must(template.New("board.html").Funcs(template.FuncMap{
+	"clock": func(value interface{}) string {
+		switch t := value.(type) {
+		case time.Time:
+			return t.Local().Format("15:04")
+		case *time.Time:
+			return t.Local().Format("15:04")
+		}
+		return ""
+	},
+}).ParseFS(boardFiles, "templates/board.html"))
+
+type DepartureController struct {
+	ds service.IDepartureService
+}
+
+func NewDepartureController(ds service.IDepartureService) *DepartureController {
+	return &DepartureController{ds}
+}
+
+// @Summary      Get stop departures
+// @Description  Get the next departures from the stop. Scheduled times are replaced by predictions when the bus is on the way
+// @Tags         stops
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Bus stop ID"
+// @Success      200  {object}  models.DepartureBoard
+// @Failure      400  {object}  string
+// @Router       /stops/{id}/departures/ [get]
+func (dc DepartureController) GetDepartures(c *gin.Context) {
+	id := c.Param("id")
+	data, err := dc.ds.GetBoard(id, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      Stop departure board page
+// @Description  Server-rendered departure board for displays at the stop. The page reloads itself every 30 seconds
+// @Tags         stops
+// @Produce      html
+// @Param        id   path      string  true  "Bus stop ID"
+// @Success      200  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Router       /board/stops/{id} [get]
+func (dc DepartureController) GetBoardPage(c *gin.Context) {
+	id := c.Param("id")
+	data, err := dc.ds.GetBoard(id, time.Now())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "Bus stop not found" {
+			status = http.StatusNotFound
+		}
+		c.String(status, err.Error())
+		return
+	}
+	c.Render(http.StatusOK, render.HTML{
+		Template: boardTemplate,
+		Name:     "board.html",
+		Data: gin.H{
+			"Board":          data,
+			"RefreshSeconds": boardRefreshSeconds,
+		},
+	})
+}
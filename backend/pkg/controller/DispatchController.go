@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type DispatchController struct {
+	ds service.IDispatchService
+}
+
+func NewDispatchController(ds service.IDispatchService) *DispatchController {
+	return &DispatchController{ds}
+}
+
+// @Summary      Propose replacement buses
+// @Description  Get the routes the bus serves with the spare buses that could replace it. Spare buses are not assigned anywhere, not in repair and of a vehicle class running on the route
+// @Tags         dispatch
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Bus ID"
+// @Success      200  {object}  models.ReplacementProposal
+// @Failure      400  {object}  string
+// @Router       /dispatch/buses/{id}/replacements/ [get]
+func (dc DispatchController) ProposeReplacements(c *gin.Context) {
+	id := c.Param("id")
+	data, err := dc.ds.ProposeReplacements(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      Report breakdown
+// @Description  Take a failing bus off the routes it serves now into repair and put the chosen spare buses on them for the rest of its periods in one step. Dispatchers only
+// @Tags         dispatch
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param breakdown body models.Breakdown required "breakdown"
+// @Success      200  {object}  models.BreakdownResult
+// @Failure      400  {object}  string
+// @Router       /dispatch/breakdowns/ [post]
+func (dc DispatchController) ReportBreakdown(c *gin.Context) {
+	var breakdown models.Breakdown
+	if err := c.ShouldBindJSON(&breakdown); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	data, err := dc.ds.ReportBreakdown(&breakdown)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      Finish repair
+// @Description  Finish the repair of a bus, making it a spare again. Dispatchers only
+// @Tags         dispatch
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Bus ID"
+// @Success      200  {object}  models.BusRepair
+// @Failure      400  {object}  string
+// @Router       /dispatch/buses/{id}/repair/finish/ [post]
+func (dc DispatchController) FinishRepair(c *gin.Context) {
+	id := c.Param("id")
+	data, err := dc.ds.FinishRepair(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
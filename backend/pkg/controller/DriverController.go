@@ -2,22 +2,25 @@ package controller
 
 import (
 	_ "backend/docs"
+	"backend/pkg"
 	"backend/pkg/models"
 	"backend/pkg/service"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"strconv"
 )
 
 type DriverController struct {
 	ds service.IDriverService
+	ps service.IPrivacyService
 }
 
-func NewDriverController(ds service.DriverService) *DriverController {
-	return &DriverController{ds}
+func NewDriverController(ds service.DriverService, ps service.IPrivacyService) *DriverController {
+	return &DriverController{ds, ps}
 }
 
 // @Summary      Get driver
-// @Description  Get driver by ID
+// @Description  Get driver by ID. Identity documents are masked unless the caller has the hr role
 // @Tags         drivers
 // @Security ApiKeyAuth
 // @Produce      json
@@ -32,11 +35,17 @@ func (dc DriverController) GetById(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
+	userId, roles := pkg.GetUserIdentity(c)
+	err = dc.ps.ProtectDriver(data, userId, roles, c.Request.Method+" "+c.FullPath())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 	c.JSON(http.StatusOK, data)
 }
 
 // @Summary      Get driver
-// @Description  Get driver by passport series
+// @Description  Get driver by passport series. Identity documents are masked unless the caller has the hr role
 // @Tags         drivers
 // @Security ApiKeyAuth
 // @Produce      json
@@ -51,11 +60,17 @@ func (dc DriverController) GetByPassportSeries(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
+	userId, roles := pkg.GetUserIdentity(c)
+	err = dc.ps.ProtectDriver(data, userId, roles, c.Request.Method+" "+c.FullPath())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 	c.JSON(http.StatusOK, data)
 }
 
 // @Summary      Get driver list
-// @Description  Get driver list
+// @Description  Get driver list. Identity documents are masked unless the caller has the hr role
 // @Tags         drivers
 // @Security ApiKeyAuth
 // @Produce      json
@@ -64,6 +79,12 @@ func (dc DriverController) GetByPassportSeries(c *gin.Context) {
 // @Router       /drivers/ [get]
 func (dc DriverController) GetAll(c *gin.Context) {
 	data := dc.ds.GetAll()
+	userId, roles := pkg.GetUserIdentity(c)
+	err := dc.ps.ProtectDrivers(data, userId, roles, c.Request.Method+" "+c.FullPath())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 	c.JSON(http.StatusOK, data)
 }
 
@@ -133,3 +154,41 @@ func (dc DriverController) UpdateById(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, driver)
 }
+
+// @Summary      Search drivers
+// @Description  Search drivers by name, surname and patronymic. Tolerates typos, ignores case and treats ё as е. Identity documents are masked unless the caller has the hr role
+// @Tags         drivers
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        q   query      string  true  "Search query"
+// @Param        page   query      int  false  "Page number, starting from 1"
+// @Param        pageSize   query      int  false  "Page size, 20 by default, 100 at most"
+// @Success      200  {object}  models.DriverSearchPage
+// @Failure      400  {object}  string
+// @Router       /drivers/search/ [get]
+func (dc DriverController) Search(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	data, err := dc.ds.Search(c.Query("q"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId, roles := pkg.GetUserIdentity(c)
+	for i := range data.Items {
+		err = dc.ps.ProtectDriver(&data.Items[i].Driver, userId, roles, c.Request.Method+" "+c.FullPath())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, data)
+}
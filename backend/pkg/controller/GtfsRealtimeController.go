@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"backend/pkg/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+const protobufContentType = "application/x-protobuf"
+
+type GtfsRealtimeController struct {
+	gs service.IGtfsRealtimeService
+}
+
+func NewGtfsRealtimeController(gs service.IGtfsRealtimeService) *GtfsRealtimeController {
+	return &GtfsRealtimeController{gs}
+}
+
+// @Summary      GTFS-Realtime vehicle positions
+// @Description  Latest bus positions as a GTFS-Realtime feed. Use format=json for a readable debug variant
+// @Tags         gtfs-rt
+// @Produce      application/x-protobuf
+// @Param        format   query      string  false  "json for the debug variant"
+// @Success      200  {array}  models.GtfsVehicle
+// @Failure      500  {object}  string
+// @Router       /gtfs-rt/vehicle-positions/ [get]
+func (gc GtfsRealtimeController) GetVehiclePositions(c *gin.Context) {
+	feed, err := gc.gs.GetFeed(time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if c.Query("format") == "json" {
+		c.JSON(http.StatusOK, gin.H{"Timestamp": feed.Timestamp, "Vehicles": feed.Vehicles})
+		return
+	}
+	c.Data(http.StatusOK, protobufContentType, service.EncodeVehiclePositions(feed))
+}
+
+// @Summary      GTFS-Realtime trip updates
+// @Description  Predicted stop arrivals as a GTFS-Realtime feed. Use format=json for a readable debug variant
+// @Tags         gtfs-rt
+// @Produce      application/x-protobuf
+// @Param        format   query      string  false  "json for the debug variant"
+// @Success      200  {array}  models.GtfsTripUpdate
+// @Failure      500  {object}  string
+// @Router       /gtfs-rt/trip-updates/ [get]
+func (gc GtfsRealtimeController) GetTripUpdates(c *gin.Context) {
+	feed, err := gc.gs.GetFeed(time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if c.Query("format") == "json" {
+		c.JSON(http.StatusOK, gin.H{"Timestamp": feed.Timestamp, "TripUpdates": feed.TripUpdates})
+		return
+	}
+	c.Data(http.StatusOK, protobufContentType, service.EncodeTripUpdates(feed))
+}
@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"backend/pkg"
+	"backend/pkg/models"
+	"backend/pkg/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type IncidentController struct {
+	is service.IIncidentService
+}
+
+func NewIncidentController(is service.IIncidentService) *IncidentController {
+	return &IncidentController{is}
+}
+
+// @Summary      Get incident
+// @Description  Get incident by ID
+// @Tags         incidents
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Incident ID"
+// @Success      200  {object}  models.Incident
+// @Failure      400  {object}  string
+// @Router       /incidents/{id}/ [get]
+func (ic IncidentController) GetById(c *gin.Context) {
+	id := c.Param("id")
+	data, err := ic.is.GetById(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      Report incident
+// @Description  Report a breakdown, accident, passenger complaint or delay. New incidents are open
+// @Tags         incidents
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param incident body models.Incident required "incident"
+// @Success      200  {object}  models.Incident
+// @Failure      400  {object}  string
+// @Router       /incidents/ [post]
+func (ic IncidentController) Add(c *gin.Context) {
+	var incident models.Incident
+	if err := c.ShouldBindJSON(&incident); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	incident.ReportedBy, _ = pkg.GetUserIdentity(c)
+	err := ic.is.Add(&incident)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, incident)
+}
+
+// @Summary      Change incident status
+// @Description  Move an incident to investigating or closed. Closing needs a resolution
+// @Tags         incidents
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Incident ID"
+// @Param change body models.IncidentStatusChange required "status change"
+// @Success      200  {object}  models.Incident
+// @Failure      400  {object}  string
+// @Router       /incidents/{id}/status/ [put]
+func (ic IncidentController) ChangeStatus(c *gin.Context) {
+	id := c.Param("id")
+	var change models.IncidentStatusChange
+	if err := c.ShouldBindJSON(&change); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	data, err := ic.is.ChangeStatus(id, change)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      Find incidents
+// @Description  Get incidents, newest first, filtered by period and linked bus, driver or route
+// @Tags         incidents
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        from   query      string  false  "Period start (YYYY-MM-DD)"
+// @Param        to   query      string  false  "Period end, exclusive (YYYY-MM-DD)"
+// @Param        busId   query      string  false  "Bus ID"
+// @Param        driverId   query      string  false  "Driver ID"
+// @Param        routeId   query      string  false  "Route ID"
+// @Param        type   query      string  false  "breakdown, accident, complaint or delay"
+// @Param        status   query      string  false  "open, investigating or closed"
+// @Success      200  {array}  models.Incident
+// @Failure      400  {object}  string
+// @Router       /incidents/ [get]
+func (ic IncidentController) Find(c *gin.Context) {
+	filter := models.IncidentFilter{
+		BusID:    c.Query("busId"),
+		DriverID: c.Query("driverId"),
+		RouteID:  c.Query("routeId"),
+		Type:     c.Query("type"),
+		Status:   c.Query("status"),
+	}
+	if value := c.Query("from"); value != "" {
+		from, err := parseTime(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filter.From = from
+	}
+	if value := c.Query("to"); value != "" {
+		to, err := parseTime(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filter.To = to
+	}
+	data, err := ic.is.Find(filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
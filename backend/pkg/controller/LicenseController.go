@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type LicenseController struct {
+	ls service.ILicenseService
+}
+
+func NewLicenseController(ls service.ILicenseService) *LicenseController {
+	return &LicenseController{ls}
+}
+
+// @Summary      Get driver license
+// @Description  Get driver license with categories by driver ID
+// @Tags         drivers
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Driver ID"
+// @Success      200  {object}  models.DriverLicense
+// @Failure      404  {object}  string
+// @Router       /drivers/{id}/license/ [get]
+func (lc LicenseController) GetByDriverId(c *gin.Context) {
+	id := c.Param("id")
+	data, err := lc.ls.GetByDriverId(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      Save driver license
+// @Description  Create or replace driver license with categories
+// @Tags         drivers
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Driver ID"
+// @Param license body models.DriverLicense required "license model"
+// @Success      200  {object}  models.DriverLicense
+// @Failure      400  {object}  string
+// @Router       /drivers/{id}/license/ [put]
+func (lc LicenseController) Save(c *gin.Context) {
+	var license models.DriverLicense
+	if err := c.ShouldBindJSON(&license); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	license.DriverID = c.Param("id")
+	err := lc.ls.Save(&license)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, license)
+}
+
+// @Summary      Get expiring licenses
+// @Description  Get driver licenses and categories expiring within the given number of days
+// @Tags         drivers
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        days   query      int  false  "Days ahead, 30 by default"
+// @Success      200  {array}  models.ExpiringLicense
+// @Failure      400  {object}  string
+// @Router       /drivers/licenses/expiring/ [get]
+func (lc LicenseController) GetExpiring(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	data, err := lc.ls.GetExpiring(days)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+type MedicalCheckController struct {
+	ms service.IMedicalCheckService
+}
+
+func NewMedicalCheckController(ms service.IMedicalCheckService) *MedicalCheckController {
+	return &MedicalCheckController{ms}
+}
+
+// @Summary      Get medical check
+// @Description  Get pre-trip medical check by ID
+// @Tags         medical
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Medical check ID"
+// @Success      200  {object}  models.MedicalCheck
+// @Failure      400  {object}  string
+// @Router       /medical-checks/{id}/ [get]
+func (mc MedicalCheckController) GetById(c *gin.Context) {
+	id := c.Param("id")
+	data, err := mc.ms.GetById(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      Add medical check
+// @Description  Record a pre-trip medical check of a driver
+// @Tags         medical
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param check body models.MedicalCheck required "medical check model"
+// @Success      200  {object}  models.MedicalCheck
+// @Failure      400  {object}  string
+// @Router       /medical-checks/ [post]
+func (mc MedicalCheckController) Add(c *gin.Context) {
+	var check models.MedicalCheck
+	if err := c.ShouldBindJSON(&check); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	err := mc.ms.Add(&check)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, check)
+}
+
+// @Summary      Get driver medical checks
+// @Description  Get medical checks of a driver for a period
+// @Tags         medical
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Driver ID"
+// @Param        from   query      string  true  "Period start (YYYY-MM-DD)"
+// @Param        to   query      string  true  "Period end, exclusive (YYYY-MM-DD)"
+// @Success      200  {array}  models.MedicalCheck
+// @Failure      400  {object}  string
+// @Router       /medical-checks/drivers/{id}/ [get]
+func (mc MedicalCheckController) GetByDriverId(c *gin.Context) {
+	id := c.Param("id")
+	from, err := parseTime(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	to, err := parseTime(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	data, err := mc.ms.GetByDriverId(id, from, to)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      Get drivers awaiting a medical check
+// @Description  Get drivers rostered for the day who have no passed medical check yet
+// @Tags         medical
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        date   query      string  false  "Date (YYYY-MM-DD), today by default"
+// @Success      200  {array}  models.PendingMedicalCheck
+// @Failure      400  {object}  string
+// @Router       /medical-checks/pending/ [get]
+func (mc MedicalCheckController) GetPending(c *gin.Context) {
+	date := time.Now()
+	if value := c.Query("date"); value != "" {
+		parsed, err := parseTime(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		date = parsed
+	}
+	data, err := mc.ms.GetPending(date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
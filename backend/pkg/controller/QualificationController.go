@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type QualificationController struct {
+	qs service.IQualificationService
+}
+
+func NewQualificationController(qs service.IQualificationService) *QualificationController {
+	return &QualificationController{qs}
+}
+
+// @Summary      Get driver qualifications
+// @Description  Get bus models the driver is trained on
+// @Tags         drivers
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Driver ID"
+// @Success      200  {array}  models.DriverQualification
+// @Failure      400  {object}  string
+// @Router       /drivers/{id}/qualifications/ [get]
+func (qc QualificationController) GetByDriverId(c *gin.Context) {
+	id := c.Param("id")
+	data, err := qc.qs.GetByDriverId(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      Save driver qualification
+// @Description  Add or update the training of a driver on a bus model
+// @Tags         drivers
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Driver ID"
+// @Param qualification body models.DriverQualification required "qualification model"
+// @Success      200  {object}  models.DriverQualification
+// @Failure      400  {object}  string
+// @Router       /drivers/{id}/qualifications/ [put]
+func (qc QualificationController) Save(c *gin.Context) {
+	var qualification models.DriverQualification
+	if err := c.ShouldBindJSON(&qualification); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	qualification.DriverID = c.Param("id")
+	err := qc.qs.Save(&qualification)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, qualification)
+}
+
+// @Summary      Delete driver qualification
+// @Description  Delete the training of a driver on a bus model
+// @Tags         drivers
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Driver ID"
+// @Param        brand   query      string  true  "Bus brand"
+// @Param        model   query      string  true  "Bus model"
+// @Success      200  {object}  string
+// @Failure      400  {object}  string
+// @Router       /drivers/{id}/qualifications/ [delete]
+func (qc QualificationController) Delete(c *gin.Context) {
+	id := c.Param("id")
+	err := qc.qs.Delete(id, c.Query("brand"), c.Query("model"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": id})
+}
+
+// @Summary      Get qualification matrix
+// @Description  Get all drivers against all bus models with the qualified flag for each pair
+// @Tags         drivers
+// @Security ApiKeyAuth
+// @Produce      json
+// @Success      200  {object}  models.QualificationMatrix
+// @Failure      400  {object}  string
+// @Router       /drivers/qualifications/matrix/ [get]
+func (qc QualificationController) GetMatrix(c *gin.Context) {
+	data, err := qc.qs.GetMatrix()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
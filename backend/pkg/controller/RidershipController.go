@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type RidershipController struct {
+	rs service.IRidershipService
+}
+
+func NewRidershipController(rs service.IRidershipService) *RidershipController {
+	return &RidershipController{rs}
+}
+
+// @Summary      Push passenger counts
+// @Description  Push a batch of boarding and alighting counts from automatic counters. Invalid items are reported and skipped
+// @Tags         ridership
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param counts body []models.PassengerCount required "counts"
+// @Success      200  {object}  models.CountBatchResult
+// @Failure      400  {object}  string
+// @Router       /ridership/counts/ [post]
+func (rc RidershipController) Ingest(c *gin.Context) {
+	var counts []models.PassengerCount
+	if err := c.ShouldBindJSON(&counts); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := rc.rs.Ingest(counts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary      Get ridership report
+// @Description  Get boardings and alightings per route, stop or hour of day
+// @Tags         ridership
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        from   query      string  true  "Period start (YYYY-MM-DD)"
+// @Param        to   query      string  true  "Period end, exclusive (YYYY-MM-DD)"
+// @Param        by   query      string  true  "Grouping: route, stop or hour"
+// @Param        granularity   query      string  false  "Counts to sum: stop (default), trip or bucket"
+// @Param        routeId   query      string  false  "Only counts of the route"
+// @Success      200  {array}  models.RidershipRow
+// @Failure      400  {object}  string
+// @Router       /ridership/report/ [get]
+func (rc RidershipController) GetReport(c *gin.Context) {
+	from, err := parseTime(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	to, err := parseTime(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	data, err := rc.rs.GetReport(from, to, c.Query("by"), c.Query("granularity"), c.Query("routeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+type RosterController struct {
+	rs service.IRosterService
+}
+
+func NewRosterController(rs service.IRosterService) *RosterController {
+	return &RosterController{rs}
+}
+
+// @Summary      Get duty
+// @Description  Get roster duty by ID
+// @Tags         roster
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Duty ID"
+// @Success      200  {object}  models.Duty
+// @Failure      400  {object}  string
+// @Router       /roster/{id}/ [get]
+func (rc RosterController) GetById(c *gin.Context) {
+	id := c.Param("id")
+	data, err := rc.rs.GetById(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      Get day roster
+// @Description  Get all duties for a day
+// @Tags         roster
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        date   path      string  true  "Date (YYYY-MM-DD)"
+// @Success      200  {object}  models.RosterDay
+// @Failure      400  {object}  string
+// @Router       /roster/day/{date}/ [get]
+func (rc RosterController) GetDay(c *gin.Context) {
+	date, err := parseTime(c.Param("date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	data, err := rc.rs.GetDay(date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      Get week roster
+// @Description  Get duties for the Monday-to-Sunday week containing the date
+// @Tags         roster
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        date   path      string  true  "Any date of the week (YYYY-MM-DD)"
+// @Success      200  {array}  models.RosterDay
+// @Failure      400  {object}  string
+// @Router       /roster/week/{date}/ [get]
+func (rc RosterController) GetWeek(c *gin.Context) {
+	date, err := parseTime(c.Param("date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	data, err := rc.rs.GetWeek(date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      Add duty
+// @Description  Add duty to the roster
+// @Tags         roster
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param duty body models.Duty required "duty model"
+// @Success      200  {object}  models.DutyResult
+// @Failure      400  {object}  string
+// @Router       /roster/ [post]
+func (rc RosterController) Add(c *gin.Context) {
+	var duty models.Duty
+	if err := c.ShouldBindJSON(&duty); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := rc.rs.Add(&duty)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary      Delete duty
+// @Description  Delete duty by ID
+// @Tags         roster
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Duty ID"
+// @Success      200  {object}  string
+// @Failure      400  {object}  string
+// @Router       /roster/{id}/ [delete]
+func (rc RosterController) DeleteById(c *gin.Context) {
+	id := c.Param("id")
+	err := rc.rs.DeleteById(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": id})
+}
+
+// @Summary      Update duty
+// @Description  Update duty by ID
+// @Tags         roster
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Duty ID"
+// @Param duty body models.Duty required "duty model"
+// @Success      200  {object}  models.DutyResult
+// @Failure      400  {object}  string
+// @Router       /roster/{id}/ [put]
+func (rc RosterController) UpdateById(c *gin.Context) {
+	var duty models.Duty
+	if err := c.ShouldBindJSON(&duty); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	duty.ID = c.Param("id")
+	result, err := rc.rs.UpdateById(&duty)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary      Start duty
+// @Description  Start duty by ID. The driver must have passed a medical check today
+// @Tags         roster
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Duty ID"
+// @Success      200  {object}  models.Duty
+// @Failure      400  {object}  string
+// @Router       /roster/{id}/start/ [post]
+func (rc RosterController) StartDuty(c *gin.Context) {
+	id := c.Param("id")
+	data, err := rc.rs.StartDuty(id, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
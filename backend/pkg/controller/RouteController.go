@@ -1,22 +1,27 @@
 package controller
 
 import (
+	"backend/pkg"
 	"backend/pkg/models"
 	"backend/pkg/service"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type RouteController struct {
-	rs service.IRouteService
+	rs  service.IRouteService
+	ps  service.IPrivacyService
+	als service.IAlertService
 }
 
-func NewRouteController(rs service.IRouteService) *RouteController {
-	return &RouteController{rs}
+func NewRouteController(rs service.IRouteService, ps service.IPrivacyService, als service.IAlertService) *RouteController {
+	return &RouteController{rs, ps, als}
 }
 
 // @Summary      Get route
-// @Description  Get route by ID
+// @Description  Get route by ID with its active service alerts
 // @Tags         routes
 // @Security ApiKeyAuth
 // @Produce      json
@@ -31,11 +36,17 @@ func (rc RouteController) GetById(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, data)
+	routes := []models.Route{*data}
+	err = rc.als.AttachToRoutes(routes, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, routes[0])
 }
 
 // @Summary      Get route by number
-// @Description  Get route by number
+// @Description  Get route by number with its active service alerts
 // @Tags         routes
 // @Security ApiKeyAuth
 // @Produce      json
@@ -50,11 +61,17 @@ func (rc RouteController) GetByNumber(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, data)
+	routes := []models.Route{*data}
+	err = rc.als.AttachToRoutes(routes, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, routes[0])
 }
 
 // @Summary      Get routes list
-// @Description  Get routes list
+// @Description  Get routes list with active service alerts
 // @Tags         routes
 // @Security ApiKeyAuth
 // @Produce      json
@@ -67,6 +84,11 @@ func (rc RouteController) GetAll(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, err.Error())
 		return
 	}
+	err = rc.als.AttachToRoutes(data, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	c.JSON(http.StatusOK, data)
 }
 
@@ -144,13 +166,32 @@ func (rc RouteController) UpdateById(c *gin.Context) {
 // @Produce      json
 // @Param        id   path      string  true  "Route ID"
 // @Param        driverId   path      string  true  "Driver ID"
+// @Param        from   query      string  false  "First day the driver works the route (YYYY-MM-DD), today by default"
+// @Param        to   query      string  false  "Last day the driver works the route (YYYY-MM-DD), the first one by default"
 // @Success      200  {object}  string
 // @Failure      400  {object}  string
 // @Router       /routes/{id}/drivers/{driverId}/ [post]
 func (rc RouteController) AssignDriver(c *gin.Context) {
 	routeId := c.Param("id")
 	driverId := c.Param("driverId")
-	err := rc.rs.AssignDriver(routeId, driverId)
+	var from, to *time.Time
+	if value := c.Query("from"); value != "" {
+		parsed, err := parseTime(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		from = &parsed
+	}
+	if value := c.Query("to"); value != "" {
+		parsed, err := parseTime(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		to = &parsed
+	}
+	err := rc.rs.AssignDriver(routeId, driverId, from, to)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -186,18 +227,39 @@ func (rc RouteController) AssignBusStop(c *gin.Context) {
 // @Produce      json
 // @Param        id   path      string  true  "Route ID"
 // @Param        busId   path      string  true  "Bus ID"
-// @Success      200  {object}  string
+// @Param        from   query      string  false  "Assignment start (RFC3339 or YYYY-MM-DD), defaults to now"
+// @Param        to   query      string  false  "Assignment end (RFC3339 or YYYY-MM-DD), open-ended if omitted"
+// @Param        force   query      bool  false  "Store the assignment even if it overlaps with other routes"
+// @Success      200  {object}  models.BusAssignmentResult
 // @Failure      400  {object}  string
 // @Router       /routes/{id}/buses/{busId}/ [post]
 func (rc RouteController) AssignBus(c *gin.Context) {
 	routeId := c.Param("id")
 	busId := c.Param("busId")
-	err := rc.rs.AssignBus(routeId, busId)
+	assignment := models.BusAssignment{RouteID: routeId, BusID: busId, StartsAt: time.Now()}
+	if from := c.Query("from"); from != "" {
+		startsAt, err := parseTime(from)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		assignment.StartsAt = startsAt
+	}
+	if to := c.Query("to"); to != "" {
+		endsAt, err := parseTime(to)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		assignment.EndsAt = &endsAt
+	}
+	force, _ := strconv.ParseBool(c.Query("force"))
+	result, err := rc.rs.AssignBusForPeriod(&assignment, force)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"success": routeId})
+	c.JSON(http.StatusOK, result)
 }
 
 // @Summary      Unassign driver from route
@@ -243,19 +305,29 @@ func (rc RouteController) UnassignBusStop(c *gin.Context) {
 }
 
 // @Summary      Unassign bus from route
-// @Description  Unassign bus from route
+// @Description  Remove one assignment period of the bus on the route, the current one unless starts_at is given
 // @Tags         routes
 // @Security ApiKeyAuth
 // @Produce      json
 // @Param        id   path      string  true  "Route ID"
 // @Param        busId   path      string  true  "Bus ID"
+// @Param        starts_at   query      string  false  "Start of the assignment period (RFC3339)"
 // @Success      200  {object}  string
 // @Failure      400  {object}  string
 // @Router       /routes/{id}/buses/{busId}/ [delete]
 func (rc RouteController) UnassignBus(c *gin.Context) {
 	routeId := c.Param("id")
 	busId := c.Param("busId")
-	err := rc.rs.UnassignBus(routeId, busId)
+	var startsAt *time.Time
+	if value := c.Query("starts_at"); value != "" {
+		parsed, err := parseTime(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		startsAt = &parsed
+	}
+	err := rc.rs.UnassignBus(routeId, busId, startsAt)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -264,7 +336,7 @@ func (rc RouteController) UnassignBus(c *gin.Context) {
 }
 
 // @Summary      Get all drivers on route
-// @Description  Get all drivers on route by route ID
+// @Description  Get all drivers on route by route ID. Identity documents are masked unless the caller has the hr role
 // @Tags         routes
 // @Security ApiKeyAuth
 // @Produce      json
@@ -279,6 +351,12 @@ func (rc RouteController) GetAllDriversById(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	userId, roles := pkg.GetUserIdentity(c)
+	err = rc.ps.ProtectDrivers(data, userId, roles, c.Request.Method+" "+c.FullPath())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 	c.JSON(http.StatusOK, data)
 }
 
@@ -302,7 +380,7 @@ func (rc RouteController) GetAllBusesById(c *gin.Context) {
 }
 
 // @Summary      Get all bus stops on route
-// @Description  Get all bus stops on route by route ID
+// @Description  Get all bus stops on route by route ID with their active service alerts
 // @Tags         routes
 // @Security ApiKeyAuth
 // @Produce      json
@@ -317,5 +395,34 @@ func (rc RouteController) GetAllBusStopsById(c *gin.Context) {
 		c.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
+	err = rc.als.AttachToStops(data, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      Get bus conflicts
+// @Description  Get buses assigned to several routes for overlapping periods
+// @Tags         conflicts
+// @Security ApiKeyAuth
+// @Produce      json
+// @Success      200  {array}  models.BusConflict
+// @Failure      400  {object}  string
+// @Router       /conflicts/buses/ [get]
+func (rc RouteController) GetBusConflicts(c *gin.Context) {
+	data, err := rc.rs.GetBusConflicts()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	c.JSON(http.StatusOK, data)
 }
+
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type ScheduleController struct {
+	ss service.IScheduleService
+}
+
+func NewScheduleController(ss service.IScheduleService) *ScheduleController {
+	return &ScheduleController{ss}
+}
+
+// @Summary      Get trip
+// @Description  Get scheduled trip with its stop times by ID
+// @Tags         schedule
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Trip ID"
+// @Success      200  {object}  models.Trip
+// @Failure      400  {object}  string
+// @Router       /schedule/trips/{id}/ [get]
+func (sc ScheduleController) GetTrip(c *gin.Context) {
+	id := c.Param("id")
+	data, err := sc.ss.GetTrip(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      Add trip
+// @Description  Schedule a trip within a duty. Stop times must follow the route stop order
+// @Tags         schedule
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param trip body models.Trip required "trip"
+// @Success      200  {object}  models.Trip
+// @Failure      400  {object}  string
+// @Router       /schedule/trips/ [post]
+func (sc ScheduleController) AddTrip(c *gin.Context) {
+	var trip models.Trip
+	if err := c.ShouldBindJSON(&trip); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	err := sc.ss.AddTrip(&trip)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, trip)
+}
+
+// @Summary      Delete trip
+// @Description  Delete scheduled trip by ID
+// @Tags         schedule
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Trip ID"
+// @Success      200  {object}  string
+// @Failure      400  {object}  string
+// @Router       /schedule/trips/{id}/ [delete]
+func (sc ScheduleController) DeleteTrip(c *gin.Context) {
+	id := c.Param("id")
+	err := sc.ss.DeleteTrip(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": id})
+}
+
+// @Summary      Get duty trips
+// @Description  Get the trips scheduled within a duty
+// @Tags         schedule
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Duty ID"
+// @Success      200  {array}  models.Trip
+// @Failure      400  {object}  string
+// @Router       /schedule/duties/{id}/trips/ [get]
+func (sc ScheduleController) GetTripsByDutyId(c *gin.Context) {
+	id := c.Param("id")
+	data, err := sc.ss.GetTripsByDutyId(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      Get on-time performance
+// @Description  Get early, on-time and late percentages of trips per route, driver or day
+// @Tags         schedule
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        from   query      string  true  "Period start (YYYY-MM-DD)"
+// @Param        to   query      string  true  "Period end, exclusive (YYYY-MM-DD)"
+// @Param        by   query      string  true  "Grouping: route, driver or day"
+// @Success      200  {array}  models.PerformanceRow
+// @Failure      400  {object}  string
+// @Router       /schedule/performance/ [get]
+func (sc ScheduleController) GetPerformance(c *gin.Context) {
+	from, err := parseTime(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	to, err := parseTime(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	data, err := sc.ss.GetPerformance(from, to, c.Query("by"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
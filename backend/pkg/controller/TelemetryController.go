@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"backend/pkg"
+	"backend/pkg/models"
+	"backend/pkg/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// streamKeepAlive keeps idle streams from being closed by proxies.
+	streamKeepAlive = 15 * time.Second
+	// streamSessionCheck is how often an open stream checks that its session
+	// is still alive, so that it ends soon after a logout.
+	streamSessionCheck = 30 * time.Second
+)
+
+type TelemetryController struct {
+	ts service.ITelemetryService
+	us service.UserService
+}
+
+func NewTelemetryController(ts service.ITelemetryService, us service.UserService) *TelemetryController {
+	return &TelemetryController{ts, us}
+}
+
+// @Summary      Push vehicle positions
+// @Description  Push a batch of positions from on-board units. Invalid items are reported and skipped
+// @Tags         telemetry
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param positions body []models.VehiclePosition required "positions"
+// @Success      200  {object}  models.PositionBatchResult
+// @Failure      400  {object}  string
+// @Router       /telemetry/positions/ [post]
+func (tc TelemetryController) Ingest(c *gin.Context) {
+	var positions []models.VehiclePosition
+	if err := c.ShouldBindJSON(&positions); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := tc.ts.Ingest(positions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary      Get bus position
+// @Description  Get the latest known position of a bus
+// @Tags         buses
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "Bus ID"
+// @Success      200  {object}  models.VehiclePosition
+// @Failure      404  {object}  string
+// @Router       /buses/{id}/position/ [get]
+func (tc TelemetryController) GetLatest(c *gin.Context) {
+	id := c.Param("id")
+	data, err := tc.ts.GetLatest(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      Stream vehicle positions
+// @Description  Server-sent events stream of position updates. The first "positions" event is a snapshot of the latest known positions. The stream ends with an "error" event once the session ends
+// @Tags         telemetry
+// @Security ApiKeyAuth
+// @Produce      text/event-stream
+// @Param        token   query      string  false  "Stream token from /auth/stream-token, instead of the Authorization header"
+// @Param        routeId   query      string  false  "Only buses of the route"
+// @Param        bbox   query      string  false  "Bounding box: minLat,minLong,maxLat,maxLong"
+// @Success      200  {array}  models.VehiclePosition
+// @Failure      400  {object}  string
+// @Router       /telemetry/stream/ [get]
+func (tc TelemetryController) Stream(c *gin.Context) {
+	var box *models.BoundingBox
+	if value := c.Query("bbox"); value != "" {
+		parsed, err := parseBoundingBox(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		box = parsed
+	}
+	snapshot, updates, unsubscribe, err := tc.ts.Subscribe(c.Query("routeId"), box)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer unsubscribe()
+
+	if snapshot == nil {
+		snapshot = []models.VehiclePosition{}
+	}
+	c.SSEvent("positions", snapshot)
+	c.Writer.Flush()
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	sessionCheck := time.NewTicker(streamSessionCheck)
+	defer sessionCheck.Stop()
+	sessionId := pkg.GetSessionId(c)
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case positions, ok := <-updates:
+			if !ok {
+				return false
+			}
+			c.SSEvent("positions", positions)
+			return true
+		case <-keepAlive.C:
+			_, err := io.WriteString(w, ": keep-alive\n\n")
+			return err == nil
+		case <-sessionCheck.C:
+			if err := tc.us.CheckSession(sessionId); err != nil {
+				c.SSEvent("error", err.Error())
+				return false
+			}
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+func parseBoundingBox(value string) (*models.BoundingBox, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return nil, errors.New("Invalid bounding box, expected minLat,minLong,maxLat,maxLong")
+	}
+	var coords [4]float64
+	for i, part := range parts {
+		coord, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, errors.New("Invalid bounding box, expected minLat,minLong,maxLat,maxLong")
+		}
+		coords[i] = coord
+	}
+	return &models.BoundingBox{MinLat: coords[0], MinLong: coords[1], MaxLat: coords[2], MaxLong: coords[3]}, nil
+}
@@ -2,6 +2,7 @@ package controller
 
 import "C"
 import (
+	"backend/pkg"
 	"backend/pkg/models"
 	"backend/pkg/service"
 	"github.com/gin-gonic/gin"
@@ -52,13 +53,168 @@ func (u UserController) Signin(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	token, err := u.s.GenerateToken(user.Username, user.Password)
+	tokens, err := u.s.GenerateToken(user.Username, user.Password)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, tokensResponse(tokens))
+}
+
+// @Summary      Refresh tokens
+// @Description  Exchange a refresh token for a new access token and refresh token, the old refresh token stops working
+// @Tags         auth
+// @Produce      json
+// @Param body body object required "refresh_token from sign-in or the previous refresh"
+// @Success      200  {object}  object
+// @Failure      400  {object}  string
+// @Router       /auth/refresh/ [POST]
+func (u UserController) Refresh(c *gin.Context) {
+	var body map[string]string
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tokens, err := u.s.Refresh(body["refresh_token"])
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tokensResponse(tokens))
+}
+
+// @Summary      Log out
+// @Description  End the session of the access token
+// @Tags         auth
+// @Security ApiKeyAuth
+// @Produce      json
+// @Success      200  {object}  string
+// @Failure      400  {object}  string
+// @Router       /auth/logout/ [POST]
+func (u UserController) Logout(c *gin.Context) {
+	sessionId := pkg.GetSessionId(c)
+	err := u.s.Logout(sessionId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": sessionId})
+}
 
-	c.JSON(http.StatusOK, map[string]interface{}{
-		"token": token,
-	})
+// @Summary      Log out everywhere
+// @Description  End every session of the signed in user
+// @Tags         auth
+// @Security ApiKeyAuth
+// @Produce      json
+// @Success      200  {object}  string
+// @Failure      400  {object}  string
+// @Router       /auth/logout-all/ [POST]
+func (u UserController) LogoutAll(c *gin.Context) {
+	userId, _ := pkg.GetUserIdentity(c)
+	err := u.s.LogoutAll(userId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": userId})
+}
+
+// @Summary      Get stream token
+// @Description  Get a token that opens a position stream within a minute, passed as the token query parameter by clients that can't send the Authorization header
+// @Tags         auth
+// @Security ApiKeyAuth
+// @Produce      json
+// @Success      200  {object}  string
+// @Failure      400  {object}  string
+// @Router       /auth/stream-token/ [POST]
+func (u UserController) StreamToken(c *gin.Context) {
+	userId, roles := pkg.GetUserIdentity(c)
+	token, expiresAt, err := u.s.GenerateStreamToken(service.Identity{UserId: userId, Roles: roles, SessionId: pkg.GetSessionId(c)})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"token": token, "expires_at": expiresAt})
+}
+
+func tokensResponse(tokens *models.Tokens) gin.H {
+	return gin.H{
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_at":    tokens.ExpiresAt,
+	}
+}
+
+// @Summary      Get all users
+// @Description  Get all users with their roles
+// @Tags         users
+// @Security ApiKeyAuth
+// @Produce      json
+// @Success      200  {array}  models.User
+// @Failure      400  {object}  string
+// @Router       /users/ [get]
+func (u UserController) GetAll(c *gin.Context) {
+	data, err := u.s.GetAll()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      Grant role
+// @Description  Grant a role to the user, effective from the next sign-in
+// @Tags         users
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Param        role   path      string  true  "Role (admin, dispatcher, hr, viewer)"
+// @Success      200  {object}  models.User
+// @Failure      400  {object}  string
+// @Router       /users/{id}/roles/{role} [post]
+func (u UserController) GrantRole(c *gin.Context) {
+	data, err := u.s.GrantRole(c.Param("id"), c.Param("role"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      Revoke role
+// @Description  Revoke a role from the user, effective from the next sign-in
+// @Tags         users
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Param        role   path      string  true  "Role (admin, dispatcher, hr, viewer)"
+// @Success      200  {object}  models.User
+// @Failure      400  {object}  string
+// @Router       /users/{id}/roles/{role} [delete]
+func (u UserController) RevokeRole(c *gin.Context) {
+	data, err := u.s.RevokeRole(c.Param("id"), c.Param("role"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary      End user sessions
+// @Description  End every session of the user, e.g. when they leave the company
+// @Tags         users
+// @Security ApiKeyAuth
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  string
+// @Failure      400  {object}  string
+// @Router       /users/{id}/sessions [delete]
+func (u UserController) RevokeSessions(c *gin.Context) {
+	id := c.Param("id")
+	err := u.s.LogoutAll(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": id})
 }
@@ -2,6 +2,7 @@ package pkg
 
 import "C"
 import (
+	"backend/pkg/models"
 	"backend/pkg/service"
 	"errors"
 	"github.com/gin-gonic/gin"
@@ -12,6 +13,8 @@ import (
 const (
 	authorizationHeader = "Authorization"
 	userCtx             = "userId"
+	userRolesCtx        = "userRoles"
+	sessionCtx          = "sessionId"
 )
 
 func UserIdentity(c *gin.Context, userService service.UserService) {
@@ -35,17 +38,40 @@ func UserIdentity(c *gin.Context, userService service.UserService) {
 		return
 	}
 
-	userId, err := userService.ParseToken(headerParts[1])
+	identity, err := userService.ParseToken(headerParts[1])
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"Error": err.Error()})
 		c.Abort()
 		return
 	}
 
-	c.Set(userCtx, userId)
+	c.Set(userCtx, identity.UserId)
+	c.Set(userRolesCtx, identity.Roles)
+	c.Set(sessionCtx, identity.SessionId)
 
 }
 
+// StreamIdentity authenticates a stream by the stream token in the token
+// query parameter, and falls back to the Authorization header without one.
+func StreamIdentity(c *gin.Context, userService service.UserService) {
+	token := c.Query("token")
+	if token == "" {
+		UserIdentity(c, userService)
+		return
+	}
+
+	identity, err := userService.ParseStreamToken(token)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"Error": err.Error()})
+		c.Abort()
+		return
+	}
+
+	c.Set(userCtx, identity.UserId)
+	c.Set(userRolesCtx, identity.Roles)
+	c.Set(sessionCtx, identity.SessionId)
+}
+
 func getUserId(c *gin.Context) (string, error) {
 	id, ok := c.Get(userCtx)
 	if !ok {
@@ -58,3 +84,53 @@ func getUserId(c *gin.Context) (string, error) {
 	strId := id.(string)
 	return strId, nil
 }
+
+// GetUserIdentity returns the ID and roles of the authenticated caller.
+func GetUserIdentity(c *gin.Context) (string, []string) {
+	return c.GetString(userCtx), c.GetStringSlice(userRolesCtx)
+}
+
+// GetSessionId returns the session the access token of the caller belongs to.
+func GetSessionId(c *gin.Context) string {
+	return c.GetString(sessionCtx)
+}
+
+// Permissions maps an HTTP method to the roles allowed to use it. Methods
+// missing from the map are open to every signed in user.
+type Permissions map[string][]string
+
+// WriteRoles lets every signed in user read and only the roles change data.
+func WriteRoles(roles ...string) Permissions {
+	return Permissions{
+		http.MethodPost:   roles,
+		http.MethodPut:    roles,
+		http.MethodPatch:  roles,
+		http.MethodDelete: roles,
+	}
+}
+
+// OnlyRoles limits every method to the roles.
+func OnlyRoles(roles ...string) Permissions {
+	permissions := WriteRoles(roles...)
+	permissions[http.MethodGet] = roles
+	return permissions
+}
+
+// Authorize checks the roles set by UserIdentity against the permissions of
+// the route group. Admins pass every check.
+func Authorize(permissions Permissions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed, ok := permissions[c.Request.Method]
+		if !ok {
+			return
+		}
+		_, roles := GetUserIdentity(c)
+		for _, role := range allowed {
+			if models.HasRole(roles, role) {
+				return
+			}
+		}
+		c.JSON(http.StatusForbidden, gin.H{"Error": "access denied"})
+		c.Abort()
+	}
+}
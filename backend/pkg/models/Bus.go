@@ -11,4 +11,6 @@ type Bus struct {
 	RegisterNumber string
 	AssemblyDate   time.Time
 	LastRepairDate time.Time
+	// VehicleClass is the driving licence category the bus requires, e.g. D or D1.
+	VehicleClass string
 }
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+type BusAssignment struct {
+	RouteID  string
+	BusID    string
+	StartsAt time.Time
+	EndsAt   *time.Time
+}
+
+type BusConflict struct {
+	BusID  string
+	First  BusAssignment
+	Second BusAssignment
+}
+
+type BusAssignmentResult struct {
+	Assignment BusAssignment
+	Conflicts  []BusConflict
+	Warnings   []string
+}
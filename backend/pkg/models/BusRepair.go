@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// BusRepair is the time a bus spends out of service. A repair without
+// FinishedAt is still going on.
+type BusRepair struct {
+	ID         string
+	BusID      string
+	Reason     string
+	IncidentID string
+	StartedAt  time.Time
+	FinishedAt *time.Time
+}
+
+// RouteReplacement lists the spare buses that can take over a route from a
+// failing bus.
+type RouteReplacement struct {
+	RouteID     string
+	RouteNumber string
+	Candidates  []Bus
+}
+
+type ReplacementProposal struct {
+	Bus    Bus
+	Routes []RouteReplacement
+}
+
+type BusReplacement struct {
+	RouteID    string
+	SpareBusID string
+}
+
+// Breakdown takes a failing bus off its routes into repair. Routes missing
+// from Replacements are left without the bus.
+type Breakdown struct {
+	BusID        string
+	Reason       string
+	IncidentID   string
+	Replacements []BusReplacement
+}
+
+type BreakdownResult struct {
+	Repair      BusRepair
+	Assignments []BusAssignment
+	Warnings    []string
+}
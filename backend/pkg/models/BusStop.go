@@ -5,4 +5,6 @@ type BusStop struct {
 	Lat  float64
 	Long float64
 	Name string
+	// Alerts are the service alerts active at the time of the request.
+	Alerts []ServiceAlert
 }
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type ComplianceViolation struct {
+	DriverID      string
+	Rule          string
+	Date          time.Time
+	DutyIDs       []string
+	ActualMinutes int
+	LimitMinutes  int
+	Message       string
+}
+
+type ComplianceDay struct {
+	Date           time.Time
+	DrivingMinutes int
+	BreakMinutes   int
+}
+
+type ComplianceReport struct {
+	DriverID            string
+	From                time.Time
+	To                  time.Time
+	TotalDrivingMinutes int
+	Days                []ComplianceDay
+	Violations          []ComplianceViolation
+}
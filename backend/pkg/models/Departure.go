@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// ScheduledDeparture is a trip leaving a stop, with the bus of its duty.
+type ScheduledDeparture struct {
+	TripID          string
+	RouteID         string
+	BusID           string
+	StopSequence    int
+	Departure       time.Time
+	ActualDeparture *time.Time
+}
+
+type Departure struct {
+	TripID      string
+	RouteID     string
+	RouteNumber string
+	BusID       string
+	Label       string
+	// Scheduled is empty for buses running without a scheduled trip.
+	Scheduled *time.Time
+	Expected  time.Time
+	Minutes   int
+	// DelayMinutes is how much later than scheduled the bus is expected,
+	// negative when it runs early.
+	DelayMinutes int
+	// Realtime marks departures expected from a live bus position.
+	Realtime bool
+}
+
+type DepartureBoard struct {
+	Stop        BusStop
+	GeneratedAt time.Time
+	Departures  []Departure
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+const (
+	AbsenceVacation  = "vacation"
+	AbsenceSickLeave = "sick_leave"
+	AbsenceTraining  = "training"
+)
+
+// DriverAbsence is a period when the driver can't work. Both StartsOn and
+// EndsOn are inclusive days.
+type DriverAbsence struct {
+	ID       string
+	DriverID string
+	Kind     string
+	StartsOn time.Time
+	EndsOn   time.Time
+	Note     string
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+type DriverLicense struct {
+	DriverID   string
+	IssueDate  time.Time
+	ExpiryDate time.Time
+	Categories []LicenseCategory
+}
+
+type LicenseCategory struct {
+	Category   string
+	IssueDate  time.Time
+	ExpiryDate time.Time
+	// Restrictions holds the restriction codes printed for the category, e.g. "AT".
+	Restrictions string
+}
+
+type ExpiringLicense struct {
+	DriverID   string
+	Name       string
+	Surname    string
+	Patronymic string
+	// Category is empty when the licence itself expires.
+	Category   string
+	ExpiryDate time.Time
+	DaysLeft   int
+}
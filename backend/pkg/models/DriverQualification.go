@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// DriverQualification records that a driver is trained on a bus model.
+type DriverQualification struct {
+	DriverID  string
+	Brand     string
+	BusModel  string
+	TrainedOn time.Time
+	Trainer   string
+}
+
+type BusModelKey struct {
+	Brand    string
+	BusModel string
+}
+
+type QualificationRow struct {
+	DriverID   string
+	Name       string
+	Surname    string
+	Patronymic string
+	// Qualified is aligned with QualificationMatrix.Models.
+	Qualified []bool
+}
+
+type QualificationMatrix struct {
+	Models []BusModelKey
+	Rows   []QualificationRow
+}
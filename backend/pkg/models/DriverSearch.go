@@ -0,0 +1,14 @@
+package models
+
+type DriverSearchHit struct {
+	Driver Driver
+	// Score is the relevance from 0 to 1, 1 being an exact match.
+	Score float64
+}
+
+type DriverSearchPage struct {
+	Items    []DriverSearchHit
+	Total    int
+	Page     int
+	PageSize int
+}
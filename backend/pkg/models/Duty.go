@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+type Duty struct {
+	ID         string
+	Date       time.Time
+	DriverID   string
+	BusID      string
+	RouteID    string
+	ShiftStart time.Time
+	ShiftEnd   time.Time
+	// BreakMinutes is the total break time inside the shift window.
+	BreakMinutes int
+	// ComplianceOverride stores the duty even if it breaks working time rules.
+	ComplianceOverride bool
+	// StartedAt is set once the driver has started the duty.
+	StartedAt *time.Time
+}
+
+type DutyResult struct {
+	Duty       Duty
+	Violations []ComplianceViolation
+	Warnings   []string
+}
+
+type RosterDay struct {
+	Date   time.Time
+	Duties []Duty
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// GtfsVehicle is a vehicle entity of the GTFS-Realtime feed. IDs are the
+// ones of our database, which the GTFS static export uses as well.
+type GtfsVehicle struct {
+	BusID   string
+	Label   string
+	RouteID string
+	// TripID is empty when the bus is on no scheduled trip.
+	TripID   string
+	Position VehiclePosition
+}
+
+type GtfsStopTimeUpdate struct {
+	StopID       string
+	StopSequence int
+	Arrival      time.Time
+}
+
+type GtfsTripUpdate struct {
+	BusID     string
+	Label     string
+	RouteID   string
+	TripID    string
+	Timestamp time.Time
+	StopTimes []GtfsStopTimeUpdate
+}
+
+type GtfsFeed struct {
+	Timestamp   time.Time
+	Vehicles    []GtfsVehicle
+	TripUpdates []GtfsTripUpdate
+}
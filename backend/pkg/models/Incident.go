@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+const (
+	IncidentBreakdown = "breakdown"
+	IncidentAccident  = "accident"
+	IncidentComplaint = "complaint"
+	IncidentDelay     = "delay"
+)
+
+const (
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+const (
+	IncidentOpen          = "open"
+	IncidentInvestigating = "investigating"
+	IncidentClosed        = "closed"
+)
+
+// Incident links to a bus, a driver and a route; the ones that do not
+// apply are left empty. Lat and Long are set when the place is known
+// precisely.
+type Incident struct {
+	ID          string
+	Type        string
+	Severity    string
+	Status      string
+	OccurredAt  time.Time
+	Location    string
+	Lat         *float64
+	Long        *float64
+	BusID       string
+	DriverID    string
+	RouteID     string
+	Description string
+	ReportedBy  string
+	Resolution  string
+	ClosedAt    *time.Time
+}
+
+// IncidentFilter narrows the incident list. Zero fields are not applied.
+type IncidentFilter struct {
+	From     time.Time
+	To       time.Time
+	BusID    string
+	DriverID string
+	RouteID  string
+	Type     string
+	Status   string
+}
+
+type IncidentStatusChange struct {
+	Status     string
+	Resolution string
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type MedicalCheck struct {
+	ID        string
+	DriverID  string
+	CheckedAt time.Time
+	Passed    bool
+	// Systolic and Diastolic are the blood pressure in mmHg.
+	Systolic  int
+	Diastolic int
+	// Alcohol is the breath alcohol test result in mg/l.
+	Alcohol  float64
+	Examiner string
+}
+
+// PendingMedicalCheck is a driver rostered for the day who has no passed check yet.
+type PendingMedicalCheck struct {
+	DriverID   string
+	Name       string
+	Surname    string
+	Patronymic string
+	ShiftStart time.Time
+	// Failed is set when the driver was examined but did not pass.
+	Failed bool
+}
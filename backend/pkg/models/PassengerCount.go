@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Granularities of passenger counts. Counts of different granularities
+// overlap, so they are never summed together.
+const (
+	CountPerStop   = "stop"
+	CountPerTrip   = "trip"
+	CountPerBucket = "bucket"
+)
+
+// PassengerCount is a reading of an automatic passenger counter. It is
+// taken at a stop, over a trip or over a time bucket starting at CountedAt;
+// StopID, TripID and BucketMinutes are set accordingly.
+type PassengerCount struct {
+	BusID         string
+	CountedAt     time.Time
+	StopID        string
+	TripID        string
+	RouteID       string
+	BucketMinutes int
+	// Granularity is derived from the fields above when the count is stored.
+	Granularity string
+	Boardings   int
+	Alightings  int
+}
+
+type CountRejection struct {
+	// Index is the position of the rejected item in the pushed batch.
+	Index int
+	Error string
+}
+
+type CountBatchResult struct {
+	Accepted int
+	Rejected []CountRejection
+}
+
+const (
+	RidershipByRoute = "route"
+	RidershipByStop  = "stop"
+	RidershipByHour  = "hour"
+)
+
+type RidershipRow struct {
+	// Key is the route ID, the stop ID or the hour of day.
+	Key        string
+	Boardings  int
+	Alightings int
+}
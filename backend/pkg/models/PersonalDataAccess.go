@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// PersonalDataAccess records an unmasked read of driver personal data.
+type PersonalDataAccess struct {
+	ID         string
+	UserID     string
+	DriverID   string
+	Action     string
+	AccessedAt time.Time
+}
@@ -3,4 +3,6 @@ package models
 type Route struct {
 	ID     string
 	Number string
+	// Alerts are the service alerts active at the time of the request.
+	Alerts []ServiceAlert
 }
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Causes and effects follow the GTFS-Realtime alert enums.
+const (
+	CauseTechnicalProblem = "technical_problem"
+	CauseStrike           = "strike"
+	CauseDemonstration    = "demonstration"
+	CauseAccident         = "accident"
+	CauseHoliday          = "holiday"
+	CauseWeather          = "weather"
+	CauseMaintenance      = "maintenance"
+	CauseConstruction     = "construction"
+	CausePoliceActivity   = "police_activity"
+	CauseMedicalEmergency = "medical_emergency"
+	CauseOther            = "other_cause"
+)
+
+const (
+	EffectNoService         = "no_service"
+	EffectReducedService    = "reduced_service"
+	EffectSignificantDelays = "significant_delays"
+	EffectDetour            = "detour"
+	EffectAdditionalService = "additional_service"
+	EffectModifiedService   = "modified_service"
+	EffectStopMoved         = "stop_moved"
+	EffectOther             = "other_effect"
+)
+
+const (
+	AlertInfo    = "info"
+	AlertWarning = "warning"
+	AlertSevere  = "severe"
+)
+
+// ServiceAlert is shown to passengers from StartsAt until EndsAt. An alert
+// without EndsAt lasts until a dispatcher ends it.
+type ServiceAlert struct {
+	ID          string
+	Title       string
+	Description string
+	Cause       string
+	Effect      string
+	Severity    string
+	StartsAt    time.Time
+	EndsAt      *time.Time
+	RouteIDs    []string
+	StopIDs     []string
+	PublishedBy string
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Session is a sign-in of a user. Access tokens carry the session ID, and the
+// refresh token, stored only as a hash, is replaced on every refresh. A
+// revoked session can't be used anymore.
+type Session struct {
+	ID               string
+	UserID           string
+	RefreshTokenHash string
+	CreatedAt        time.Time
+	ExpiresAt        time.Time
+	RevokedAt        *time.Time
+}
+
+// Tokens is what a sign-in or refresh returns
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	// ExpiresAt is when the access token expires
+	ExpiresAt time.Time
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// SegmentTravelTime is the average time buses of a route need between two
+// consecutive stops, by hour of day.
+type SegmentTravelTime struct {
+	RouteID    string
+	FromStopID string
+	ToStopID   string
+	Hour       int
+	AvgSeconds float64
+	Samples    int
+}
+
+// StopPassage is the last route stop a bus was seen at.
+type StopPassage struct {
+	BusID    string
+	RouteID  string
+	StopID   string
+	PassedAt time.Time
+}
+
+type VehicleArrival struct {
+	BusID     string
+	Label     string
+	ArrivesAt time.Time
+	Minutes   int
+}
+
+type RouteArrivals struct {
+	RouteID     string
+	RouteNumber string
+	Vehicles    []VehicleArrival
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Trip is one scheduled run of a route within a duty. The bus and the
+// driver are the ones of the duty.
+type Trip struct {
+	ID        string
+	DutyID    string
+	RouteID   string
+	StartsAt  time.Time
+	EndsAt    time.Time
+	StopTimes []TripStopTime
+}
+
+type TripStopTime struct {
+	// StopSequence is the position of the stop within the trip, starting at
+	// 1. A trip back along the route visits the route stops in reverse.
+	StopSequence    int
+	StopID          string
+	Arrival         time.Time
+	Departure       time.Time
+	ActualArrival   *time.Time
+	ActualDeparture *time.Time
+}
+
+// ObservedStopTime is a stop time the bus was seen at, with the trip data
+// the performance report is grouped by.
+type ObservedStopTime struct {
+	TripID   string
+	RouteID  string
+	DriverID string
+	// Terminal marks the last stop of the trip.
+	Terminal bool
+	TripStopTime
+}
+
+const (
+	PerformanceByRoute  = "route"
+	PerformanceByDriver = "driver"
+	PerformanceByDay    = "day"
+)
+
+type PerformanceRow struct {
+	Key           string
+	Observed      int
+	Early         int
+	OnTime        int
+	Late          int
+	EarlyPercent  float64
+	OnTimePercent float64
+	LatePercent   float64
+}
@@ -1,7 +1,38 @@
 package models
 
+const (
+	// RoleAdmin manages user roles and passes every role check.
+	RoleAdmin      = "admin"
+	RoleViewer     = "viewer"
+	RoleDispatcher = "dispatcher"
+	// RoleHR is the only role that sees driver personal data unmasked.
+	RoleHR = "hr"
+)
+
 type User struct {
 	ID       string
 	Username string
 	Password string
+	Roles    []string
+}
+
+// HasRole reports whether the roles include the role or admin
+func HasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role || r == RoleAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// HoldsRole reports whether the roles include the role itself, admin does
+// not stand in for it
+func HoldsRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+type VehiclePosition struct {
+	BusID      string
+	RecordedAt time.Time
+	Lat        float64
+	Long       float64
+	// Speed is in km/h.
+	Speed float64
+	// Heading is in degrees clockwise from north.
+	Heading float64
+}
+
+type PositionRejection struct {
+	// Index is the position of the rejected item in the pushed batch.
+	Index int
+	BusID string
+	Error string
+}
+
+type PositionBatchResult struct {
+	Accepted int
+	Rejected []PositionRejection
+}
+
+type BoundingBox struct {
+	MinLat  float64
+	MinLong float64
+	MaxLat  float64
+	MaxLong float64
+}
+
+func (b BoundingBox) Contains(lat, long float64) bool {
+	return lat >= b.MinLat && lat <= b.MaxLat && long >= b.MinLong && long <= b.MaxLong
+}
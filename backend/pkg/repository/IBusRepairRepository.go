@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type IBusRepairRepository interface {
+	GetOpen(busId string) (*models.BusRepair, error)
+	GetAllOpen() ([]models.BusRepair, error)
+	ReplaceBus(repair *models.BusRepair, replacements []models.BusAssignment) error
+	Finish(busId string, at time.Time) error
+}
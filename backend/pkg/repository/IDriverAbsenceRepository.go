@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type IDriverAbsenceRepository interface {
+	GetById(id string) (*models.DriverAbsence, error)
+	Add(absence *models.DriverAbsence) error
+	DeleteById(id string) error
+	GetByDriverId(driverId string) ([]models.DriverAbsence, error)
+	GetOverlapping(driverId string, from, to time.Time) ([]models.DriverAbsence, error)
+	GetByDate(date time.Time) ([]models.DriverAbsence, error)
+}
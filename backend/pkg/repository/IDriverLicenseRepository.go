@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type IDriverLicenseRepository interface {
+	GetByDriverId(driverId string) (*models.DriverLicense, error)
+	Save(license *models.DriverLicense) error
+	GetExpiring(before time.Time) ([]models.DriverLicense, error)
+}
@@ -0,0 +1,11 @@
+package repository
+
+import "backend/pkg/models"
+
+type IDriverQualificationRepository interface {
+	Save(qualification *models.DriverQualification) error
+	Delete(driverId, brand, busModel string) error
+	GetByDriverId(driverId string) ([]models.DriverQualification, error)
+	GetByBusModel(brand, busModel string) ([]models.DriverQualification, error)
+	GetAll() ([]models.DriverQualification, error)
+}
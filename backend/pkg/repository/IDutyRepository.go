@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type IDutyRepository interface {
+	GetById(id string) (*models.Duty, error)
+	Add(duty *models.Duty) error
+	DeleteById(id string) error
+	UpdateById(duty *models.Duty) error
+	SetStarted(id string, startedAt time.Time) error
+	GetByPeriod(from, to time.Time) ([]models.Duty, error)
+	GetByDriverId(driverId string, from, to time.Time) ([]models.Duty, error)
+	GetOverlapping(driverId, busId string, shiftStart, shiftEnd time.Time) ([]models.Duty, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type IIncidentRepository interface {
+	GetById(id string) (*models.Incident, error)
+	Add(incident *models.Incident) error
+	SetStatus(id, expected, status, resolution string, closedAt *time.Time) error
+	Find(filter models.IncidentFilter) ([]models.Incident, error)
+}
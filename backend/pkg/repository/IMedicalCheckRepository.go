@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type IMedicalCheckRepository interface {
+	GetById(id string) (*models.MedicalCheck, error)
+	Add(check *models.MedicalCheck) error
+	GetByDriverId(driverId string, from, to time.Time) ([]models.MedicalCheck, error)
+	GetByPeriod(from, to time.Time) ([]models.MedicalCheck, error)
+}
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type IPassengerCountRepository interface {
+	AddBatch(counts []models.PassengerCount) error
+	Aggregate(from, to time.Time, groupBy, granularity, routeId string) ([]models.RidershipRow, error)
+}
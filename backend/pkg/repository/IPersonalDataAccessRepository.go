@@ -0,0 +1,7 @@
+package repository
+
+import "backend/pkg/models"
+
+type IPersonalDataAccessRepository interface {
+	Add(access *models.PersonalDataAccess) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type IPositionRepository interface {
+	SaveBatch(positions []models.VehiclePosition) error
+	GetLatest(busId string) (*models.VehiclePosition, error)
+	GetAllLatest() ([]models.VehiclePosition, error)
+	GetHistory(busId string, from, to time.Time) ([]models.VehiclePosition, error)
+}
@@ -1,6 +1,9 @@
 package repository
 
-import "backend/pkg/models"
+import (
+	"backend/pkg/models"
+	"time"
+)
 
 type IRouteRepository interface {
 	GetById(id string) (*models.Route, error)
@@ -14,8 +17,11 @@ type IRouteRepository interface {
 	AssignBus(routeId, busId string) error
 	UnassignDriver(routeId, driverId string) error
 	UnassignBusStop(routeId, busStopId string) error
-	UnassignBus(routeId, busId string) error
+	UnassignBus(routeId, busId string, startsAt time.Time) error
 	GetAllDriversById(routeId string) ([]models.Driver, error)
 	GetAllBusStopsById(routeId string) ([]models.BusStop, error)
-	GetAllBusesById(routeId string) ([]models.Bus, error)
+	GetAllBusesAt(routeId string, at time.Time) ([]models.Bus, error)
+	AssignBusForPeriod(assignment *models.BusAssignment, force bool) error
+	GetBusAssignments(busId string) ([]models.BusAssignment, error)
+	GetBusConflicts() ([]models.BusConflict, error)
 }
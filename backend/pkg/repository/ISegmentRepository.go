@@ -0,0 +1,11 @@
+package repository
+
+import "backend/pkg/models"
+
+type ISegmentRepository interface {
+	AddTravelTime(routeId, fromStopId, toStopId string, hour int, seconds float64) error
+	GetTravelTimes(routeId string) ([]models.SegmentTravelTime, error)
+	GetLastStop(busId string) (*models.StopPassage, error)
+	GetAllLastStops() ([]models.StopPassage, error)
+	SaveLastStop(passage *models.StopPassage) error
+}
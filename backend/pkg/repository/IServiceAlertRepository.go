@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type IServiceAlertRepository interface {
+	GetById(id string) (*models.ServiceAlert, error)
+	Add(alert *models.ServiceAlert) error
+	End(id string, at time.Time) error
+	GetActive(at time.Time, routeId, stopId string) ([]models.ServiceAlert, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type ISessionRepository interface {
+	GetById(id string) (*models.Session, error)
+	GetByRefreshTokenHash(hash string) (*models.Session, error)
+	GetByRotatedRefreshTokenHash(hash string) (*models.Session, error)
+	Add(session *models.Session) error
+	Rotate(id, oldHash, newHash string, expiresAt time.Time) error
+	Revoke(id string, at time.Time) error
+	RevokeByUserId(userId string, at time.Time) error
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type ITripRepository interface {
+	GetById(id string) (*models.Trip, error)
+	Add(trip *models.Trip) error
+	DeleteById(id string) error
+	GetByDutyId(dutyId string) ([]models.Trip, error)
+	GetByBusId(busId string, from, to time.Time) ([]models.Trip, error)
+	SetActual(tripId string, stopSequence int, arrival, departure time.Time) error
+	GetObserved(from, to time.Time) ([]models.ObservedStopTime, error)
+	GetStopDepartures(stopId string, from, to time.Time) ([]models.ScheduledDeparture, error)
+}
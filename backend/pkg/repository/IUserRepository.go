@@ -4,6 +4,11 @@ import "backend/pkg/models"
 
 type IUserRepository interface {
 	GetById(id string) (*models.User, error)
-	GetByUsername(username, password string) (*models.User, error)
+	GetByUsername(username string) (*models.User, error)
 	Add(user *models.User) error
+	UpdatePassword(userId, passwordHash string) error
+	GetAll() ([]models.User, error)
+	GrantRole(userId, role string) error
+	RevokeRole(userId, role string) error
+	CountByRole(role string) (int, error)
 }
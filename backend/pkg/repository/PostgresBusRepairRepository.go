@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+type PostgresBusRepairRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresBusRepairRepository(db *sql.DB) (*PostgresBusRepairRepository, error) {
+	repo := &PostgresBusRepairRepository{db: db}
+	return repo, nil
+}
+
+// GetOpen returns the repair the bus is in now
+func (r *PostgresBusRepairRepository) GetOpen(busId string) (*models.BusRepair, error) {
+	repair := &models.BusRepair{}
+	err := r.db.QueryRow(`
+		SELECT id, bus_id, reason, incident_id, started_at, finished_at
+		FROM bus_repairs
+		WHERE bus_id = $1 AND finished_at IS NULL`, busId).Scan(
+		&repair.ID,
+		&repair.BusID,
+		&repair.Reason,
+		&repair.IncidentID,
+		&repair.StartedAt,
+		&repair.FinishedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("Repair not found")
+		}
+		return nil, err
+	}
+	return repair, nil
+}
+
+func (r *PostgresBusRepairRepository) GetAllOpen() ([]models.BusRepair, error) {
+	rows, err := r.db.Query(`
+		SELECT id, bus_id, reason, incident_id, started_at, finished_at
+		FROM bus_repairs
+		WHERE finished_at IS NULL
+		ORDER BY started_at
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var repairs []models.BusRepair
+	for rows.Next() {
+		repair := &models.BusRepair{}
+		err := rows.Scan(
+			&repair.ID,
+			&repair.BusID,
+			&repair.Reason,
+			&repair.IncidentID,
+			&repair.StartedAt,
+			&repair.FinishedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		repairs = append(repairs, *repair)
+	}
+	return repairs, nil
+}
+
+// ReplaceBus takes the bus off the routes it serves when the repair starts,
+// puts it into repair and assigns the replacement buses, all in one
+// transaction. Periods that start later stay with the bus. The replacement
+// buses are locked and checked to be still spare within the transaction.
+func (r *PostgresBusRepairRepository) ReplaceBus(repair *models.BusRepair, replacements []models.BusAssignment) error {
+	if strings.TrimSpace(repair.ID) == "" {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return err
+		}
+		repair.ID = id.String()
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// the broken bus stays locked so that no assignment or repair slips in
+	var inRepair bool
+	err = tx.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM bus_repairs WHERE bus_id = $1 AND finished_at IS NULL)
+		FROM buses
+		WHERE id = $1
+		FOR UPDATE`, repair.BusID).Scan(&inRepair)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("Bus not found")
+		}
+		return err
+	}
+	if inRepair {
+		return errors.New("Bus is already in repair")
+	}
+	for _, assignment := range replacements {
+		var busy bool
+		err = tx.QueryRow(`
+			SELECT EXISTS (SELECT 1 FROM routes_buses WHERE bus_id = $1 AND (ends_at IS NULL OR ends_at > $2))
+				OR EXISTS (SELECT 1 FROM bus_repairs WHERE bus_id = $1 AND finished_at IS NULL)
+			FROM buses
+			WHERE id = $1
+			FOR UPDATE`, assignment.BusID, assignment.StartsAt).Scan(&busy)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errors.New("Bus not found")
+			}
+			return err
+		}
+		if busy {
+			return fmt.Errorf("Bus %s is no longer spare", assignment.BusID)
+		}
+	}
+	_, err = tx.Exec(`UPDATE routes_buses SET ends_at = $2
+WHERE bus_id = $1 AND starts_at <= $2 AND (ends_at IS NULL OR ends_at > $2)`, repair.BusID, repair.StartedAt)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`INSERT into bus_repairs (id, bus_id, reason, incident_id, started_at, finished_at) 
+VALUES ($1, $2, $3, $4, $5, $6)`,
+		repair.ID,
+		repair.BusID,
+		repair.Reason,
+		repair.IncidentID,
+		repair.StartedAt,
+		repair.FinishedAt,
+	)
+	if err != nil {
+		return err
+	}
+	for _, assignment := range replacements {
+		_, err = tx.Exec(`INSERT into routes_buses (route_id, bus_id, starts_at, ends_at) 
+VALUES ($1, $2, $3, $4)`, assignment.RouteID, assignment.BusID, assignment.StartsAt, assignment.EndsAt)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Finish closes the open repair of the bus and records the repair date on
+// the bus
+func (r *PostgresBusRepairRepository) Finish(busId string, at time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE bus_repairs SET finished_at = $1 WHERE bus_id = $2 AND finished_at IS NULL`, at, busId)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("Repair not found")
+	}
+	_, err = tx.Exec(`UPDATE buses SET last_repair_date = $1 WHERE id = $2`, at, busId)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func setupMockBusRepair(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *PostgresBusRepairRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Ошибка создания mock базы данных: %v", err)
+	}
+	repo := &PostgresBusRepairRepository{db: db}
+	return db, mock, repo
+}
+
+func newTestBusRepair() *models.BusRepair {
+	return &models.BusRepair{
+		ID:         uuid.New().String(),
+		BusID:      uuid.New().String(),
+		Reason:     "Отказ тормозной системы",
+		IncidentID: uuid.New().String(),
+		StartedAt:  time.Date(2025, 4, 7, 9, 30, 0, 0, time.UTC),
+	}
+}
+
+func TestPostgresBusRepairRepository(t *testing.T) {
+	columns := []string{"id", "bus_id", "reason", "incident_id", "started_at", "finished_at"}
+
+	t.Run("NewPostgresBusRepairRepository", func(t *testing.T) {
+		db, _, _ := setupMockBusRepair(t)
+		defer db.Close()
+
+		repo, err := NewPostgresBusRepairRepository(db)
+		if err != nil {
+			t.Errorf("Ошибка при создании репозитория: %v", err)
+		}
+		if repo == nil {
+			t.Error("Репозиторий не должен быть nil")
+		}
+	})
+
+	t.Run("GetOpen", func(t *testing.T) {
+		db, mock, repo := setupMockBusRepair(t)
+		defer db.Close()
+
+		repair := newTestBusRepair()
+		mock.ExpectQuery(`SELECT id, bus_id, reason, incident_id, started_at, finished_at FROM bus_repairs WHERE bus_id = \$1 AND finished_at IS NULL`).
+			WithArgs(repair.BusID).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(repair.ID, repair.BusID, repair.Reason, repair.IncidentID, repair.StartedAt, nil))
+		mock.ExpectQuery(`SELECT id, bus_id, reason, incident_id, started_at, finished_at FROM bus_repairs WHERE bus_id = \$1 AND finished_at IS NULL`).
+			WithArgs("in-service").
+			WillReturnError(sql.ErrNoRows)
+
+		retrieved, err := repo.GetOpen(repair.BusID)
+		if err != nil {
+			t.Errorf("Ошибка при получении ремонта: %v", err)
+		}
+		if !reflect.DeepEqual(repair, retrieved) {
+			t.Errorf("Полученный ремонт не совпадает: ожидалось %v, получено %v", repair, retrieved)
+		}
+		_, err = repo.GetOpen("in-service")
+		if err == nil || err.Error() != "Repair not found" {
+			t.Errorf("Ожидалась ошибка 'Repair not found', получена: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("GetAllOpen", func(t *testing.T) {
+		db, mock, repo := setupMockBusRepair(t)
+		defer db.Close()
+
+		repair := newTestBusRepair()
+		mock.ExpectQuery(`SELECT id, bus_id, reason, incident_id, started_at, finished_at FROM bus_repairs WHERE finished_at IS NULL ORDER BY started_at`).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(repair.ID, repair.BusID, repair.Reason, repair.IncidentID, repair.StartedAt, nil))
+
+		repairs, err := repo.GetAllOpen()
+		if err != nil {
+			t.Errorf("Ошибка при получении ремонтов: %v", err)
+		}
+		if len(repairs) != 1 || !reflect.DeepEqual(*repair, repairs[0]) {
+			t.Errorf("Полученные ремонты не совпадают: получено %v", repairs)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("ReplaceBus", func(t *testing.T) {
+		db, mock, repo := setupMockBusRepair(t)
+		defer db.Close()
+
+		repair := newTestBusRepair()
+		repair.ID = ""
+		endsAt := repair.StartedAt.Add(48 * time.Hour)
+		replacement := models.BusAssignment{RouteID: uuid.New().String(), BusID: uuid.New().String(), StartsAt: repair.StartedAt, EndsAt: &endsAt}
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM bus_repairs WHERE bus_id = \$1 AND finished_at IS NULL\)(.|\n)*FOR UPDATE`).
+			WithArgs(repair.BusID).
+			WillReturnRows(sqlmock.NewRows([]string{"in_repair"}).AddRow(false))
+		mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM routes_buses WHERE bus_id = \$1 AND \(ends_at IS NULL OR ends_at > \$2\)\)(.|\n)*FOR UPDATE`).
+			WithArgs(replacement.BusID, replacement.StartsAt).
+			WillReturnRows(sqlmock.NewRows([]string{"busy"}).AddRow(false))
+		mock.ExpectExec(`UPDATE routes_buses SET ends_at = \$2\s+WHERE bus_id = \$1 AND starts_at <= \$2 AND \(ends_at IS NULL OR ends_at > \$2\)`).
+			WithArgs(repair.BusID, repair.StartedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT into bus_repairs \(id, bus_id, reason, incident_id, started_at, finished_at\)`).
+			WithArgs(sqlmock.AnyArg(), repair.BusID, repair.Reason, repair.IncidentID, repair.StartedAt, repair.FinishedAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`INSERT into routes_buses \(route_id, bus_id, starts_at, ends_at\)`).
+			WithArgs(replacement.RouteID, replacement.BusID, replacement.StartsAt, replacement.EndsAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := repo.ReplaceBus(repair, []models.BusAssignment{replacement})
+		if err != nil {
+			t.Errorf("Ошибка при замене автобуса: %v", err)
+		}
+		if repair.ID == "" {
+			t.Error("ID ремонта должен быть сгенерирован")
+		}
+
+		other := newTestBusRepair()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM bus_repairs WHERE bus_id = \$1 AND finished_at IS NULL\)(.|\n)*FOR UPDATE`).
+			WithArgs(other.BusID).
+			WillReturnRows(sqlmock.NewRows([]string{"in_repair"}).AddRow(false))
+		mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM routes_buses`).
+			WithArgs(replacement.BusID, replacement.StartsAt).
+			WillReturnRows(sqlmock.NewRows([]string{"busy"}).AddRow(true))
+		mock.ExpectRollback()
+
+		err = repo.ReplaceBus(other, []models.BusAssignment{replacement})
+		if err == nil || err.Error() != "Bus "+replacement.BusID+" is no longer spare" {
+			t.Errorf("Ожидалась ошибка о занятом резервном автобусе, получена: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM bus_repairs`).
+			WithArgs(other.BusID).
+			WillReturnRows(sqlmock.NewRows([]string{"in_repair"}).AddRow(true))
+		mock.ExpectRollback()
+
+		err = repo.ReplaceBus(other, []models.BusAssignment{replacement})
+		if err == nil || err.Error() != "Bus is already in repair" {
+			t.Errorf("Ожидалась ошибка 'Bus is already in repair', получена: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("Finish", func(t *testing.T) {
+		db, mock, repo := setupMockBusRepair(t)
+		defer db.Close()
+
+		at := time.Date(2025, 4, 9, 17, 0, 0, 0, time.UTC)
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE bus_repairs SET finished_at = \$1 WHERE bus_id = \$2 AND finished_at IS NULL`).
+			WithArgs(at, "bus-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE buses SET last_repair_date = \$1 WHERE id = \$2`).
+			WithArgs(at, "bus-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE bus_repairs SET finished_at = \$1 WHERE bus_id = \$2 AND finished_at IS NULL`).
+			WithArgs(at, "bus-2").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.Finish("bus-1", at)
+		if err != nil {
+			t.Errorf("Ошибка при завершении ремонта: %v", err)
+		}
+		err = repo.Finish("bus-2", at)
+		if err == nil || err.Error() != "Repair not found" {
+			t.Errorf("Ожидалась ошибка 'Repair not found', получена: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+}
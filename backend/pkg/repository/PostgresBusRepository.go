@@ -22,7 +22,7 @@ func NewPostgresBusRepository(db *sql.DB) (*PostgresBusRepository, error) {
 func (r *PostgresBusRepository) GetById(id string) (*models.Bus, error) {
 	bus := &models.Bus{}
 	err := r.db.QueryRow(`
-		SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date 
+		SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date, vehicle_class 
 		FROM buses 
 		WHERE id = $1`, id).Scan(
 		&bus.ID,
@@ -31,6 +31,7 @@ func (r *PostgresBusRepository) GetById(id string) (*models.Bus, error) {
 		&bus.RegisterNumber,
 		&bus.AssemblyDate,
 		&bus.LastRepairDate,
+		&bus.VehicleClass,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -45,7 +46,7 @@ func (r *PostgresBusRepository) GetById(id string) (*models.Bus, error) {
 func (r *PostgresBusRepository) GetByNumber(number string) (*models.Bus, error) {
 	bus := &models.Bus{}
 	err := r.db.QueryRow(`
-		SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date 
+		SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date, vehicle_class 
 		FROM buses 
 		WHERE register_number = $1`, number).Scan(
 		&bus.ID,
@@ -54,6 +55,7 @@ func (r *PostgresBusRepository) GetByNumber(number string) (*models.Bus, error)
 		&bus.RegisterNumber,
 		&bus.AssemblyDate,
 		&bus.LastRepairDate,
+		&bus.VehicleClass,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -77,13 +79,14 @@ func (r *PostgresBusRepository) Add(bus *models.Bus) error {
 		}
 		bus.ID = id.String()
 	}
-	_, err = r.db.Exec(`INSERT into buses (id, brand, bus_model, register_number, assembly_date, last_repair_date ) 
-VALUES ($1, $2, $3, $4, $5, $6)`, &bus.ID,
+	_, err = r.db.Exec(`INSERT into buses (id, brand, bus_model, register_number, assembly_date, last_repair_date, vehicle_class ) 
+VALUES ($1, $2, $3, $4, $5, $6, $7)`, &bus.ID,
 		&bus.Brand,
 		&bus.BusModel,
 		&bus.RegisterNumber,
 		&bus.AssemblyDate,
-		&bus.LastRepairDate)
+		&bus.LastRepairDate,
+		&bus.VehicleClass)
 	if err != nil {
 		return err
 	}
@@ -95,7 +98,7 @@ func (r *PostgresBusRepository) GetAll() ([]models.Bus, error) {
 
 	var buses []models.Bus
 	rows, err := r.db.Query(`
-		SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date 
+		SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date, vehicle_class 
 		FROM buses 
 		`)
 	if err != nil {
@@ -113,6 +116,7 @@ func (r *PostgresBusRepository) GetAll() ([]models.Bus, error) {
 			&bus.RegisterNumber,
 			&bus.AssemblyDate,
 			&bus.LastRepairDate,
+			&bus.VehicleClass,
 		)
 		if err != nil {
 			return nil, err
@@ -146,7 +150,7 @@ func (r *PostgresBusRepository) UpdateById(bus *models.Bus) error {
 	if err != nil {
 		return err
 	}
-	_, err = r.db.Exec("UPDATE buses SET brand = $1, bus_model = $2, register_number = $3, assembly_date = $4, last_repair_date = $5 WHERE id = $6", bus.Brand, bus.BusModel, bus.RegisterNumber, bus.AssemblyDate, bus.LastRepairDate, bus.ID)
+	_, err = r.db.Exec("UPDATE buses SET brand = $1, bus_model = $2, register_number = $3, assembly_date = $4, last_repair_date = $5, vehicle_class = $6 WHERE id = $7", bus.Brand, bus.BusModel, bus.RegisterNumber, bus.AssemblyDate, bus.LastRepairDate, bus.VehicleClass, bus.ID)
 
 	if err != nil {
 		return err
@@ -47,9 +47,9 @@ func TestPostgresBusRepository(t *testing.T) {
 			LastRepairDate: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
 		}
 
-		rows := sqlmock.NewRows([]string{"id", "brand", "bus_model", "register_number", "assembly_date", "last_repair_date"}).
-			AddRow(bus.ID, bus.Brand, bus.BusModel, bus.RegisterNumber, bus.AssemblyDate, bus.LastRepairDate)
-		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date FROM buses WHERE id = \$1`).
+		rows := sqlmock.NewRows([]string{"id", "brand", "bus_model", "register_number", "assembly_date", "last_repair_date", "vehicle_class"}).
+			AddRow(bus.ID, bus.Brand, bus.BusModel, bus.RegisterNumber, bus.AssemblyDate, bus.LastRepairDate, bus.VehicleClass)
+		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date, vehicle_class FROM buses WHERE id = \$1`).
 			WithArgs(busID).
 			WillReturnRows(rows)
 
@@ -61,7 +61,7 @@ func TestPostgresBusRepository(t *testing.T) {
 			t.Errorf("Полученный автобус не совпадает: ожидался %v, получен %v", bus, retrievedBus)
 		}
 
-		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date FROM buses WHERE id = \$1`).
+		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date, vehicle_class FROM buses WHERE id = \$1`).
 			WithArgs("nonexistent").
 			WillReturnError(sql.ErrNoRows)
 
@@ -87,9 +87,9 @@ func TestPostgresBusRepository(t *testing.T) {
 			AssemblyDate:   time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
 		}
 
-		rows := sqlmock.NewRows([]string{"id", "brand", "bus_model", "register_number", "assembly_date", "last_repair_date"}).
-			AddRow(bus.ID, bus.Brand, bus.BusModel, bus.RegisterNumber, bus.AssemblyDate, bus.LastRepairDate)
-		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date FROM buses WHERE register_number = \$1`).
+		rows := sqlmock.NewRows([]string{"id", "brand", "bus_model", "register_number", "assembly_date", "last_repair_date", "vehicle_class"}).
+			AddRow(bus.ID, bus.Brand, bus.BusModel, bus.RegisterNumber, bus.AssemblyDate, bus.LastRepairDate, bus.VehicleClass)
+		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date, vehicle_class FROM buses WHERE register_number = \$1`).
 			WithArgs("DEF456").
 			WillReturnRows(rows)
 
@@ -101,7 +101,7 @@ func TestPostgresBusRepository(t *testing.T) {
 			t.Errorf("Полученный автобус не совпадает: ожидался %v, получен %v", bus, retrievedBus)
 		}
 
-		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date FROM buses WHERE register_number = \$1`).
+		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date, vehicle_class FROM buses WHERE register_number = \$1`).
 			WithArgs("NONEXISTENT").
 			WillReturnError(sql.ErrNoRows)
 
@@ -127,13 +127,13 @@ func TestPostgresBusRepository(t *testing.T) {
 			AssemblyDate:   time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
 		}
 
-		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date FROM buses WHERE register_number = \$1`).
+		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date, vehicle_class FROM buses WHERE register_number = \$1`).
 			WithArgs(bus.RegisterNumber).
 			WillReturnError(sql.ErrNoRows)
 
 		// Точный SQL-запрос из кода репозитория
-		mock.ExpectExec(`INSERT into buses \(id, brand, bus_model, register_number, assembly_date, last_repair_date \) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6\)`).
-			WithArgs(bus.ID, bus.Brand, bus.BusModel, bus.RegisterNumber, bus.AssemblyDate, bus.LastRepairDate).
+		mock.ExpectExec(`INSERT into buses \(id, brand, bus_model, register_number, assembly_date, last_repair_date, vehicle_class \) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7\)`).
+			WithArgs(bus.ID, bus.Brand, bus.BusModel, bus.RegisterNumber, bus.AssemblyDate, bus.LastRepairDate, bus.VehicleClass).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		err := repo.Add(bus)
@@ -141,10 +141,10 @@ func TestPostgresBusRepository(t *testing.T) {
 			t.Errorf("Ошибка при добавлении автобуса: %v", err)
 		}
 
-		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date FROM buses WHERE register_number = \$1`).
+		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date, vehicle_class FROM buses WHERE register_number = \$1`).
 			WithArgs(bus.RegisterNumber).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "brand", "bus_model", "register_number", "assembly_date", "last_repair_date"}).
-				AddRow(bus.ID, bus.Brand, bus.BusModel, bus.RegisterNumber, bus.AssemblyDate, bus.LastRepairDate))
+			WillReturnRows(sqlmock.NewRows([]string{"id", "brand", "bus_model", "register_number", "assembly_date", "last_repair_date", "vehicle_class"}).
+				AddRow(bus.ID, bus.Brand, bus.BusModel, bus.RegisterNumber, bus.AssemblyDate, bus.LastRepairDate, bus.VehicleClass))
 
 		err = repo.Add(bus)
 		if err == nil || err.Error() != "Bus already exists" {
@@ -175,10 +175,10 @@ func TestPostgresBusRepository(t *testing.T) {
 			AssemblyDate:   time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
 		}
 
-		rows := sqlmock.NewRows([]string{"id", "brand", "bus_model", "register_number", "assembly_date", "last_repair_date"}).
-			AddRow(bus1.ID, bus1.Brand, bus1.BusModel, bus1.RegisterNumber, bus1.AssemblyDate, bus1.LastRepairDate).
-			AddRow(bus2.ID, bus2.Brand, bus2.BusModel, bus2.RegisterNumber, bus2.AssemblyDate, bus2.LastRepairDate)
-		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date FROM buses`).
+		rows := sqlmock.NewRows([]string{"id", "brand", "bus_model", "register_number", "assembly_date", "last_repair_date", "vehicle_class"}).
+			AddRow(bus1.ID, bus1.Brand, bus1.BusModel, bus1.RegisterNumber, bus1.AssemblyDate, bus1.LastRepairDate, bus1.VehicleClass).
+			AddRow(bus2.ID, bus2.Brand, bus2.BusModel, bus2.RegisterNumber, bus2.AssemblyDate, bus2.LastRepairDate, bus2.VehicleClass)
+		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date, vehicle_class FROM buses`).
 			WillReturnRows(rows)
 
 		buses, err := repo.GetAll()
@@ -201,8 +201,8 @@ func TestPostgresBusRepository(t *testing.T) {
 			t.Errorf("Не все автобусы найдены в списке: bus1=%v, bus2=%v", foundBus1, foundBus2)
 		}
 
-		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date FROM buses`).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "brand", "bus_model", "register_number", "assembly_date", "last_repair_date"}))
+		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date, vehicle_class FROM buses`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "brand", "bus_model", "register_number", "assembly_date", "last_repair_date", "vehicle_class"}))
 
 		buses, err = repo.GetAll()
 		if err != nil {
@@ -223,10 +223,10 @@ func TestPostgresBusRepository(t *testing.T) {
 
 		busID := uuid.New().String()
 
-		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date FROM buses WHERE id = \$1`).
+		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date, vehicle_class FROM buses WHERE id = \$1`).
 			WithArgs(busID).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "brand", "bus_model", "register_number", "assembly_date", "last_repair_date"}).
-				AddRow(busID, "Volvo", "B9R", "PQR678", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)))
+			WillReturnRows(sqlmock.NewRows([]string{"id", "brand", "bus_model", "register_number", "assembly_date", "last_repair_date", "vehicle_class"}).
+				AddRow(busID, "Volvo", "B9R", "PQR678", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), "D"))
 
 		mock.ExpectExec(`DELETE FROM buses WHERE id = \$1`).
 			WithArgs(busID).
@@ -237,7 +237,7 @@ func TestPostgresBusRepository(t *testing.T) {
 			t.Errorf("Ошибка при удалении автобуса: %v", err)
 		}
 
-		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date FROM buses WHERE id = \$1`).
+		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date, vehicle_class FROM buses WHERE id = \$1`).
 			WithArgs("nonexistent").
 			WillReturnError(sql.ErrNoRows)
 
@@ -264,13 +264,13 @@ func TestPostgresBusRepository(t *testing.T) {
 			LastRepairDate: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
 		}
 
-		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date FROM buses WHERE id = \$1`).
+		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date, vehicle_class FROM buses WHERE id = \$1`).
 			WithArgs(bus.ID).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "brand", "bus_model", "register_number", "assembly_date", "last_repair_date"}).
-				AddRow(bus.ID, bus.Brand, bus.BusModel, bus.RegisterNumber, bus.AssemblyDate, bus.LastRepairDate))
+			WillReturnRows(sqlmock.NewRows([]string{"id", "brand", "bus_model", "register_number", "assembly_date", "last_repair_date", "vehicle_class"}).
+				AddRow(bus.ID, bus.Brand, bus.BusModel, bus.RegisterNumber, bus.AssemblyDate, bus.LastRepairDate, bus.VehicleClass))
 
-		mock.ExpectExec(`UPDATE buses SET brand = \$1, bus_model = \$2, register_number = \$3, assembly_date = \$4, last_repair_date = \$5 WHERE id = \$6`).
-			WithArgs(bus.Brand, bus.BusModel, bus.RegisterNumber, bus.AssemblyDate, bus.LastRepairDate, bus.ID).
+		mock.ExpectExec(`UPDATE buses SET brand = \$1, bus_model = \$2, register_number = \$3, assembly_date = \$4, last_repair_date = \$5, vehicle_class = \$6 WHERE id = \$7`).
+			WithArgs(bus.Brand, bus.BusModel, bus.RegisterNumber, bus.AssemblyDate, bus.LastRepairDate, bus.VehicleClass, bus.ID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		err := repo.UpdateById(bus)
@@ -278,7 +278,7 @@ func TestPostgresBusRepository(t *testing.T) {
 			t.Errorf("Ошибка при обновлении автобуса: %v", err)
 		}
 
-		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date FROM buses WHERE id = \$1`).
+		mock.ExpectQuery(`SELECT id, brand, bus_model, register_number, assembly_date, last_repair_date, vehicle_class FROM buses WHERE id = \$1`).
 			WithArgs("nonexistent").
 			WillReturnError(sql.ErrNoRows)
 
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+type PostgresDriverAbsenceRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresDriverAbsenceRepository(db *sql.DB) (*PostgresDriverAbsenceRepository, error) {
+	repo := &PostgresDriverAbsenceRepository{db: db}
+	return repo, nil
+}
+
+func (r *PostgresDriverAbsenceRepository) GetById(id string) (*models.DriverAbsence, error) {
+	absence := &models.DriverAbsence{}
+	err := r.db.QueryRow(`
+		SELECT id, driver_id, kind, starts_on, ends_on, note
+		FROM driver_absences
+		WHERE id = $1`, id).Scan(
+		&absence.ID,
+		&absence.DriverID,
+		&absence.Kind,
+		&absence.StartsOn,
+		&absence.EndsOn,
+		&absence.Note,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("Absence not found")
+		}
+		return nil, err
+	}
+
+	return absence, nil
+}
+
+func (r *PostgresDriverAbsenceRepository) Add(absence *models.DriverAbsence) error {
+	if strings.TrimSpace(absence.ID) == "" {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return err
+		}
+		absence.ID = id.String()
+	}
+	_, err := r.db.Exec(`INSERT into driver_absences (id, driver_id, kind, starts_on, ends_on, note) 
+VALUES ($1, $2, $3, $4, $5, $6)`, &absence.ID,
+		&absence.DriverID,
+		&absence.Kind,
+		&absence.StartsOn,
+		&absence.EndsOn,
+		&absence.Note,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *PostgresDriverAbsenceRepository) DeleteById(id string) error {
+	exist, err := r.GetById(id)
+	if exist == nil {
+		return errors.New("Absence not found")
+	}
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec("DELETE FROM driver_absences WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *PostgresDriverAbsenceRepository) GetByDriverId(driverId string) ([]models.DriverAbsence, error) {
+	rows, err := r.db.Query(`
+		SELECT id, driver_id, kind, starts_on, ends_on, note
+		FROM driver_absences
+		WHERE driver_id = $1
+		ORDER BY starts_on
+	`, driverId)
+	if err != nil {
+		return nil, err
+	}
+	return scanDriverAbsences(rows)
+}
+
+// GetOverlapping returns absences of the driver that share at least one day
+// with [from, to], both days inclusive.
+func (r *PostgresDriverAbsenceRepository) GetOverlapping(driverId string, from, to time.Time) ([]models.DriverAbsence, error) {
+	rows, err := r.db.Query(`
+		SELECT id, driver_id, kind, starts_on, ends_on, note
+		FROM driver_absences
+		WHERE driver_id = $1 AND starts_on <= $3 AND ends_on >= $2
+		ORDER BY starts_on
+	`, driverId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return scanDriverAbsences(rows)
+}
+
+// GetByDate returns absences of all drivers covering the date.
+func (r *PostgresDriverAbsenceRepository) GetByDate(date time.Time) ([]models.DriverAbsence, error) {
+	rows, err := r.db.Query(`
+		SELECT id, driver_id, kind, starts_on, ends_on, note
+		FROM driver_absences
+		WHERE starts_on <= $1 AND ends_on >= $1
+	`, date)
+	if err != nil {
+		return nil, err
+	}
+	return scanDriverAbsences(rows)
+}
+
+func scanDriverAbsences(rows *sql.Rows) ([]models.DriverAbsence, error) {
+	var absences []models.DriverAbsence
+	for rows.Next() {
+		absence := &models.DriverAbsence{}
+		err := rows.Scan(
+			&absence.ID,
+			&absence.DriverID,
+			&absence.Kind,
+			&absence.StartsOn,
+			&absence.EndsOn,
+			&absence.Note,
+		)
+		if err != nil {
+			return nil, err
+		}
+		absences = append(absences, *absence)
+	}
+	return absences, nil
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func setupMockDriverAbsence(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *PostgresDriverAbsenceRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Ошибка создания mock базы данных: %v", err)
+	}
+	repo := &PostgresDriverAbsenceRepository{db: db}
+	return db, mock, repo
+}
+
+func newTestDriverAbsence() *models.DriverAbsence {
+	return &models.DriverAbsence{
+		ID:       uuid.New().String(),
+		DriverID: uuid.New().String(),
+		Kind:     models.AbsenceVacation,
+		StartsOn: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+		EndsOn:   time.Date(2025, 7, 14, 0, 0, 0, 0, time.UTC),
+		Note:     "Летний отпуск",
+	}
+}
+
+func TestPostgresDriverAbsenceRepository(t *testing.T) {
+	columns := []string{"id", "driver_id", "kind", "starts_on", "ends_on", "note"}
+
+	t.Run("NewPostgresDriverAbsenceRepository", func(t *testing.T) {
+		db, _, _ := setupMockDriverAbsence(t)
+		defer db.Close()
+
+		repo, err := NewPostgresDriverAbsenceRepository(db)
+		if err != nil {
+			t.Errorf("Ошибка при создании репозитория: %v", err)
+		}
+		if repo == nil {
+			t.Error("Репозиторий не должен быть nil")
+		}
+	})
+
+	t.Run("GetById", func(t *testing.T) {
+		db, mock, repo := setupMockDriverAbsence(t)
+		defer db.Close()
+
+		absence := newTestDriverAbsence()
+		mock.ExpectQuery(`SELECT id, driver_id, kind, starts_on, ends_on, note FROM driver_absences WHERE id = \$1`).
+			WithArgs(absence.ID).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(absence.ID, absence.DriverID, absence.Kind, absence.StartsOn, absence.EndsOn, absence.Note))
+
+		retrieved, err := repo.GetById(absence.ID)
+		if err != nil {
+			t.Errorf("Ошибка при получении отсутствия по ID: %v", err)
+		}
+		if !reflect.DeepEqual(absence, retrieved) {
+			t.Errorf("Полученное отсутствие не совпадает: ожидалось %v, получено %v", absence, retrieved)
+		}
+
+		mock.ExpectQuery(`SELECT id, driver_id, kind, starts_on, ends_on, note FROM driver_absences WHERE id = \$1`).
+			WithArgs("nonexistent").
+			WillReturnError(sql.ErrNoRows)
+
+		_, err = repo.GetById("nonexistent")
+		if err == nil || err.Error() != "Absence not found" {
+			t.Errorf("Ожидалась ошибка 'Absence not found', получена: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("Add", func(t *testing.T) {
+		db, mock, repo := setupMockDriverAbsence(t)
+		defer db.Close()
+
+		absence := newTestDriverAbsence()
+		absence.ID = ""
+		mock.ExpectExec(`INSERT into driver_absences \(id, driver_id, kind, starts_on, ends_on, note\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6\)`).
+			WithArgs(sqlmock.AnyArg(), absence.DriverID, absence.Kind, absence.StartsOn, absence.EndsOn, absence.Note).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.Add(absence)
+		if err != nil {
+			t.Errorf("Ошибка при добавлении отсутствия: %v", err)
+		}
+		if absence.ID == "" {
+			t.Error("ID отсутствия должен быть сгенерирован")
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("DeleteById", func(t *testing.T) {
+		db, mock, repo := setupMockDriverAbsence(t)
+		defer db.Close()
+
+		absence := newTestDriverAbsence()
+		mock.ExpectQuery(`SELECT id, driver_id, kind, starts_on, ends_on, note FROM driver_absences WHERE id = \$1`).
+			WithArgs(absence.ID).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(absence.ID, absence.DriverID, absence.Kind, absence.StartsOn, absence.EndsOn, absence.Note))
+		mock.ExpectExec(`DELETE FROM driver_absences WHERE id = \$1`).
+			WithArgs(absence.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.DeleteById(absence.ID)
+		if err != nil {
+			t.Errorf("Ошибка при удалении отсутствия: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("GetOverlapping", func(t *testing.T) {
+		db, mock, repo := setupMockDriverAbsence(t)
+		defer db.Close()
+
+		absence := newTestDriverAbsence()
+		from := time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(0, 0, 7)
+		mock.ExpectQuery(`SELECT id, driver_id, kind, starts_on, ends_on, note FROM driver_absences WHERE driver_id = \$1 AND starts_on <= \$3 AND ends_on >= \$2`).
+			WithArgs(absence.DriverID, from, to).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(absence.ID, absence.DriverID, absence.Kind, absence.StartsOn, absence.EndsOn, absence.Note))
+
+		absences, err := repo.GetOverlapping(absence.DriverID, from, to)
+		if err != nil {
+			t.Errorf("Ошибка при получении пересекающихся отсутствий: %v", err)
+		}
+		if len(absences) != 1 || !reflect.DeepEqual(*absence, absences[0]) {
+			t.Errorf("Ожидалось 1 отсутствие %v, получено: %v", absence, absences)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("GetByDate", func(t *testing.T) {
+		db, mock, repo := setupMockDriverAbsence(t)
+		defer db.Close()
+
+		absence := newTestDriverAbsence()
+		date := time.Date(2025, 7, 5, 0, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`SELECT id, driver_id, kind, starts_on, ends_on, note FROM driver_absences WHERE starts_on <= \$1 AND ends_on >= \$1`).
+			WithArgs(date).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(absence.ID, absence.DriverID, absence.Kind, absence.StartsOn, absence.EndsOn, absence.Note))
+
+		absences, err := repo.GetByDate(date)
+		if err != nil {
+			t.Errorf("Ошибка при получении отсутствий на дату: %v", err)
+		}
+		if len(absences) != 1 {
+			t.Errorf("Ожидалось 1 отсутствие, получено: %d", len(absences))
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+}
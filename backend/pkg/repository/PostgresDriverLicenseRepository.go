@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type PostgresDriverLicenseRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresDriverLicenseRepository(db *sql.DB) (*PostgresDriverLicenseRepository, error) {
+	repo := &PostgresDriverLicenseRepository{db: db}
+	return repo, nil
+}
+
+func (r *PostgresDriverLicenseRepository) GetByDriverId(driverId string) (*models.DriverLicense, error) {
+	license := &models.DriverLicense{}
+	err := r.db.QueryRow(`
+		SELECT driver_id, issue_date, expiry_date
+		FROM driver_licenses
+		WHERE driver_id = $1`, driverId).Scan(
+		&license.DriverID,
+		&license.IssueDate,
+		&license.ExpiryDate,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("License not found")
+		}
+		return nil, err
+	}
+	rows, err := r.db.Query(`
+		SELECT category, issue_date, expiry_date, restrictions
+		FROM driver_license_categories
+		WHERE driver_id = $1
+		ORDER BY category
+	`, driverId)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		category := &models.LicenseCategory{}
+		err := rows.Scan(
+			&category.Category,
+			&category.IssueDate,
+			&category.ExpiryDate,
+			&category.Restrictions,
+		)
+		if err != nil {
+			return nil, err
+		}
+		license.Categories = append(license.Categories, *category)
+	}
+	return license, nil
+}
+
+// Save replaces the driver's licence and all of its categories.
+func (r *PostgresDriverLicenseRepository) Save(license *models.DriverLicense) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT into driver_licenses (driver_id, issue_date, expiry_date) 
+VALUES ($1, $2, $3)
+ON CONFLICT (driver_id) DO UPDATE SET issue_date = EXCLUDED.issue_date, expiry_date = EXCLUDED.expiry_date`,
+		license.DriverID,
+		license.IssueDate,
+		license.ExpiryDate,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`DELETE FROM driver_license_categories WHERE driver_id = $1`, license.DriverID)
+	if err != nil {
+		return err
+	}
+	for _, category := range license.Categories {
+		_, err = tx.Exec(`INSERT into driver_license_categories (driver_id, category, issue_date, expiry_date, restrictions) 
+VALUES ($1, $2, $3, $4, $5)`, license.DriverID,
+			category.Category,
+			category.IssueDate,
+			category.ExpiryDate,
+			category.Restrictions,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetExpiring returns licences that expire, or have a category that expires,
+// before the given time.
+func (r *PostgresDriverLicenseRepository) GetExpiring(before time.Time) ([]models.DriverLicense, error) {
+	rows, err := r.db.Query(`
+		SELECT l.driver_id, l.issue_date, l.expiry_date, c.category, c.issue_date, c.expiry_date, c.restrictions
+		FROM driver_licenses l
+		LEFT JOIN driver_license_categories c ON c.driver_id = l.driver_id
+		WHERE l.expiry_date < $1
+		   OR l.driver_id IN (SELECT driver_id FROM driver_license_categories WHERE expiry_date < $1)
+		ORDER BY l.driver_id, c.category
+	`, before)
+	if err != nil {
+		return nil, err
+	}
+	var licenses []models.DriverLicense
+	for rows.Next() {
+		license := models.DriverLicense{}
+		var category, restrictions sql.NullString
+		var categoryIssueDate, categoryExpiryDate sql.NullTime
+		err := rows.Scan(
+			&license.DriverID,
+			&license.IssueDate,
+			&license.ExpiryDate,
+			&category,
+			&categoryIssueDate,
+			&categoryExpiryDate,
+			&restrictions,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if n := len(licenses); n == 0 || licenses[n-1].DriverID != license.DriverID {
+			licenses = append(licenses, license)
+		}
+		if category.Valid {
+			last := &licenses[len(licenses)-1]
+			last.Categories = append(last.Categories, models.LicenseCategory{
+				Category:     category.String,
+				IssueDate:    categoryIssueDate.Time,
+				ExpiryDate:   categoryExpiryDate.Time,
+				Restrictions: restrictions.String,
+			})
+		}
+	}
+	return licenses, nil
+}
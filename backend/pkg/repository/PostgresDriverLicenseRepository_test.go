@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func setupMockDriverLicense(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *PostgresDriverLicenseRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Ошибка создания mock базы данных: %v", err)
+	}
+	repo := &PostgresDriverLicenseRepository{db: db}
+	return db, mock, repo
+}
+
+func TestPostgresDriverLicenseRepository(t *testing.T) {
+	issued := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	license := &models.DriverLicense{
+		DriverID:   uuid.New().String(),
+		IssueDate:  issued,
+		ExpiryDate: issued.AddDate(10, 0, 0),
+		Categories: []models.LicenseCategory{
+			{Category: "B", IssueDate: issued, ExpiryDate: issued.AddDate(10, 0, 0), Restrictions: ""},
+			{Category: "D", IssueDate: issued, ExpiryDate: issued.AddDate(5, 0, 0), Restrictions: "AT"},
+		},
+	}
+
+	t.Run("NewPostgresDriverLicenseRepository", func(t *testing.T) {
+		db, _, _ := setupMockDriverLicense(t)
+		defer db.Close()
+
+		repo, err := NewPostgresDriverLicenseRepository(db)
+		if err != nil {
+			t.Errorf("Ошибка при создании репозитория: %v", err)
+		}
+		if repo == nil {
+			t.Error("Репозиторий не должен быть nil")
+		}
+	})
+
+	t.Run("GetByDriverId", func(t *testing.T) {
+		db, mock, repo := setupMockDriverLicense(t)
+		defer db.Close()
+
+		mock.ExpectQuery(`SELECT driver_id, issue_date, expiry_date FROM driver_licenses WHERE driver_id = \$1`).
+			WithArgs(license.DriverID).
+			WillReturnRows(sqlmock.NewRows([]string{"driver_id", "issue_date", "expiry_date"}).
+				AddRow(license.DriverID, license.IssueDate, license.ExpiryDate))
+		categories := sqlmock.NewRows([]string{"category", "issue_date", "expiry_date", "restrictions"})
+		for _, c := range license.Categories {
+			categories.AddRow(c.Category, c.IssueDate, c.ExpiryDate, c.Restrictions)
+		}
+		mock.ExpectQuery(`SELECT category, issue_date, expiry_date, restrictions FROM driver_license_categories WHERE driver_id = \$1`).
+			WithArgs(license.DriverID).
+			WillReturnRows(categories)
+
+		retrieved, err := repo.GetByDriverId(license.DriverID)
+		if err != nil {
+			t.Errorf("Ошибка при получении прав водителя: %v", err)
+		}
+		if !reflect.DeepEqual(license, retrieved) {
+			t.Errorf("Полученные права не совпадают: ожидались %v, получены %v", license, retrieved)
+		}
+
+		mock.ExpectQuery(`SELECT driver_id, issue_date, expiry_date FROM driver_licenses WHERE driver_id = \$1`).
+			WithArgs("nonexistent").
+			WillReturnError(sql.ErrNoRows)
+
+		_, err = repo.GetByDriverId("nonexistent")
+		if err == nil || err.Error() != "License not found" {
+			t.Errorf("Ожидалась ошибка 'License not found', получена: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("Save", func(t *testing.T) {
+		db, mock, repo := setupMockDriverLicense(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT into driver_licenses \(driver_id, issue_date, expiry_date\) VALUES \(\$1, \$2, \$3\) ON CONFLICT \(driver_id\) DO UPDATE`).
+			WithArgs(license.DriverID, license.IssueDate, license.ExpiryDate).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`DELETE FROM driver_license_categories WHERE driver_id = \$1`).
+			WithArgs(license.DriverID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		for _, c := range license.Categories {
+			mock.ExpectExec(`INSERT into driver_license_categories \(driver_id, category, issue_date, expiry_date, restrictions\) VALUES \(\$1, \$2, \$3, \$4, \$5\)`).
+				WithArgs(license.DriverID, c.Category, c.IssueDate, c.ExpiryDate, c.Restrictions).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+		}
+		mock.ExpectCommit()
+
+		err := repo.Save(license)
+		if err != nil {
+			t.Errorf("Ошибка при сохранении прав водителя: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("GetExpiring", func(t *testing.T) {
+		db, mock, repo := setupMockDriverLicense(t)
+		defer db.Close()
+
+		before := issued.AddDate(6, 0, 0)
+		otherID := uuid.New().String()
+		rows := sqlmock.NewRows([]string{"driver_id", "issue_date", "expiry_date", "category", "issue_date", "expiry_date", "restrictions"})
+		for _, c := range license.Categories {
+			rows.AddRow(license.DriverID, license.IssueDate, license.ExpiryDate, c.Category, c.IssueDate, c.ExpiryDate, c.Restrictions)
+		}
+		rows.AddRow(otherID, issued, issued.AddDate(5, 0, 0), nil, nil, nil, nil)
+		mock.ExpectQuery(`SELECT l\.driver_id, l\.issue_date, l\.expiry_date, c\.category, c\.issue_date, c\.expiry_date, c\.restrictions FROM driver_licenses l`).
+			WithArgs(before).
+			WillReturnRows(rows)
+
+		licenses, err := repo.GetExpiring(before)
+		if err != nil {
+			t.Errorf("Ошибка при получении истекающих прав: %v", err)
+		}
+		if len(licenses) != 2 || !reflect.DeepEqual(*license, licenses[0]) || licenses[1].DriverID != otherID || len(licenses[1].Categories) != 0 {
+			t.Errorf("Неверный список истекающих прав: %v", licenses)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+}
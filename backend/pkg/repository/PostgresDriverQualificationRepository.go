@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"errors"
+)
+
+type PostgresDriverQualificationRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresDriverQualificationRepository(db *sql.DB) (*PostgresDriverQualificationRepository, error) {
+	repo := &PostgresDriverQualificationRepository{db: db}
+	return repo, nil
+}
+
+// Save adds the qualification or updates the training date and trainer of
+// an existing one.
+func (r *PostgresDriverQualificationRepository) Save(qualification *models.DriverQualification) error {
+	_, err := r.db.Exec(`INSERT into driver_qualifications (driver_id, brand, bus_model, trained_on, trainer) 
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (driver_id, brand, bus_model) DO UPDATE SET trained_on = EXCLUDED.trained_on, trainer = EXCLUDED.trainer`,
+		&qualification.DriverID,
+		&qualification.Brand,
+		&qualification.BusModel,
+		&qualification.TrainedOn,
+		&qualification.Trainer,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *PostgresDriverQualificationRepository) Delete(driverId, brand, busModel string) error {
+	result, err := r.db.Exec("DELETE FROM driver_qualifications WHERE driver_id = $1 AND brand = $2 AND bus_model = $3",
+		driverId, brand, busModel)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("Qualification not found")
+	}
+	return nil
+}
+
+func (r *PostgresDriverQualificationRepository) GetByDriverId(driverId string) ([]models.DriverQualification, error) {
+	rows, err := r.db.Query(`
+		SELECT driver_id, brand, bus_model, trained_on, trainer
+		FROM driver_qualifications
+		WHERE driver_id = $1
+		ORDER BY brand, bus_model
+	`, driverId)
+	if err != nil {
+		return nil, err
+	}
+	return scanDriverQualifications(rows)
+}
+
+// GetByBusModel returns qualifications for the model, ignoring case.
+func (r *PostgresDriverQualificationRepository) GetByBusModel(brand, busModel string) ([]models.DriverQualification, error) {
+	rows, err := r.db.Query(`
+		SELECT driver_id, brand, bus_model, trained_on, trainer
+		FROM driver_qualifications
+		WHERE lower(brand) = lower($1) AND lower(bus_model) = lower($2)
+	`, brand, busModel)
+	if err != nil {
+		return nil, err
+	}
+	return scanDriverQualifications(rows)
+}
+
+func (r *PostgresDriverQualificationRepository) GetAll() ([]models.DriverQualification, error) {
+	rows, err := r.db.Query(`
+		SELECT driver_id, brand, bus_model, trained_on, trainer
+		FROM driver_qualifications
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return scanDriverQualifications(rows)
+}
+
+func scanDriverQualifications(rows *sql.Rows) ([]models.DriverQualification, error) {
+	var qualifications []models.DriverQualification
+	for rows.Next() {
+		qualification := &models.DriverQualification{}
+		err := rows.Scan(
+			&qualification.DriverID,
+			&qualification.Brand,
+			&qualification.BusModel,
+			&qualification.TrainedOn,
+			&qualification.Trainer,
+		)
+		if err != nil {
+			return nil, err
+		}
+		qualifications = append(qualifications, *qualification)
+	}
+	return qualifications, nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func setupMockDriverQualification(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *PostgresDriverQualificationRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Ошибка создания mock базы данных: %v", err)
+	}
+	repo := &PostgresDriverQualificationRepository{db: db}
+	return db, mock, repo
+}
+
+func newTestDriverQualification() *models.DriverQualification {
+	return &models.DriverQualification{
+		DriverID:  uuid.New().String(),
+		Brand:     "ЛиАЗ",
+		BusModel:  "5292",
+		TrainedOn: time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC),
+		Trainer:   "Сидоров И.П.",
+	}
+}
+
+func TestPostgresDriverQualificationRepository(t *testing.T) {
+	columns := []string{"driver_id", "brand", "bus_model", "trained_on", "trainer"}
+
+	t.Run("NewPostgresDriverQualificationRepository", func(t *testing.T) {
+		db, _, _ := setupMockDriverQualification(t)
+		defer db.Close()
+
+		repo, err := NewPostgresDriverQualificationRepository(db)
+		if err != nil {
+			t.Errorf("Ошибка при создании репозитория: %v", err)
+		}
+		if repo == nil {
+			t.Error("Репозиторий не должен быть nil")
+		}
+	})
+
+	t.Run("Save", func(t *testing.T) {
+		db, mock, repo := setupMockDriverQualification(t)
+		defer db.Close()
+
+		qualification := newTestDriverQualification()
+		mock.ExpectExec(`INSERT into driver_qualifications \(driver_id, brand, bus_model, trained_on, trainer\) VALUES \(\$1, \$2, \$3, \$4, \$5\) ON CONFLICT \(driver_id, brand, bus_model\) DO UPDATE`).
+			WithArgs(qualification.DriverID, qualification.Brand, qualification.BusModel, qualification.TrainedOn, qualification.Trainer).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.Save(qualification)
+		if err != nil {
+			t.Errorf("Ошибка при сохранении допуска: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		db, mock, repo := setupMockDriverQualification(t)
+		defer db.Close()
+
+		qualification := newTestDriverQualification()
+		mock.ExpectExec(`DELETE FROM driver_qualifications WHERE driver_id = \$1 AND brand = \$2 AND bus_model = \$3`).
+			WithArgs(qualification.DriverID, qualification.Brand, qualification.BusModel).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.Delete(qualification.DriverID, qualification.Brand, qualification.BusModel)
+		if err != nil {
+			t.Errorf("Ошибка при удалении допуска: %v", err)
+		}
+
+		mock.ExpectExec(`DELETE FROM driver_qualifications WHERE driver_id = \$1 AND brand = \$2 AND bus_model = \$3`).
+			WithArgs(qualification.DriverID, "ПАЗ", "3205").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err = repo.Delete(qualification.DriverID, "ПАЗ", "3205")
+		if err == nil || err.Error() != "Qualification not found" {
+			t.Errorf("Ожидалась ошибка 'Qualification not found', получена: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("GetByBusModel", func(t *testing.T) {
+		db, mock, repo := setupMockDriverQualification(t)
+		defer db.Close()
+
+		qualification := newTestDriverQualification()
+		mock.ExpectQuery(`SELECT driver_id, brand, bus_model, trained_on, trainer FROM driver_qualifications WHERE lower\(brand\) = lower\(\$1\) AND lower\(bus_model\) = lower\(\$2\)`).
+			WithArgs("лиаз", "5292").
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(qualification.DriverID, qualification.Brand, qualification.BusModel, qualification.TrainedOn, qualification.Trainer))
+
+		qualifications, err := repo.GetByBusModel("лиаз", "5292")
+		if err != nil {
+			t.Errorf("Ошибка при получении допусков к модели: %v", err)
+		}
+		if len(qualifications) != 1 || !reflect.DeepEqual(*qualification, qualifications[0]) {
+			t.Errorf("Ожидался 1 допуск %v, получено: %v", qualification, qualifications)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("GetByDriverId", func(t *testing.T) {
+		db, mock, repo := setupMockDriverQualification(t)
+		defer db.Close()
+
+		qualification := newTestDriverQualification()
+		mock.ExpectQuery(`SELECT driver_id, brand, bus_model, trained_on, trainer FROM driver_qualifications WHERE driver_id = \$1`).
+			WithArgs(qualification.DriverID).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(qualification.DriverID, qualification.Brand, qualification.BusModel, qualification.TrainedOn, qualification.Trainer))
+
+		qualifications, err := repo.GetByDriverId(qualification.DriverID)
+		if err != nil {
+			t.Errorf("Ошибка при получении допусков водителя: %v", err)
+		}
+		if len(qualifications) != 1 {
+			t.Errorf("Ожидался 1 допуск, получено: %d", len(qualifications))
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+}
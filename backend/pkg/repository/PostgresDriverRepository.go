@@ -136,11 +136,24 @@ func (r *PostgresDriverRepository) DeleteById(id string) error {
 	if err != nil {
 		return err
 	}
-	_, err = r.db.Exec("DELETE FROM drivers WHERE id = $1", id)
+	tx, err := r.db.Begin()
 	if err != nil {
 		return err
 	}
-	return nil
+	defer tx.Rollback()
+	_, err = tx.Exec("DELETE FROM driver_license_categories WHERE driver_id = $1", id)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM driver_licenses WHERE driver_id = $1", id)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM drivers WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *PostgresDriverRepository) UpdateById(driver *models.Driver) error {
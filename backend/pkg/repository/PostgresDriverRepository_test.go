@@ -241,9 +241,17 @@ func TestPostgresDriverRepository(t *testing.T) {
 			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "surname", "patronymic", "birth_date", "passport_series", "snils", "license_series"}).
 				AddRow(driverID, "Иван", "Иванов", "Иванович", time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC), "1234 567890", "123-456-789 01", "AB1234567"))
 
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM driver_license_categories WHERE driver_id = \$1`).
+			WithArgs(driverID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`DELETE FROM driver_licenses WHERE driver_id = \$1`).
+			WithArgs(driverID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectExec(`DELETE FROM drivers WHERE id = \$1`).
 			WithArgs(driverID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
 
 		err := repo.DeleteById(driverID)
 		if err != nil {
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+type PostgresDutyRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresDutyRepository(db *sql.DB) (*PostgresDutyRepository, error) {
+	repo := &PostgresDutyRepository{db: db}
+	return repo, nil
+}
+
+func (r *PostgresDutyRepository) GetById(id string) (*models.Duty, error) {
+	duty := &models.Duty{}
+	err := r.db.QueryRow(`
+		SELECT id, date, driver_id, bus_id, route_id, shift_start, shift_end, break_minutes, compliance_override, started_at
+		FROM duties 
+		WHERE id = $1`, id).Scan(
+		&duty.ID,
+		&duty.Date,
+		&duty.DriverID,
+		&duty.BusID,
+		&duty.RouteID,
+		&duty.ShiftStart,
+		&duty.ShiftEnd,
+		&duty.BreakMinutes,
+		&duty.ComplianceOverride,
+		&duty.StartedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("Duty not found")
+		}
+		return nil, err
+	}
+
+	return duty, nil
+}
+
+func (r *PostgresDutyRepository) Add(duty *models.Duty) error {
+	if strings.TrimSpace(duty.ID) == "" {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return err
+		}
+		duty.ID = id.String()
+	}
+	_, err := r.db.Exec(`INSERT into duties (id, date, driver_id, bus_id, route_id, shift_start, shift_end, break_minutes, compliance_override) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`, &duty.ID,
+		&duty.Date,
+		&duty.DriverID,
+		&duty.BusID,
+		&duty.RouteID,
+		&duty.ShiftStart,
+		&duty.ShiftEnd,
+		&duty.BreakMinutes,
+		&duty.ComplianceOverride,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *PostgresDutyRepository) DeleteById(id string) error {
+	exist, err := r.GetById(id)
+	if exist == nil {
+		return errors.New("Duty not found")
+	}
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec("DELETE FROM duties WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *PostgresDutyRepository) UpdateById(duty *models.Duty) error {
+	exist, err := r.GetById(duty.ID)
+	if exist == nil {
+		return errors.New("Duty not found")
+	}
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec("UPDATE duties SET date = $1, driver_id = $2, bus_id = $3, route_id = $4, shift_start = $5, shift_end = $6, break_minutes = $7, compliance_override = $8 WHERE id = $9",
+		duty.Date, duty.DriverID, duty.BusID, duty.RouteID, duty.ShiftStart, duty.ShiftEnd, duty.BreakMinutes, duty.ComplianceOverride, duty.ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *PostgresDutyRepository) SetStarted(id string, startedAt time.Time) error {
+	_, err := r.db.Exec("UPDATE duties SET started_at = $1 WHERE id = $2", startedAt, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetByPeriod returns duties whose date falls in [from, to).
+func (r *PostgresDutyRepository) GetByPeriod(from, to time.Time) ([]models.Duty, error) {
+	rows, err := r.db.Query(`
+		SELECT id, date, driver_id, bus_id, route_id, shift_start, shift_end, break_minutes, compliance_override, started_at
+		FROM duties
+		WHERE date >= $1 AND date < $2
+		ORDER BY date, shift_start
+	`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return scanDuties(rows)
+}
+
+// GetByDriverId returns the driver's duties whose date falls in [from, to).
+func (r *PostgresDutyRepository) GetByDriverId(driverId string, from, to time.Time) ([]models.Duty, error) {
+	rows, err := r.db.Query(`
+		SELECT id, date, driver_id, bus_id, route_id, shift_start, shift_end, break_minutes, compliance_override, started_at
+		FROM duties
+		WHERE driver_id = $1 AND date >= $2 AND date < $3
+		ORDER BY shift_start
+	`, driverId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return scanDuties(rows)
+}
+
+// GetOverlapping returns duties of the driver or the bus whose shift window
+// intersects [shiftStart, shiftEnd).
+func (r *PostgresDutyRepository) GetOverlapping(driverId, busId string, shiftStart, shiftEnd time.Time) ([]models.Duty, error) {
+	rows, err := r.db.Query(`
+		SELECT id, date, driver_id, bus_id, route_id, shift_start, shift_end, break_minutes, compliance_override, started_at
+		FROM duties
+		WHERE (driver_id = $1 OR bus_id = $2) AND shift_start < $4 AND shift_end > $3
+		ORDER BY shift_start
+	`, driverId, busId, shiftStart, shiftEnd)
+	if err != nil {
+		return nil, err
+	}
+	return scanDuties(rows)
+}
+
+func scanDuties(rows *sql.Rows) ([]models.Duty, error) {
+	var duties []models.Duty
+	for rows.Next() {
+		duty := &models.Duty{}
+		err := rows.Scan(
+			&duty.ID,
+			&duty.Date,
+			&duty.DriverID,
+			&duty.BusID,
+			&duty.RouteID,
+			&duty.ShiftStart,
+			&duty.ShiftEnd,
+			&duty.BreakMinutes,
+			&duty.ComplianceOverride,
+			&duty.StartedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		duties = append(duties, *duty)
+	}
+	return duties, nil
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func setupMockDuty(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *PostgresDutyRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Ошибка создания mock базы данных: %v", err)
+	}
+	repo := &PostgresDutyRepository{db: db}
+	return db, mock, repo
+}
+
+func newTestDuty() *models.Duty {
+	shiftStart := time.Date(2025, 3, 3, 6, 0, 0, 0, time.UTC)
+	return &models.Duty{
+		ID:           uuid.New().String(),
+		Date:         time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC),
+		DriverID:     uuid.New().String(),
+		BusID:        uuid.New().String(),
+		RouteID:      uuid.New().String(),
+		ShiftStart:   shiftStart,
+		ShiftEnd:     shiftStart.Add(8 * time.Hour),
+		BreakMinutes: 45,
+	}
+}
+
+func TestPostgresDutyRepository(t *testing.T) {
+	columns := []string{"id", "date", "driver_id", "bus_id", "route_id", "shift_start", "shift_end", "break_minutes", "compliance_override", "started_at"}
+
+	t.Run("NewPostgresDutyRepository", func(t *testing.T) {
+		db, _, _ := setupMockDuty(t)
+		defer db.Close()
+
+		repo, err := NewPostgresDutyRepository(db)
+		if err != nil {
+			t.Errorf("Ошибка при создании репозитория: %v", err)
+		}
+		if repo == nil {
+			t.Error("Репозиторий не должен быть nil")
+		}
+	})
+
+	t.Run("GetById", func(t *testing.T) {
+		db, mock, repo := setupMockDuty(t)
+		defer db.Close()
+
+		duty := newTestDuty()
+		rows := sqlmock.NewRows(columns).
+			AddRow(duty.ID, duty.Date, duty.DriverID, duty.BusID, duty.RouteID, duty.ShiftStart, duty.ShiftEnd, duty.BreakMinutes, duty.ComplianceOverride, duty.StartedAt)
+		mock.ExpectQuery(`SELECT id, date, driver_id, bus_id, route_id, shift_start, shift_end, break_minutes, compliance_override, started_at FROM duties WHERE id = \$1`).
+			WithArgs(duty.ID).
+			WillReturnRows(rows)
+
+		retrieved, err := repo.GetById(duty.ID)
+		if err != nil {
+			t.Errorf("Ошибка при получении смены по ID: %v", err)
+		}
+		if !reflect.DeepEqual(duty, retrieved) {
+			t.Errorf("Полученная смена не совпадает: ожидалась %v, получена %v", duty, retrieved)
+		}
+
+		mock.ExpectQuery(`SELECT id, date, driver_id, bus_id, route_id, shift_start, shift_end, break_minutes, compliance_override, started_at FROM duties WHERE id = \$1`).
+			WithArgs("nonexistent").
+			WillReturnError(sql.ErrNoRows)
+
+		_, err = repo.GetById("nonexistent")
+		if err == nil || err.Error() != "Duty not found" {
+			t.Errorf("Ожидалась ошибка 'Duty not found', получена: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("Add", func(t *testing.T) {
+		db, mock, repo := setupMockDuty(t)
+		defer db.Close()
+
+		duty := newTestDuty()
+		duty.ID = ""
+		mock.ExpectExec(`INSERT into duties \(id, date, driver_id, bus_id, route_id, shift_start, shift_end, break_minutes, compliance_override\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9\)`).
+			WithArgs(sqlmock.AnyArg(), duty.Date, duty.DriverID, duty.BusID, duty.RouteID, duty.ShiftStart, duty.ShiftEnd, duty.BreakMinutes, duty.ComplianceOverride).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.Add(duty)
+		if err != nil {
+			t.Errorf("Ошибка при добавлении смены: %v", err)
+		}
+		if duty.ID == "" {
+			t.Error("ID смены должен быть сгенерирован")
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("DeleteById", func(t *testing.T) {
+		db, mock, repo := setupMockDuty(t)
+		defer db.Close()
+
+		duty := newTestDuty()
+		mock.ExpectQuery(`SELECT id, date, driver_id, bus_id, route_id, shift_start, shift_end, break_minutes, compliance_override, started_at FROM duties WHERE id = \$1`).
+			WithArgs(duty.ID).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(duty.ID, duty.Date, duty.DriverID, duty.BusID, duty.RouteID, duty.ShiftStart, duty.ShiftEnd, duty.BreakMinutes, duty.ComplianceOverride, duty.StartedAt))
+		mock.ExpectExec(`DELETE FROM duties WHERE id = \$1`).
+			WithArgs(duty.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.DeleteById(duty.ID)
+		if err != nil {
+			t.Errorf("Ошибка при удалении смены: %v", err)
+		}
+
+		mock.ExpectQuery(`SELECT id, date, driver_id, bus_id, route_id, shift_start, shift_end, break_minutes, compliance_override, started_at FROM duties WHERE id = \$1`).
+			WithArgs("nonexistent").
+			WillReturnError(sql.ErrNoRows)
+
+		err = repo.DeleteById("nonexistent")
+		if err == nil || err.Error() != "Duty not found" {
+			t.Errorf("Ожидалась ошибка 'Duty not found', получена: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("UpdateById", func(t *testing.T) {
+		db, mock, repo := setupMockDuty(t)
+		defer db.Close()
+
+		duty := newTestDuty()
+		mock.ExpectQuery(`SELECT id, date, driver_id, bus_id, route_id, shift_start, shift_end, break_minutes, compliance_override, started_at FROM duties WHERE id = \$1`).
+			WithArgs(duty.ID).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(duty.ID, duty.Date, duty.DriverID, duty.BusID, duty.RouteID, duty.ShiftStart, duty.ShiftEnd, duty.BreakMinutes, duty.ComplianceOverride, duty.StartedAt))
+		mock.ExpectExec(`UPDATE duties SET date = \$1, driver_id = \$2, bus_id = \$3, route_id = \$4, shift_start = \$5, shift_end = \$6, break_minutes = \$7, compliance_override = \$8 WHERE id = \$9`).
+			WithArgs(duty.Date, duty.DriverID, duty.BusID, duty.RouteID, duty.ShiftStart, duty.ShiftEnd, duty.BreakMinutes, duty.ComplianceOverride, duty.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.UpdateById(duty)
+		if err != nil {
+			t.Errorf("Ошибка при обновлении смены: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("SetStarted", func(t *testing.T) {
+		db, mock, repo := setupMockDuty(t)
+		defer db.Close()
+
+		duty := newTestDuty()
+		startedAt := duty.ShiftStart.Add(-10 * time.Minute)
+		mock.ExpectExec(`UPDATE duties SET started_at = \$1 WHERE id = \$2`).
+			WithArgs(startedAt, duty.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.SetStarted(duty.ID, startedAt)
+		if err != nil {
+			t.Errorf("Ошибка при отметке начала смены: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("GetByPeriod", func(t *testing.T) {
+		db, mock, repo := setupMockDuty(t)
+		defer db.Close()
+
+		duty := newTestDuty()
+		from := duty.Date
+		to := from.AddDate(0, 0, 1)
+		mock.ExpectQuery(`SELECT id, date, driver_id, bus_id, route_id, shift_start, shift_end, break_minutes, compliance_override, started_at FROM duties WHERE date >= \$1 AND date < \$2`).
+			WithArgs(from, to).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(duty.ID, duty.Date, duty.DriverID, duty.BusID, duty.RouteID, duty.ShiftStart, duty.ShiftEnd, duty.BreakMinutes, duty.ComplianceOverride, duty.StartedAt))
+
+		duties, err := repo.GetByPeriod(from, to)
+		if err != nil {
+			t.Errorf("Ошибка при получении смен за период: %v", err)
+		}
+		if !reflect.DeepEqual([]models.Duty{*duty}, duties) {
+			t.Errorf("Полученные смены не совпадают: ожидалось %v, получено %v", []models.Duty{*duty}, duties)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("GetByDriverId", func(t *testing.T) {
+		db, mock, repo := setupMockDuty(t)
+		defer db.Close()
+
+		duty := newTestDuty()
+		from := duty.Date
+		to := from.AddDate(0, 0, 7)
+		mock.ExpectQuery(`SELECT id, date, driver_id, bus_id, route_id, shift_start, shift_end, break_minutes, compliance_override, started_at FROM duties WHERE driver_id = \$1 AND date >= \$2 AND date < \$3`).
+			WithArgs(duty.DriverID, from, to).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(duty.ID, duty.Date, duty.DriverID, duty.BusID, duty.RouteID, duty.ShiftStart, duty.ShiftEnd, duty.BreakMinutes, duty.ComplianceOverride, duty.StartedAt))
+
+		duties, err := repo.GetByDriverId(duty.DriverID, from, to)
+		if err != nil {
+			t.Errorf("Ошибка при получении смен водителя: %v", err)
+		}
+		if !reflect.DeepEqual([]models.Duty{*duty}, duties) {
+			t.Errorf("Полученные смены не совпадают: ожидалось %v, получено %v", []models.Duty{*duty}, duties)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("GetOverlapping", func(t *testing.T) {
+		db, mock, repo := setupMockDuty(t)
+		defer db.Close()
+
+		duty := newTestDuty()
+		mock.ExpectQuery(`SELECT id, date, driver_id, bus_id, route_id, shift_start, shift_end, break_minutes, compliance_override, started_at FROM duties WHERE \(driver_id = \$1 OR bus_id = \$2\) AND shift_start < \$4 AND shift_end > \$3`).
+			WithArgs(duty.DriverID, duty.BusID, duty.ShiftStart, duty.ShiftEnd).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		duties, err := repo.GetOverlapping(duty.DriverID, duty.BusID, duty.ShiftStart, duty.ShiftEnd)
+		if err != nil {
+			t.Errorf("Ошибка при поиске пересекающихся смен: %v", err)
+		}
+		if len(duties) != 0 {
+			t.Errorf("Ожидалось 0 смен, получено %d", len(duties))
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+type PostgresIncidentRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresIncidentRepository(db *sql.DB) (*PostgresIncidentRepository, error) {
+	repo := &PostgresIncidentRepository{db: db}
+	return repo, nil
+}
+
+func (r *PostgresIncidentRepository) GetById(id string) (*models.Incident, error) {
+	incident := &models.Incident{}
+	err := r.db.QueryRow(`
+		SELECT id, type, severity, status, occurred_at, location, lat, long, bus_id, driver_id, route_id, description, reported_by, resolution, closed_at
+		FROM incidents
+		WHERE id = $1`, id).Scan(
+		&incident.ID,
+		&incident.Type,
+		&incident.Severity,
+		&incident.Status,
+		&incident.OccurredAt,
+		&incident.Location,
+		&incident.Lat,
+		&incident.Long,
+		&incident.BusID,
+		&incident.DriverID,
+		&incident.RouteID,
+		&incident.Description,
+		&incident.ReportedBy,
+		&incident.Resolution,
+		&incident.ClosedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("Incident not found")
+		}
+		return nil, err
+	}
+
+	return incident, nil
+}
+
+func (r *PostgresIncidentRepository) Add(incident *models.Incident) error {
+	if strings.TrimSpace(incident.ID) == "" {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return err
+		}
+		incident.ID = id.String()
+	}
+	_, err := r.db.Exec(`INSERT into incidents (id, type, severity, status, occurred_at, location, lat, long, bus_id, driver_id, route_id, description, reported_by, resolution, closed_at) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`,
+		incident.ID,
+		incident.Type,
+		incident.Severity,
+		incident.Status,
+		incident.OccurredAt,
+		incident.Location,
+		incident.Lat,
+		incident.Long,
+		incident.BusID,
+		incident.DriverID,
+		incident.RouteID,
+		incident.Description,
+		incident.ReportedBy,
+		incident.Resolution,
+		incident.ClosedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetStatus moves the incident from the expected status to the new one. It
+// fails if someone else changed the status since the caller read it.
+func (r *PostgresIncidentRepository) SetStatus(id, expected, status, resolution string, closedAt *time.Time) error {
+	result, err := r.db.Exec(`UPDATE incidents SET status = $1, resolution = $2, closed_at = $3 WHERE id = $4 AND status = $5`,
+		status, resolution, closedAt, id, expected)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected > 0 {
+		return nil
+	}
+	var exists bool
+	err = r.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM incidents WHERE id = $1)`, id).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.New("Incident not found")
+	}
+	return fmt.Errorf("Incident is no longer %s, reload it and try again", expected)
+}
+
+// Find returns the incidents matching the filter, newest first. The period
+// is [From, To).
+func (r *PostgresIncidentRepository) Find(filter models.IncidentFilter) ([]models.Incident, error) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if !filter.From.IsZero() {
+		add("occurred_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		add("occurred_at < $%d", filter.To)
+	}
+	if filter.BusID != "" {
+		add("bus_id = $%d", filter.BusID)
+	}
+	if filter.DriverID != "" {
+		add("driver_id = $%d", filter.DriverID)
+	}
+	if filter.RouteID != "" {
+		add("route_id = $%d", filter.RouteID)
+	}
+	if filter.Type != "" {
+		add("type = $%d", filter.Type)
+	}
+	if filter.Status != "" {
+		add("status = $%d", filter.Status)
+	}
+	query := `
+		SELECT id, type, severity, status, occurred_at, location, lat, long, bus_id, driver_id, route_id, description, reported_by, resolution, closed_at
+		FROM incidents`
+	if len(conditions) > 0 {
+		query += `
+		WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += `
+		ORDER BY occurred_at DESC`
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanIncidents(rows)
+}
+
+func scanIncidents(rows *sql.Rows) ([]models.Incident, error) {
+	var incidents []models.Incident
+	for rows.Next() {
+		incident := &models.Incident{}
+		err := rows.Scan(
+			&incident.ID,
+			&incident.Type,
+			&incident.Severity,
+			&incident.Status,
+			&incident.OccurredAt,
+			&incident.Location,
+			&incident.Lat,
+			&incident.Long,
+			&incident.BusID,
+			&incident.DriverID,
+			&incident.RouteID,
+			&incident.Description,
+			&incident.ReportedBy,
+			&incident.Resolution,
+			&incident.ClosedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		incidents = append(incidents, *incident)
+	}
+	return incidents, nil
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"database/sql/driver"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func setupMockIncident(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *PostgresIncidentRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Ошибка создания mock базы данных: %v", err)
+	}
+	repo := &PostgresIncidentRepository{db: db}
+	return db, mock, repo
+}
+
+func newTestIncident() *models.Incident {
+	lat, long := 55.7558, 37.6173
+	return &models.Incident{
+		ID:          uuid.New().String(),
+		Type:        models.IncidentAccident,
+		Severity:    models.SeverityMedium,
+		Status:      models.IncidentOpen,
+		OccurredAt:  time.Date(2025, 3, 3, 8, 15, 0, 0, time.UTC),
+		Location:    "Тверская улица, 7",
+		Lat:         &lat,
+		Long:        &long,
+		BusID:       uuid.New().String(),
+		DriverID:    uuid.New().String(),
+		RouteID:     "",
+		Description: "Столкновение с легковым автомобилем",
+		ReportedBy:  uuid.New().String(),
+	}
+}
+
+func incidentRow(incident *models.Incident) []driver.Value {
+	return []driver.Value{incident.ID, incident.Type, incident.Severity, incident.Status, incident.OccurredAt, incident.Location,
+		*incident.Lat, *incident.Long, incident.BusID, incident.DriverID, incident.RouteID, incident.Description,
+		incident.ReportedBy, incident.Resolution, nil}
+}
+
+func TestPostgresIncidentRepository(t *testing.T) {
+	columns := []string{"id", "type", "severity", "status", "occurred_at", "location", "lat", "long", "bus_id", "driver_id", "route_id", "description", "reported_by", "resolution", "closed_at"}
+	selectQuery := `SELECT id, type, severity, status, occurred_at, location, lat, long, bus_id, driver_id, route_id, description, reported_by, resolution, closed_at FROM incidents`
+
+	t.Run("NewPostgresIncidentRepository", func(t *testing.T) {
+		db, _, _ := setupMockIncident(t)
+		defer db.Close()
+
+		repo, err := NewPostgresIncidentRepository(db)
+		if err != nil {
+			t.Errorf("Ошибка при создании репозитория: %v", err)
+		}
+		if repo == nil {
+			t.Error("Репозиторий не должен быть nil")
+		}
+	})
+
+	t.Run("GetById", func(t *testing.T) {
+		db, mock, repo := setupMockIncident(t)
+		defer db.Close()
+
+		incident := newTestIncident()
+		mock.ExpectQuery(selectQuery + ` WHERE id = \$1`).
+			WithArgs(incident.ID).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(incidentRow(incident)...))
+
+		retrieved, err := repo.GetById(incident.ID)
+		if err != nil {
+			t.Errorf("Ошибка при получении происшествия по ID: %v", err)
+		}
+		if !reflect.DeepEqual(incident, retrieved) {
+			t.Errorf("Полученное происшествие не совпадает: ожидалось %v, получено %v", incident, retrieved)
+		}
+
+		mock.ExpectQuery(selectQuery + ` WHERE id = \$1`).
+			WithArgs("nonexistent").
+			WillReturnError(sql.ErrNoRows)
+
+		_, err = repo.GetById("nonexistent")
+		if err == nil || err.Error() != "Incident not found" {
+			t.Errorf("Ожидалась ошибка 'Incident not found', получена: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("Add", func(t *testing.T) {
+		db, mock, repo := setupMockIncident(t)
+		defer db.Close()
+
+		incident := newTestIncident()
+		incident.ID = ""
+		mock.ExpectExec(`INSERT into incidents \(id, type, severity, status, occurred_at, location, lat, long, bus_id, driver_id, route_id, description, reported_by, resolution, closed_at\)`).
+			WithArgs(sqlmock.AnyArg(), incident.Type, incident.Severity, incident.Status, incident.OccurredAt, incident.Location,
+				incident.Lat, incident.Long, incident.BusID, incident.DriverID, incident.RouteID, incident.Description,
+				incident.ReportedBy, incident.Resolution, incident.ClosedAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.Add(incident)
+		if err != nil {
+			t.Errorf("Ошибка при добавлении происшествия: %v", err)
+		}
+		if incident.ID == "" {
+			t.Error("ID происшествия должен быть сгенерирован")
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("SetStatus", func(t *testing.T) {
+		db, mock, repo := setupMockIncident(t)
+		defer db.Close()
+
+		closedAt := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+		mock.ExpectExec(`UPDATE incidents SET status = \$1, resolution = \$2, closed_at = \$3 WHERE id = \$4 AND status = \$5`).
+			WithArgs(models.IncidentClosed, "Ремонт выполнен", &closedAt, "incident-1", models.IncidentInvestigating).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE incidents SET status = \$1, resolution = \$2, closed_at = \$3 WHERE id = \$4 AND status = \$5`).
+			WithArgs(models.IncidentInvestigating, "", nil, "incident-1", models.IncidentOpen).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM incidents WHERE id = \$1\)`).
+			WithArgs("incident-1").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectExec(`UPDATE incidents SET status = \$1, resolution = \$2, closed_at = \$3 WHERE id = \$4 AND status = \$5`).
+			WithArgs(models.IncidentInvestigating, "", nil, "nonexistent", models.IncidentOpen).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM incidents WHERE id = \$1\)`).
+			WithArgs("nonexistent").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		err := repo.SetStatus("incident-1", models.IncidentInvestigating, models.IncidentClosed, "Ремонт выполнен", &closedAt)
+		if err != nil {
+			t.Errorf("Ошибка при изменении статуса: %v", err)
+		}
+		err = repo.SetStatus("incident-1", models.IncidentOpen, models.IncidentInvestigating, "", nil)
+		if err == nil || err.Error() != "Incident is no longer open, reload it and try again" {
+			t.Errorf("Ожидалась ошибка конфликта статуса, получена: %v", err)
+		}
+		err = repo.SetStatus("nonexistent", models.IncidentOpen, models.IncidentInvestigating, "", nil)
+		if err == nil || err.Error() != "Incident not found" {
+			t.Errorf("Ожидалась ошибка 'Incident not found', получена: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		db, mock, repo := setupMockIncident(t)
+		defer db.Close()
+
+		incident := newTestIncident()
+		from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(0, 1, 0)
+		mock.ExpectQuery(selectQuery+` WHERE occurred_at >= \$1 AND occurred_at < \$2 AND bus_id = \$3 AND status = \$4 ORDER BY occurred_at DESC`).
+			WithArgs(from, to, incident.BusID, models.IncidentOpen).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(incidentRow(incident)...))
+		mock.ExpectQuery(selectQuery + ` ORDER BY occurred_at DESC`).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		incidents, err := repo.Find(models.IncidentFilter{From: from, To: to, BusID: incident.BusID, Status: models.IncidentOpen})
+		if err != nil {
+			t.Errorf("Ошибка при поиске происшествий: %v", err)
+		}
+		if len(incidents) != 1 || !reflect.DeepEqual(*incident, incidents[0]) {
+			t.Errorf("Найденные происшествия не совпадают: получено %v", incidents)
+		}
+		incidents, err = repo.Find(models.IncidentFilter{})
+		if err != nil || len(incidents) != 0 {
+			t.Errorf("Ожидался пустой список, получено %v, ошибка %v", incidents, err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+type PostgresMedicalCheckRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresMedicalCheckRepository(db *sql.DB) (*PostgresMedicalCheckRepository, error) {
+	repo := &PostgresMedicalCheckRepository{db: db}
+	return repo, nil
+}
+
+func (r *PostgresMedicalCheckRepository) GetById(id string) (*models.MedicalCheck, error) {
+	check := &models.MedicalCheck{}
+	err := r.db.QueryRow(`
+		SELECT id, driver_id, checked_at, passed, systolic, diastolic, alcohol, examiner
+		FROM medical_checks
+		WHERE id = $1`, id).Scan(
+		&check.ID,
+		&check.DriverID,
+		&check.CheckedAt,
+		&check.Passed,
+		&check.Systolic,
+		&check.Diastolic,
+		&check.Alcohol,
+		&check.Examiner,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("Medical check not found")
+		}
+		return nil, err
+	}
+
+	return check, nil
+}
+
+func (r *PostgresMedicalCheckRepository) Add(check *models.MedicalCheck) error {
+	if strings.TrimSpace(check.ID) == "" {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return err
+		}
+		check.ID = id.String()
+	}
+	_, err := r.db.Exec(`INSERT into medical_checks (id, driver_id, checked_at, passed, systolic, diastolic, alcohol, examiner) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`, &check.ID,
+		&check.DriverID,
+		&check.CheckedAt,
+		&check.Passed,
+		&check.Systolic,
+		&check.Diastolic,
+		&check.Alcohol,
+		&check.Examiner,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetByDriverId returns the driver's checks made in [from, to).
+func (r *PostgresMedicalCheckRepository) GetByDriverId(driverId string, from, to time.Time) ([]models.MedicalCheck, error) {
+	rows, err := r.db.Query(`
+		SELECT id, driver_id, checked_at, passed, systolic, diastolic, alcohol, examiner
+		FROM medical_checks
+		WHERE driver_id = $1 AND checked_at >= $2 AND checked_at < $3
+		ORDER BY checked_at
+	`, driverId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return scanMedicalChecks(rows)
+}
+
+// GetByPeriod returns all checks made in [from, to).
+func (r *PostgresMedicalCheckRepository) GetByPeriod(from, to time.Time) ([]models.MedicalCheck, error) {
+	rows, err := r.db.Query(`
+		SELECT id, driver_id, checked_at, passed, systolic, diastolic, alcohol, examiner
+		FROM medical_checks
+		WHERE checked_at >= $1 AND checked_at < $2
+		ORDER BY checked_at
+	`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return scanMedicalChecks(rows)
+}
+
+func scanMedicalChecks(rows *sql.Rows) ([]models.MedicalCheck, error) {
+	var checks []models.MedicalCheck
+	for rows.Next() {
+		check := &models.MedicalCheck{}
+		err := rows.Scan(
+			&check.ID,
+			&check.DriverID,
+			&check.CheckedAt,
+			&check.Passed,
+			&check.Systolic,
+			&check.Diastolic,
+			&check.Alcohol,
+			&check.Examiner,
+		)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, *check)
+	}
+	return checks, nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func setupMockMedicalCheck(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *PostgresMedicalCheckRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Ошибка создания mock базы данных: %v", err)
+	}
+	repo := &PostgresMedicalCheckRepository{db: db}
+	return db, mock, repo
+}
+
+func newTestMedicalCheck() *models.MedicalCheck {
+	return &models.MedicalCheck{
+		ID:        uuid.New().String(),
+		DriverID:  uuid.New().String(),
+		CheckedAt: time.Date(2025, 3, 3, 5, 30, 0, 0, time.UTC),
+		Passed:    true,
+		Systolic:  120,
+		Diastolic: 80,
+		Alcohol:   0,
+		Examiner:  "Petrova A.V.",
+	}
+}
+
+func TestPostgresMedicalCheckRepository(t *testing.T) {
+	columns := []string{"id", "driver_id", "checked_at", "passed", "systolic", "diastolic", "alcohol", "examiner"}
+
+	t.Run("NewPostgresMedicalCheckRepository", func(t *testing.T) {
+		db, _, _ := setupMockMedicalCheck(t)
+		defer db.Close()
+
+		repo, err := NewPostgresMedicalCheckRepository(db)
+		if err != nil {
+			t.Errorf("Ошибка при создании репозитория: %v", err)
+		}
+		if repo == nil {
+			t.Error("Репозиторий не должен быть nil")
+		}
+	})
+
+	t.Run("GetById", func(t *testing.T) {
+		db, mock, repo := setupMockMedicalCheck(t)
+		defer db.Close()
+
+		check := newTestMedicalCheck()
+		mock.ExpectQuery(`SELECT id, driver_id, checked_at, passed, systolic, diastolic, alcohol, examiner FROM medical_checks WHERE id = \$1`).
+			WithArgs(check.ID).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(check.ID, check.DriverID, check.CheckedAt, check.Passed, check.Systolic, check.Diastolic, check.Alcohol, check.Examiner))
+
+		retrieved, err := repo.GetById(check.ID)
+		if err != nil {
+			t.Errorf("Ошибка при получении медосмотра по ID: %v", err)
+		}
+		if !reflect.DeepEqual(check, retrieved) {
+			t.Errorf("Полученный медосмотр не совпадает: ожидался %v, получен %v", check, retrieved)
+		}
+
+		mock.ExpectQuery(`SELECT id, driver_id, checked_at, passed, systolic, diastolic, alcohol, examiner FROM medical_checks WHERE id = \$1`).
+			WithArgs("nonexistent").
+			WillReturnError(sql.ErrNoRows)
+
+		_, err = repo.GetById("nonexistent")
+		if err == nil || err.Error() != "Medical check not found" {
+			t.Errorf("Ожидалась ошибка 'Medical check not found', получена: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("Add", func(t *testing.T) {
+		db, mock, repo := setupMockMedicalCheck(t)
+		defer db.Close()
+
+		check := newTestMedicalCheck()
+		check.ID = ""
+		mock.ExpectExec(`INSERT into medical_checks \(id, driver_id, checked_at, passed, systolic, diastolic, alcohol, examiner\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8\)`).
+			WithArgs(sqlmock.AnyArg(), check.DriverID, check.CheckedAt, check.Passed, check.Systolic, check.Diastolic, check.Alcohol, check.Examiner).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.Add(check)
+		if err != nil {
+			t.Errorf("Ошибка при добавлении медосмотра: %v", err)
+		}
+		if check.ID == "" {
+			t.Error("ID медосмотра должен быть сгенерирован")
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("GetByDriverId", func(t *testing.T) {
+		db, mock, repo := setupMockMedicalCheck(t)
+		defer db.Close()
+
+		check := newTestMedicalCheck()
+		from := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(0, 0, 1)
+		mock.ExpectQuery(`SELECT id, driver_id, checked_at, passed, systolic, diastolic, alcohol, examiner FROM medical_checks WHERE driver_id = \$1 AND checked_at >= \$2 AND checked_at < \$3`).
+			WithArgs(check.DriverID, from, to).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(check.ID, check.DriverID, check.CheckedAt, check.Passed, check.Systolic, check.Diastolic, check.Alcohol, check.Examiner))
+
+		checks, err := repo.GetByDriverId(check.DriverID, from, to)
+		if err != nil {
+			t.Errorf("Ошибка при получении медосмотров водителя: %v", err)
+		}
+		if len(checks) != 1 || !reflect.DeepEqual(*check, checks[0]) {
+			t.Errorf("Ожидался 1 медосмотр %v, получено: %v", check, checks)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("GetByPeriod", func(t *testing.T) {
+		db, mock, repo := setupMockMedicalCheck(t)
+		defer db.Close()
+
+		check := newTestMedicalCheck()
+		from := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(0, 0, 1)
+		mock.ExpectQuery(`SELECT id, driver_id, checked_at, passed, systolic, diastolic, alcohol, examiner FROM medical_checks WHERE checked_at >= \$1 AND checked_at < \$2`).
+			WithArgs(from, to).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(check.ID, check.DriverID, check.CheckedAt, check.Passed, check.Systolic, check.Diastolic, check.Alcohol, check.Examiner))
+
+		checks, err := repo.GetByPeriod(from, to)
+		if err != nil {
+			t.Errorf("Ошибка при получении медосмотров за период: %v", err)
+		}
+		if len(checks) != 1 {
+			t.Errorf("Ожидался 1 медосмотр, получено: %d", len(checks))
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ridershipGroups maps a report grouping to the column expression it groups by.
+var ridershipGroups = map[string]string{
+	models.RidershipByRoute: "route_id",
+	models.RidershipByStop:  "stop_id",
+	models.RidershipByHour:  "to_char(counted_at, 'HH24')",
+}
+
+type PostgresPassengerCountRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresPassengerCountRepository(db *sql.DB) (*PostgresPassengerCountRepository, error) {
+	repo := &PostgresPassengerCountRepository{db: db}
+	return repo, nil
+}
+
+// AddBatch stores the counts. Counts a counter sends again are skipped.
+func (r *PostgresPassengerCountRepository) AddBatch(counts []models.PassengerCount) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, count := range counts {
+		_, err = tx.Exec(`INSERT into passenger_counts (bus_id, counted_at, stop_id, trip_id, route_id, bucket_minutes, granularity, boardings, alightings) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (bus_id, counted_at, stop_id, trip_id) DO NOTHING`,
+			count.BusID, count.CountedAt, count.StopID, count.TripID, count.RouteID, count.BucketMinutes, count.Granularity, count.Boardings, count.Alightings)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Aggregate sums the counts of the granularity taken in [from, to).
+func (r *PostgresPassengerCountRepository) Aggregate(from, to time.Time, groupBy, granularity, routeId string) ([]models.RidershipRow, error) {
+	column, ok := ridershipGroups[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("Unknown report grouping %s", groupBy)
+	}
+	query := fmt.Sprintf(`
+		SELECT %s AS key, SUM(boardings), SUM(alightings)
+		FROM passenger_counts
+		WHERE counted_at >= $1 AND counted_at < $2 AND ($3 = '' OR route_id = $3) AND granularity = $4
+		GROUP BY key
+		ORDER BY key`, column)
+	rows, err := r.db.Query(query, from, to, routeId, granularity)
+	if err != nil {
+		return nil, err
+	}
+	var result []models.RidershipRow
+	for rows.Next() {
+		row := &models.RidershipRow{}
+		err := rows.Scan(
+			&row.Key,
+			&row.Boardings,
+			&row.Alightings,
+		)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *row)
+	}
+	return result, nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func setupMockPassengerCount(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *PostgresPassengerCountRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Ошибка создания mock базы данных: %v", err)
+	}
+	repo := &PostgresPassengerCountRepository{db: db}
+	return db, mock, repo
+}
+
+func TestPostgresPassengerCountRepository(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	t.Run("NewPostgresPassengerCountRepository", func(t *testing.T) {
+		db, _, _ := setupMockPassengerCount(t)
+		defer db.Close()
+
+		repo, err := NewPostgresPassengerCountRepository(db)
+		if err != nil {
+			t.Errorf("Ошибка при создании репозитория: %v", err)
+		}
+		if repo == nil {
+			t.Error("Репозиторий не должен быть nil")
+		}
+	})
+
+	t.Run("AddBatch", func(t *testing.T) {
+		db, mock, repo := setupMockPassengerCount(t)
+		defer db.Close()
+
+		count := models.PassengerCount{
+			BusID:       uuid.New().String(),
+			CountedAt:   time.Date(2025, 3, 3, 8, 15, 0, 0, time.UTC),
+			StopID:      uuid.New().String(),
+			RouteID:     uuid.New().String(),
+			Granularity: models.CountPerStop,
+			Boardings:   7,
+			Alightings:  3,
+		}
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT into passenger_counts .* ON CONFLICT \(bus_id, counted_at, stop_id, trip_id\) DO NOTHING`).
+			WithArgs(count.BusID, count.CountedAt, count.StopID, count.TripID, count.RouteID, count.BucketMinutes, count.Granularity, count.Boardings, count.Alightings).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := repo.AddBatch([]models.PassengerCount{count})
+		if err != nil {
+			t.Errorf("Ошибка при сохранении показаний: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("Aggregate", func(t *testing.T) {
+		db, mock, repo := setupMockPassengerCount(t)
+		defer db.Close()
+
+		expected := []models.RidershipRow{{Key: "07", Boardings: 80, Alightings: 20}, {Key: "08", Boardings: 120, Alightings: 95}}
+		mock.ExpectQuery(`SELECT to_char\(counted_at, 'HH24'\) AS key, SUM\(boardings\), SUM\(alightings\) FROM passenger_counts WHERE counted_at >= \$1 AND counted_at < \$2 AND \(\$3 = '' OR route_id = \$3\) AND granularity = \$4 GROUP BY key ORDER BY key`).
+			WithArgs(from, to, "", models.CountPerBucket).
+			WillReturnRows(sqlmock.NewRows([]string{"key", "sum", "sum"}).
+				AddRow("07", 80, 20).
+				AddRow("08", 120, 95))
+
+		rows, err := repo.Aggregate(from, to, models.RidershipByHour, models.CountPerBucket, "")
+		if err != nil {
+			t.Errorf("Ошибка при агрегации пассажиропотока: %v", err)
+		}
+		if !reflect.DeepEqual(expected, rows) {
+			t.Errorf("Полученный отчет не совпадает: ожидалось %v, получено %v", expected, rows)
+		}
+
+		mock.ExpectQuery(`SELECT stop_id AS key, .* AND granularity = \$4 GROUP BY key`).
+			WithArgs(from, to, "route-1", models.CountPerStop).
+			WillReturnRows(sqlmock.NewRows([]string{"key", "sum", "sum"}))
+
+		_, err = repo.Aggregate(from, to, models.RidershipByStop, models.CountPerStop, "route-1")
+		if err != nil {
+			t.Errorf("Ошибка при агрегации пассажиропотока: %v", err)
+		}
+
+		_, err = repo.Aggregate(from, to, "driver", models.CountPerStop, "")
+		if err == nil || err.Error() != "Unknown report grouping driver" {
+			t.Errorf("Ожидалась ошибка 'Unknown report grouping driver', получена: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+}
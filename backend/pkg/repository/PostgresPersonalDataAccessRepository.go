@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"github.com/google/uuid"
+	"strings"
+)
+
+type PostgresPersonalDataAccessRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresPersonalDataAccessRepository(db *sql.DB) (*PostgresPersonalDataAccessRepository, error) {
+	repo := &PostgresPersonalDataAccessRepository{db: db}
+	return repo, nil
+}
+
+func (r *PostgresPersonalDataAccessRepository) Add(access *models.PersonalDataAccess) error {
+	if strings.TrimSpace(access.ID) == "" {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return err
+		}
+		access.ID = id.String()
+	}
+	_, err := r.db.Exec(`INSERT into personal_data_access_log (id, user_id, driver_id, action, accessed_at) 
+VALUES ($1, $2, $3, $4, $5)`, &access.ID,
+		&access.UserID,
+		&access.DriverID,
+		&access.Action,
+		&access.AccessedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+func setupMockPersonalDataAccess(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *PostgresPersonalDataAccessRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Ошибка создания mock базы данных: %v", err)
+	}
+	repo := &PostgresPersonalDataAccessRepository{db: db}
+	return db, mock, repo
+}
+
+func TestPostgresPersonalDataAccessRepository(t *testing.T) {
+	t.Run("NewPostgresPersonalDataAccessRepository", func(t *testing.T) {
+		db, _, _ := setupMockPersonalDataAccess(t)
+		defer db.Close()
+
+		repo, err := NewPostgresPersonalDataAccessRepository(db)
+		if err != nil {
+			t.Errorf("Ошибка при создании репозитория: %v", err)
+		}
+		if repo == nil {
+			t.Error("Репозиторий не должен быть nil")
+		}
+	})
+
+	t.Run("Add", func(t *testing.T) {
+		db, mock, repo := setupMockPersonalDataAccess(t)
+		defer db.Close()
+
+		access := &models.PersonalDataAccess{
+			UserID:     uuid.New().String(),
+			DriverID:   uuid.New().String(),
+			Action:     "GET /api/drivers/id/:id",
+			AccessedAt: time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC),
+		}
+		mock.ExpectExec(`INSERT into personal_data_access_log \(id, user_id, driver_id, action, accessed_at\) VALUES \(\$1, \$2, \$3, \$4, \$5\)`).
+			WithArgs(sqlmock.AnyArg(), access.UserID, access.DriverID, access.Action, access.AccessedAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.Add(access)
+		if err != nil {
+			t.Errorf("Ошибка при записи в журнал доступа: %v", err)
+		}
+		if access.ID == "" {
+			t.Error("ID записи должен быть сгенерирован")
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+type PostgresPositionRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresPositionRepository(db *sql.DB) (*PostgresPositionRepository, error) {
+	repo := &PostgresPositionRepository{db: db}
+	return repo, nil
+}
+
+// SaveBatch appends the positions to the history and moves the latest
+// position of each bus forward. Positions older than the stored latest one
+// only go to the history.
+func (r *PostgresPositionRepository) SaveBatch(positions []models.VehiclePosition) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, position := range positions {
+		_, err = tx.Exec(`INSERT into bus_position_history (id, bus_id, recorded_at, lat, long, speed, heading) 
+VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			uuid.NewString(), position.BusID, position.RecordedAt, position.Lat, position.Long, position.Speed, position.Heading)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`INSERT into bus_positions (bus_id, recorded_at, lat, long, speed, heading) 
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (bus_id) DO UPDATE SET recorded_at = EXCLUDED.recorded_at, lat = EXCLUDED.lat, long = EXCLUDED.long, speed = EXCLUDED.speed, heading = EXCLUDED.heading
+WHERE bus_positions.recorded_at < EXCLUDED.recorded_at`,
+			position.BusID, position.RecordedAt, position.Lat, position.Long, position.Speed, position.Heading)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresPositionRepository) GetLatest(busId string) (*models.VehiclePosition, error) {
+	position := &models.VehiclePosition{}
+	err := r.db.QueryRow(`
+		SELECT bus_id, recorded_at, lat, long, speed, heading
+		FROM bus_positions
+		WHERE bus_id = $1`, busId).Scan(
+		&position.BusID,
+		&position.RecordedAt,
+		&position.Lat,
+		&position.Long,
+		&position.Speed,
+		&position.Heading,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("Position not found")
+		}
+		return nil, err
+	}
+
+	return position, nil
+}
+
+func (r *PostgresPositionRepository) GetAllLatest() ([]models.VehiclePosition, error) {
+	rows, err := r.db.Query(`
+		SELECT bus_id, recorded_at, lat, long, speed, heading
+		FROM bus_positions
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return scanPositions(rows)
+}
+
+// GetHistory returns positions of the bus recorded in [from, to).
+func (r *PostgresPositionRepository) GetHistory(busId string, from, to time.Time) ([]models.VehiclePosition, error) {
+	rows, err := r.db.Query(`
+		SELECT bus_id, recorded_at, lat, long, speed, heading
+		FROM bus_position_history
+		WHERE bus_id = $1 AND recorded_at >= $2 AND recorded_at < $3
+		ORDER BY recorded_at
+	`, busId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return scanPositions(rows)
+}
+
+func scanPositions(rows *sql.Rows) ([]models.VehiclePosition, error) {
+	var positions []models.VehiclePosition
+	for rows.Next() {
+		position := &models.VehiclePosition{}
+		err := rows.Scan(
+			&position.BusID,
+			&position.RecordedAt,
+			&position.Lat,
+			&position.Long,
+			&position.Speed,
+			&position.Heading,
+		)
+		if err != nil {
+			return nil, err
+		}
+		positions = append(positions, *position)
+	}
+	return positions, nil
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func setupMockPosition(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *PostgresPositionRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Ошибка создания mock базы данных: %v", err)
+	}
+	repo := &PostgresPositionRepository{db: db}
+	return db, mock, repo
+}
+
+func newTestPosition() *models.VehiclePosition {
+	return &models.VehiclePosition{
+		BusID:      uuid.New().String(),
+		RecordedAt: time.Date(2025, 3, 3, 8, 15, 0, 0, time.UTC),
+		Lat:        55.7558,
+		Long:       37.6173,
+		Speed:      32.5,
+		Heading:    270,
+	}
+}
+
+func TestPostgresPositionRepository(t *testing.T) {
+	columns := []string{"bus_id", "recorded_at", "lat", "long", "speed", "heading"}
+
+	t.Run("NewPostgresPositionRepository", func(t *testing.T) {
+		db, _, _ := setupMockPosition(t)
+		defer db.Close()
+
+		repo, err := NewPostgresPositionRepository(db)
+		if err != nil {
+			t.Errorf("Ошибка при создании репозитория: %v", err)
+		}
+		if repo == nil {
+			t.Error("Репозиторий не должен быть nil")
+		}
+	})
+
+	t.Run("SaveBatch", func(t *testing.T) {
+		db, mock, repo := setupMockPosition(t)
+		defer db.Close()
+
+		position := newTestPosition()
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT into bus_position_history`).
+			WithArgs(sqlmock.AnyArg(), position.BusID, position.RecordedAt, position.Lat, position.Long, position.Speed, position.Heading).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`INSERT into bus_positions .* ON CONFLICT \(bus_id\) DO UPDATE .* WHERE bus_positions.recorded_at < EXCLUDED.recorded_at`).
+			WithArgs(position.BusID, position.RecordedAt, position.Lat, position.Long, position.Speed, position.Heading).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := repo.SaveBatch([]models.VehiclePosition{*position})
+		if err != nil {
+			t.Errorf("Ошибка при сохранении позиций: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("SaveBatch rollback", func(t *testing.T) {
+		db, mock, repo := setupMockPosition(t)
+		defer db.Close()
+
+		position := newTestPosition()
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT into bus_position_history`).
+			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		err := repo.SaveBatch([]models.VehiclePosition{*position})
+		if err == nil {
+			t.Error("Ожидалась ошибка при сохранении позиций")
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("GetLatest", func(t *testing.T) {
+		db, mock, repo := setupMockPosition(t)
+		defer db.Close()
+
+		position := newTestPosition()
+		mock.ExpectQuery(`SELECT bus_id, recorded_at, lat, long, speed, heading FROM bus_positions WHERE bus_id = \$1`).
+			WithArgs(position.BusID).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(position.BusID, position.RecordedAt, position.Lat, position.Long, position.Speed, position.Heading))
+
+		retrieved, err := repo.GetLatest(position.BusID)
+		if err != nil {
+			t.Errorf("Ошибка при получении позиции: %v", err)
+		}
+		if !reflect.DeepEqual(position, retrieved) {
+			t.Errorf("Полученная позиция не совпадает: ожидалось %v, получено %v", position, retrieved)
+		}
+
+		mock.ExpectQuery(`SELECT bus_id, recorded_at, lat, long, speed, heading FROM bus_positions WHERE bus_id = \$1`).
+			WithArgs("nonexistent").
+			WillReturnError(sql.ErrNoRows)
+
+		_, err = repo.GetLatest("nonexistent")
+		if err == nil || err.Error() != "Position not found" {
+			t.Errorf("Ожидалась ошибка 'Position not found', получена: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("GetHistory", func(t *testing.T) {
+		db, mock, repo := setupMockPosition(t)
+		defer db.Close()
+
+		position := newTestPosition()
+		from := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(0, 0, 1)
+		mock.ExpectQuery(`SELECT bus_id, recorded_at, lat, long, speed, heading FROM bus_position_history WHERE bus_id = \$1 AND recorded_at >= \$2 AND recorded_at < \$3 ORDER BY recorded_at`).
+			WithArgs(position.BusID, from, to).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(position.BusID, position.RecordedAt, position.Lat, position.Long, position.Speed, position.Heading))
+
+		positions, err := repo.GetHistory(position.BusID, from, to)
+		if err != nil {
+			t.Errorf("Ошибка при получении истории позиций: %v", err)
+		}
+		if len(positions) != 1 || !reflect.DeepEqual(*position, positions[0]) {
+			t.Errorf("Полученная история не совпадает: ожидалось %v, получено %v", position, positions)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"github.com/google/uuid"
 	"strings"
+	"time"
 )
 
 type PostgresRouteRepository struct {
@@ -171,8 +172,8 @@ func (r *PostgresRouteRepository) AssignBusStop(routeId, busStopId string) error
 	if count > 0 {
 		return errors.New("Pair route_id and bus_stop_id already exists")
 	}
-	_, err = r.db.Exec(`INSERT into routes_bus_stops (route_id, bus_stop_id) 
-VALUES ($1, $2)`, routeId,
+	_, err = r.db.Exec(`INSERT into routes_bus_stops (route_id, bus_stop_id, stop_sequence) 
+VALUES ($1, $2, (SELECT COALESCE(MAX(stop_sequence), 0) + 1 FROM routes_bus_stops WHERE route_id = $1))`, routeId,
 		busStopId,
 	)
 	if err != nil {
@@ -216,15 +217,24 @@ func (r *PostgresRouteRepository) UnassignBusStop(routeId, busStopId string) err
 	return nil
 }
 
-func (r *PostgresRouteRepository) UnassignBus(routeId, busId string) error {
+// UnassignBus removes the assignment period of the bus on the route that
+// starts at startsAt, the other periods stay.
+func (r *PostgresRouteRepository) UnassignBus(routeId, busId string, startsAt time.Time) error {
 	exist, err := r.GetById(routeId)
 	if exist == nil {
 		return errors.New("Route not found")
 	}
-	_, err = r.db.Exec(`DELETE FROM routes_buses WHERE route_id = $1 AND bus_id = $2`, routeId, busId)
+	result, err := r.db.Exec(`DELETE FROM routes_buses WHERE route_id = $1 AND bus_id = $2 AND starts_at = $3`, routeId, busId, startsAt)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if affected == 0 {
+		return errors.New("Assignment not found")
+	}
 	return nil
 }
 
@@ -292,6 +302,7 @@ func (r *PostgresRouteRepository) GetAllBusStopsById(routeId string) ([]models.B
 		FROM bus_stops d 
 		JOIN routes_bus_stops rd ON d.id = rd.bus_stop_id
 		WHERE rd.route_id=$1
+		ORDER BY rd.stop_sequence
 	`, routeId)
 	if err != nil {
 		return nil, err
@@ -312,7 +323,9 @@ func (r *PostgresRouteRepository) GetAllBusStopsById(routeId string) ([]models.B
 	return busStops, nil
 }
 
-func (r *PostgresRouteRepository) GetAllBusesById(routeId string) ([]models.Bus, error) {
+// GetAllBusesAt returns the buses assigned to the route at the given time.
+// A bus with several periods on the route is returned once.
+func (r *PostgresRouteRepository) GetAllBusesAt(routeId string, at time.Time) ([]models.Bus, error) {
 	var buses []models.Bus
 	exist, err := r.GetById(routeId)
 	if exist == nil {
@@ -322,11 +335,11 @@ func (r *PostgresRouteRepository) GetAllBusesById(routeId string) ([]models.Bus,
 		return nil, err
 	}
 	rows, err := r.db.Query(`
-		SELECT d.id, d.brand, d.bus_model, d.register_number, d.assembly_date, d.last_repair_date
+		SELECT DISTINCT d.id, d.brand, d.bus_model, d.register_number, d.assembly_date, d.last_repair_date, d.vehicle_class
 		FROM buses d 
 		JOIN routes_buses rd ON d.id = rd.bus_id
-		WHERE rd.route_id=$1
-	`, routeId)
+		WHERE rd.route_id=$1 AND rd.starts_at <= $2 AND (rd.ends_at IS NULL OR rd.ends_at > $2)
+	`, routeId, at)
 	if err != nil {
 		return nil, err
 	}
@@ -339,6 +352,7 @@ func (r *PostgresRouteRepository) GetAllBusesById(routeId string) ([]models.Bus,
 			&bus.RegisterNumber,
 			&bus.AssemblyDate,
 			&bus.LastRepairDate,
+			&bus.VehicleClass,
 		)
 		if err != nil {
 			return nil, err
@@ -347,3 +361,109 @@ func (r *PostgresRouteRepository) GetAllBusesById(routeId string) ([]models.Bus,
 	}
 	return buses, nil
 }
+
+// AssignBusForPeriod stores the assignment unless the bus went into repair or
+// got an overlapping period on the same route, or on any route without force,
+// since the caller checked. The bus row stays locked until the insert, as in
+// ReplaceBus.
+func (r *PostgresRouteRepository) AssignBusForPeriod(assignment *models.BusAssignment, force bool) error {
+	exist, err := r.GetById(assignment.RouteID)
+	if exist == nil {
+		return errors.New("Route not found")
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var overlaps, inRepair bool
+	err = tx.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM routes_buses WHERE bus_id = $1 AND (route_id = $2 OR NOT $5)
+			AND starts_at < COALESCE($4::timestamp, 'infinity') AND COALESCE(ends_at, 'infinity') > $3),
+			EXISTS (SELECT 1 FROM bus_repairs WHERE bus_id = $1 AND finished_at IS NULL)
+		FROM buses
+		WHERE id = $1
+		FOR UPDATE`, assignment.BusID, assignment.RouteID, assignment.StartsAt, assignment.EndsAt, force).Scan(&overlaps, &inRepair)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("Bus not found")
+		}
+		return err
+	}
+	if inRepair {
+		return errors.New("Bus is in repair")
+	}
+	if overlaps {
+		return errors.New("Bus got an overlapping assignment meanwhile, reload and try again")
+	}
+	_, err = tx.Exec(`INSERT into routes_buses (route_id, bus_id, starts_at, ends_at) 
+VALUES ($1, $2, $3, $4)`, assignment.RouteID,
+		assignment.BusID,
+		assignment.StartsAt,
+		assignment.EndsAt,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresRouteRepository) GetBusAssignments(busId string) ([]models.BusAssignment, error) {
+	var assignments []models.BusAssignment
+	rows, err := r.db.Query(`
+		SELECT route_id, bus_id, starts_at, ends_at
+		FROM routes_buses
+		WHERE bus_id = $1
+	`, busId)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		assignment := &models.BusAssignment{}
+		err := rows.Scan(
+			&assignment.RouteID,
+			&assignment.BusID,
+			&assignment.StartsAt,
+			&assignment.EndsAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, *assignment)
+	}
+	return assignments, nil
+}
+
+func (r *PostgresRouteRepository) GetBusConflicts() ([]models.BusConflict, error) {
+	var conflicts []models.BusConflict
+	rows, err := r.db.Query(`
+		SELECT a.bus_id, a.route_id, a.starts_at, a.ends_at, b.route_id, b.starts_at, b.ends_at
+		FROM routes_buses a
+		JOIN routes_buses b ON a.bus_id = b.bus_id AND a.route_id < b.route_id
+		WHERE a.starts_at < COALESCE(b.ends_at, 'infinity') AND b.starts_at < COALESCE(a.ends_at, 'infinity')
+		ORDER BY a.bus_id, a.starts_at
+	`)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		conflict := &models.BusConflict{}
+		err := rows.Scan(
+			&conflict.BusID,
+			&conflict.First.RouteID,
+			&conflict.First.StartsAt,
+			&conflict.First.EndsAt,
+			&conflict.Second.RouteID,
+			&conflict.Second.StartsAt,
+			&conflict.Second.EndsAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		conflict.First.BusID = conflict.BusID
+		conflict.Second.BusID = conflict.BusID
+		conflicts = append(conflicts, *conflict)
+	}
+	return conflicts, nil
+}
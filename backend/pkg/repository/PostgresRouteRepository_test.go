@@ -341,7 +341,7 @@ func TestPostgresRouteRepository(t *testing.T) {
 			WithArgs(routeID, busStopID).
 			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 
-		mock.ExpectExec(`INSERT into routes_bus_stops \(route_id, bus_stop_id\) VALUES \(\$1, \$2\)`).
+		mock.ExpectExec(`INSERT into routes_bus_stops \(route_id, bus_stop_id, stop_sequence\) VALUES \(\$1, \$2, \(SELECT COALESCE\(MAX\(stop_sequence\), 0\) \+ 1 FROM routes_bus_stops WHERE route_id = \$1\)\)`).
 			WithArgs(routeID, busStopID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -478,20 +478,34 @@ func TestPostgresRouteRepository(t *testing.T) {
 			WillReturnRows(sqlmock.NewRows([]string{"id", "number"}).
 				AddRow(routeID, "112"))
 
-		mock.ExpectExec(`DELETE FROM routes_buses WHERE route_id = \$1 AND bus_id = \$2`).
-			WithArgs(routeID, busID).
+		startsAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+		mock.ExpectExec(`DELETE FROM routes_buses WHERE route_id = \$1 AND bus_id = \$2 AND starts_at = \$3`).
+			WithArgs(routeID, busID, startsAt).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
-		err := repo.UnassignBus(routeID, busID)
+		err := repo.UnassignBus(routeID, busID, startsAt)
 		if err != nil {
 			t.Errorf("Ошибка при снятии назначения автобуса: %v", err)
 		}
 
+		mock.ExpectQuery(`SELECT id, number FROM routes WHERE id = \$1`).
+			WithArgs(routeID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "number"}).
+				AddRow(routeID, "112"))
+		mock.ExpectExec(`DELETE FROM routes_buses WHERE route_id = \$1 AND bus_id = \$2 AND starts_at = \$3`).
+			WithArgs(routeID, busID, startsAt).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err = repo.UnassignBus(routeID, busID, startsAt)
+		if err == nil || err.Error() != "Assignment not found" {
+			t.Errorf("Ожидалась ошибка 'Assignment not found', получена: %v", err)
+		}
+
 		mock.ExpectQuery(`SELECT id, number FROM routes WHERE id = \$1`).
 			WithArgs("nonexistent").
 			WillReturnError(sql.ErrNoRows)
 
-		err = repo.UnassignBus("nonexistent", busID)
+		err = repo.UnassignBus("nonexistent", busID, startsAt)
 		if err == nil || err.Error() != "Route not found" {
 			t.Errorf("Ожидалась ошибка 'Route not found', получена: %v", err)
 		}
@@ -634,7 +648,7 @@ func TestPostgresRouteRepository(t *testing.T) {
 		rows := sqlmock.NewRows([]string{"id", "lat", "long", "name"}).
 			AddRow(busStop1.ID, busStop1.Lat, busStop1.Long, busStop1.Name).
 			AddRow(busStop2.ID, busStop2.Lat, busStop2.Long, busStop2.Name)
-		mock.ExpectQuery(`SELECT d\.id, d\.lat, d\.long, d\.name FROM bus_stops d JOIN routes_bus_stops rd ON d\.id = rd\.bus_stop_id WHERE rd\.route_id=\$1`).
+		mock.ExpectQuery(`SELECT d\.id, d\.lat, d\.long, d\.name FROM bus_stops d JOIN routes_bus_stops rd ON d\.id = rd\.bus_stop_id WHERE rd\.route_id=\$1 ORDER BY rd\.stop_sequence`).
 			WithArgs(routeID).
 			WillReturnRows(rows)
 
@@ -672,7 +686,7 @@ func TestPostgresRouteRepository(t *testing.T) {
 		}
 	})
 
-	t.Run("GetAllBusesById", func(t *testing.T) {
+	t.Run("GetAllBusesAt", func(t *testing.T) {
 		db, mock, repo := setupMockRoute(t)
 		defer db.Close()
 
@@ -699,14 +713,15 @@ func TestPostgresRouteRepository(t *testing.T) {
 			WillReturnRows(sqlmock.NewRows([]string{"id", "number"}).
 				AddRow(routeID, "116"))
 
-		rows := sqlmock.NewRows([]string{"id", "brand", "bus_model", "register_number", "assembly_date", "last_repair_date"}).
-			AddRow(bus1.ID, bus1.Brand, bus1.BusModel, bus1.RegisterNumber, bus1.AssemblyDate, bus1.LastRepairDate).
-			AddRow(bus2.ID, bus2.Brand, bus2.BusModel, bus2.RegisterNumber, bus2.AssemblyDate, bus2.LastRepairDate)
-		mock.ExpectQuery(`SELECT d\.id, d\.brand, d\.bus_model, d\.register_number, d\.assembly_date, d\.last_repair_date FROM buses d JOIN routes_buses rd ON d\.id = rd\.bus_id WHERE rd\.route_id=\$1`).
-			WithArgs(routeID).
+		rows := sqlmock.NewRows([]string{"id", "brand", "bus_model", "register_number", "assembly_date", "last_repair_date", "vehicle_class"}).
+			AddRow(bus1.ID, bus1.Brand, bus1.BusModel, bus1.RegisterNumber, bus1.AssemblyDate, bus1.LastRepairDate, bus1.VehicleClass).
+			AddRow(bus2.ID, bus2.Brand, bus2.BusModel, bus2.RegisterNumber, bus2.AssemblyDate, bus2.LastRepairDate, bus2.VehicleClass)
+		at := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`SELECT DISTINCT d\.id, d\.brand, d\.bus_model, d\.register_number, d\.assembly_date, d\.last_repair_date, d\.vehicle_class FROM buses d JOIN routes_buses rd ON d\.id = rd\.bus_id WHERE rd\.route_id=\$1 AND rd\.starts_at <= \$2 AND \(rd\.ends_at IS NULL OR rd\.ends_at > \$2\)`).
+			WithArgs(routeID, at).
 			WillReturnRows(rows)
 
-		buses, err := repo.GetAllBusesById(routeID)
+		buses, err := repo.GetAllBusesAt(routeID, at)
 		if err != nil {
 			t.Errorf("Ошибка при получении автобусов по маршруту: %v", err)
 		}
@@ -730,7 +745,80 @@ func TestPostgresRouteRepository(t *testing.T) {
 			WithArgs("nonexistent").
 			WillReturnError(sql.ErrNoRows)
 
-		_, err = repo.GetAllBusesById("nonexistent")
+		_, err = repo.GetAllBusesAt("nonexistent", at)
+		if err == nil || err.Error() != "Route not found" {
+			t.Errorf("Ожидалась ошибка 'Route not found', получена: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("AssignBusForPeriod", func(t *testing.T) {
+		db, mock, repo := setupMockRoute(t)
+		defer db.Close()
+
+		start := time.Date(2025, 3, 1, 6, 0, 0, 0, time.UTC)
+		end := start.Add(8 * time.Hour)
+		assignment := &models.BusAssignment{RouteID: uuid.New().String(), BusID: uuid.New().String(), StartsAt: start, EndsAt: &end}
+
+		mock.ExpectQuery(`SELECT id, number FROM routes WHERE id = \$1`).
+			WithArgs(assignment.RouteID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "number"}).
+				AddRow(assignment.RouteID, "110"))
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT EXISTS .+ FROM buses WHERE id = \$1 FOR UPDATE`).
+			WithArgs(assignment.BusID, assignment.RouteID, assignment.StartsAt, assignment.EndsAt, false).
+			WillReturnRows(sqlmock.NewRows([]string{"overlaps", "in_repair"}).AddRow(false, false))
+		mock.ExpectExec(`INSERT into routes_buses \(route_id, bus_id, starts_at, ends_at\) VALUES \(\$1, \$2, \$3, \$4\)`).
+			WithArgs(assignment.RouteID, assignment.BusID, assignment.StartsAt, assignment.EndsAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := repo.AssignBusForPeriod(assignment, false)
+		if err != nil {
+			t.Errorf("Ошибка при назначении автобуса на период: %v", err)
+		}
+
+		// another assignment of the bus was stored after the service checked
+		mock.ExpectQuery(`SELECT id, number FROM routes WHERE id = \$1`).
+			WithArgs(assignment.RouteID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "number"}).
+				AddRow(assignment.RouteID, "110"))
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT EXISTS .+ FROM buses WHERE id = \$1 FOR UPDATE`).
+			WithArgs(assignment.BusID, assignment.RouteID, assignment.StartsAt, assignment.EndsAt, true).
+			WillReturnRows(sqlmock.NewRows([]string{"overlaps", "in_repair"}).AddRow(true, false))
+		mock.ExpectRollback()
+
+		err = repo.AssignBusForPeriod(assignment, true)
+		if err == nil || err.Error() != "Bus got an overlapping assignment meanwhile, reload and try again" {
+			t.Errorf("Ожидалась ошибка о пересечении назначений, получена: %v", err)
+		}
+
+		// the bus broke down after the service checked
+		mock.ExpectQuery(`SELECT id, number FROM routes WHERE id = \$1`).
+			WithArgs(assignment.RouteID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "number"}).
+				AddRow(assignment.RouteID, "110"))
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT EXISTS .+ FROM buses WHERE id = \$1 FOR UPDATE`).
+			WithArgs(assignment.BusID, assignment.RouteID, assignment.StartsAt, assignment.EndsAt, false).
+			WillReturnRows(sqlmock.NewRows([]string{"overlaps", "in_repair"}).AddRow(false, true))
+		mock.ExpectRollback()
+
+		err = repo.AssignBusForPeriod(assignment, false)
+		if err == nil || err.Error() != "Bus is in repair" {
+			t.Errorf("Ожидалась ошибка 'Bus is in repair', получена: %v", err)
+		}
+
+		mock.ExpectQuery(`SELECT id, number FROM routes WHERE id = \$1`).
+			WithArgs("nonexistent").
+			WillReturnError(sql.ErrNoRows)
+
+		err = repo.AssignBusForPeriod(&models.BusAssignment{RouteID: "nonexistent", BusID: assignment.BusID, StartsAt: start}, false)
 		if err == nil || err.Error() != "Route not found" {
 			t.Errorf("Ожидалась ошибка 'Route not found', получена: %v", err)
 		}
@@ -739,4 +827,63 @@ func TestPostgresRouteRepository(t *testing.T) {
 			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
 		}
 	})
+
+	t.Run("GetBusAssignments", func(t *testing.T) {
+		db, mock, repo := setupMockRoute(t)
+		defer db.Close()
+
+		busID := uuid.New().String()
+		start := time.Date(2025, 3, 1, 6, 0, 0, 0, time.UTC)
+		end := start.Add(8 * time.Hour)
+		expected := []models.BusAssignment{
+			{RouteID: uuid.New().String(), BusID: busID, StartsAt: start, EndsAt: &end},
+			{RouteID: uuid.New().String(), BusID: busID, StartsAt: end},
+		}
+
+		rows := sqlmock.NewRows([]string{"route_id", "bus_id", "starts_at", "ends_at"}).
+			AddRow(expected[0].RouteID, busID, start, end).
+			AddRow(expected[1].RouteID, busID, end, nil)
+		mock.ExpectQuery(`SELECT route_id, bus_id, starts_at, ends_at FROM routes_buses WHERE bus_id = \$1`).
+			WithArgs(busID).
+			WillReturnRows(rows)
+
+		assignments, err := repo.GetBusAssignments(busID)
+		if err != nil {
+			t.Errorf("Ошибка при получении назначений автобуса: %v", err)
+		}
+		if !reflect.DeepEqual(expected, assignments) {
+			t.Errorf("Полученные назначения не совпадают: ожидалось %v, получено %v", expected, assignments)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("GetBusConflicts", func(t *testing.T) {
+		db, mock, repo := setupMockRoute(t)
+		defer db.Close()
+
+		busID := uuid.New().String()
+		firstRouteID := uuid.New().String()
+		secondRouteID := uuid.New().String()
+		start := time.Date(2025, 3, 1, 6, 0, 0, 0, time.UTC)
+
+		rows := sqlmock.NewRows([]string{"bus_id", "route_id", "starts_at", "ends_at", "route_id", "starts_at", "ends_at"}).
+			AddRow(busID, firstRouteID, start, nil, secondRouteID, start.Add(time.Hour), nil)
+		mock.ExpectQuery(`SELECT a\.bus_id, a\.route_id, a\.starts_at, a\.ends_at, b\.route_id, b\.starts_at, b\.ends_at FROM routes_buses a JOIN routes_buses b`).
+			WillReturnRows(rows)
+
+		conflicts, err := repo.GetBusConflicts()
+		if err != nil {
+			t.Errorf("Ошибка при получении конфликтов: %v", err)
+		}
+		if len(conflicts) != 1 || conflicts[0].First.RouteID != firstRouteID || conflicts[0].Second.RouteID != secondRouteID || conflicts[0].Second.BusID != busID {
+			t.Errorf("Неверные конфликты: %v", conflicts)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
 }
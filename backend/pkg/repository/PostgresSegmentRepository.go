@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"errors"
+)
+
+// maxSegmentSamples bounds the weight of the stored average, so it keeps
+// following changes in traffic instead of settling forever.
+const maxSegmentSamples = 50
+
+type PostgresSegmentRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSegmentRepository(db *sql.DB) (*PostgresSegmentRepository, error) {
+	repo := &PostgresSegmentRepository{db: db}
+	return repo, nil
+}
+
+func (r *PostgresSegmentRepository) AddTravelTime(routeId, fromStopId, toStopId string, hour int, seconds float64) error {
+	_, err := r.db.Exec(`INSERT into segment_travel_times (route_id, from_stop_id, to_stop_id, hour, avg_seconds, samples) 
+VALUES ($1, $2, $3, $4, $5, 1)
+ON CONFLICT (route_id, from_stop_id, to_stop_id, hour) DO UPDATE SET
+avg_seconds = (segment_travel_times.avg_seconds * LEAST(segment_travel_times.samples, $6 - 1) + EXCLUDED.avg_seconds) / (LEAST(segment_travel_times.samples, $6 - 1) + 1),
+samples = LEAST(segment_travel_times.samples + 1, $6)`,
+		routeId, fromStopId, toStopId, hour, seconds, maxSegmentSamples)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *PostgresSegmentRepository) GetTravelTimes(routeId string) ([]models.SegmentTravelTime, error) {
+	rows, err := r.db.Query(`
+		SELECT route_id, from_stop_id, to_stop_id, hour, avg_seconds, samples
+		FROM segment_travel_times
+		WHERE route_id = $1
+	`, routeId)
+	if err != nil {
+		return nil, err
+	}
+	var times []models.SegmentTravelTime
+	for rows.Next() {
+		travelTime := &models.SegmentTravelTime{}
+		err := rows.Scan(
+			&travelTime.RouteID,
+			&travelTime.FromStopID,
+			&travelTime.ToStopID,
+			&travelTime.Hour,
+			&travelTime.AvgSeconds,
+			&travelTime.Samples,
+		)
+		if err != nil {
+			return nil, err
+		}
+		times = append(times, *travelTime)
+	}
+	return times, nil
+}
+
+func (r *PostgresSegmentRepository) GetLastStop(busId string) (*models.StopPassage, error) {
+	passage := &models.StopPassage{}
+	err := r.db.QueryRow(`
+		SELECT bus_id, route_id, stop_id, passed_at
+		FROM bus_last_stops
+		WHERE bus_id = $1`, busId).Scan(
+		&passage.BusID,
+		&passage.RouteID,
+		&passage.StopID,
+		&passage.PassedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("Stop passage not found")
+		}
+		return nil, err
+	}
+
+	return passage, nil
+}
+
+// GetAllLastStops returns the stop every bus passed last
+func (r *PostgresSegmentRepository) GetAllLastStops() ([]models.StopPassage, error) {
+	rows, err := r.db.Query(`
+		SELECT bus_id, route_id, stop_id, passed_at
+		FROM bus_last_stops
+	`)
+	if err != nil {
+		return nil, err
+	}
+	var passages []models.StopPassage
+	for rows.Next() {
+		passage := &models.StopPassage{}
+		err := rows.Scan(
+			&passage.BusID,
+			&passage.RouteID,
+			&passage.StopID,
+			&passage.PassedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		passages = append(passages, *passage)
+	}
+	return passages, nil
+}
+
+func (r *PostgresSegmentRepository) SaveLastStop(passage *models.StopPassage) error {
+	_, err := r.db.Exec(`INSERT into bus_last_stops (bus_id, route_id, stop_id, passed_at) 
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (bus_id) DO UPDATE SET route_id = EXCLUDED.route_id, stop_id = EXCLUDED.stop_id, passed_at = EXCLUDED.passed_at`,
+		passage.BusID, passage.RouteID, passage.StopID, passage.PassedAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
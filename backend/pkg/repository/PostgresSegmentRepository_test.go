@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func setupMockSegment(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *PostgresSegmentRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Ошибка создания mock базы данных: %v", err)
+	}
+	repo := &PostgresSegmentRepository{db: db}
+	return db, mock, repo
+}
+
+func TestPostgresSegmentRepository(t *testing.T) {
+	t.Run("NewPostgresSegmentRepository", func(t *testing.T) {
+		db, _, _ := setupMockSegment(t)
+		defer db.Close()
+
+		repo, err := NewPostgresSegmentRepository(db)
+		if err != nil {
+			t.Errorf("Ошибка при создании репозитория: %v", err)
+		}
+		if repo == nil {
+			t.Error("Репозиторий не должен быть nil")
+		}
+	})
+
+	t.Run("AddTravelTime", func(t *testing.T) {
+		db, mock, repo := setupMockSegment(t)
+		defer db.Close()
+
+		routeID := uuid.New().String()
+		mock.ExpectExec(`INSERT into segment_travel_times .* ON CONFLICT \(route_id, from_stop_id, to_stop_id, hour\) DO UPDATE`).
+			WithArgs(routeID, "s1", "s2", 8, 180.0, maxSegmentSamples).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.AddTravelTime(routeID, "s1", "s2", 8, 180)
+		if err != nil {
+			t.Errorf("Ошибка при добавлении времени проезда: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("GetTravelTimes", func(t *testing.T) {
+		db, mock, repo := setupMockSegment(t)
+		defer db.Close()
+
+		travelTime := models.SegmentTravelTime{RouteID: uuid.New().String(), FromStopID: "s1", ToStopID: "s2", Hour: 8, AvgSeconds: 180, Samples: 3}
+		mock.ExpectQuery(`SELECT route_id, from_stop_id, to_stop_id, hour, avg_seconds, samples FROM segment_travel_times WHERE route_id = \$1`).
+			WithArgs(travelTime.RouteID).
+			WillReturnRows(sqlmock.NewRows([]string{"route_id", "from_stop_id", "to_stop_id", "hour", "avg_seconds", "samples"}).
+				AddRow(travelTime.RouteID, travelTime.FromStopID, travelTime.ToStopID, travelTime.Hour, travelTime.AvgSeconds, travelTime.Samples))
+
+		times, err := repo.GetTravelTimes(travelTime.RouteID)
+		if err != nil {
+			t.Errorf("Ошибка при получении времени проезда: %v", err)
+		}
+		if len(times) != 1 || !reflect.DeepEqual(travelTime, times[0]) {
+			t.Errorf("Полученное время проезда не совпадает: ожидалось %v, получено %v", travelTime, times)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("LastStop", func(t *testing.T) {
+		db, mock, repo := setupMockSegment(t)
+		defer db.Close()
+
+		passage := &models.StopPassage{BusID: uuid.New().String(), RouteID: uuid.New().String(), StopID: "s1", PassedAt: time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC)}
+		mock.ExpectExec(`INSERT into bus_last_stops \(bus_id, route_id, stop_id, passed_at\) VALUES \(\$1, \$2, \$3, \$4\) ON CONFLICT \(bus_id\) DO UPDATE`).
+			WithArgs(passage.BusID, passage.RouteID, passage.StopID, passage.PassedAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery(`SELECT bus_id, route_id, stop_id, passed_at FROM bus_last_stops WHERE bus_id = \$1`).
+			WithArgs(passage.BusID).
+			WillReturnRows(sqlmock.NewRows([]string{"bus_id", "route_id", "stop_id", "passed_at"}).
+				AddRow(passage.BusID, passage.RouteID, passage.StopID, passage.PassedAt))
+		mock.ExpectQuery(`SELECT bus_id, route_id, stop_id, passed_at FROM bus_last_stops WHERE bus_id = \$1`).
+			WithArgs("nonexistent").
+			WillReturnError(sql.ErrNoRows)
+
+		err := repo.SaveLastStop(passage)
+		if err != nil {
+			t.Errorf("Ошибка при сохранении последней остановки: %v", err)
+		}
+		retrieved, err := repo.GetLastStop(passage.BusID)
+		if err != nil {
+			t.Errorf("Ошибка при получении последней остановки: %v", err)
+		}
+		if !reflect.DeepEqual(passage, retrieved) {
+			t.Errorf("Полученная остановка не совпадает: ожидалось %v, получено %v", passage, retrieved)
+		}
+		_, err = repo.GetLastStop("nonexistent")
+		if err == nil || err.Error() != "Stop passage not found" {
+			t.Errorf("Ожидалась ошибка 'Stop passage not found', получена: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("GetAllLastStops", func(t *testing.T) {
+		db, mock, repo := setupMockSegment(t)
+		defer db.Close()
+
+		passage := models.StopPassage{BusID: uuid.New().String(), RouteID: uuid.New().String(), StopID: "s2", PassedAt: time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC)}
+		mock.ExpectQuery(`SELECT bus_id, route_id, stop_id, passed_at FROM bus_last_stops`).
+			WillReturnRows(sqlmock.NewRows([]string{"bus_id", "route_id", "stop_id", "passed_at"}).
+				AddRow(passage.BusID, passage.RouteID, passage.StopID, passage.PassedAt))
+
+		passages, err := repo.GetAllLastStops()
+		if err != nil {
+			t.Errorf("Ошибка при получении последних остановок: %v", err)
+		}
+		if len(passages) != 1 || !reflect.DeepEqual(passage, passages[0]) {
+			t.Errorf("Полученные остановки не совпадают: ожидалось %v, получено %v", passage, passages)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+type PostgresServiceAlertRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresServiceAlertRepository(db *sql.DB) (*PostgresServiceAlertRepository, error) {
+	repo := &PostgresServiceAlertRepository{db: db}
+	return repo, nil
+}
+
+func (r *PostgresServiceAlertRepository) GetById(id string) (*models.ServiceAlert, error) {
+	alert := &models.ServiceAlert{}
+	err := r.db.QueryRow(`
+		SELECT id, title, description, cause, effect, severity, starts_at, ends_at, published_by
+		FROM service_alerts
+		WHERE id = $1`, id).Scan(
+		&alert.ID,
+		&alert.Title,
+		&alert.Description,
+		&alert.Cause,
+		&alert.Effect,
+		&alert.Severity,
+		&alert.StartsAt,
+		&alert.EndsAt,
+		&alert.PublishedBy,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("Service alert not found")
+		}
+		return nil, err
+	}
+	err = r.loadLinks(alert)
+	if err != nil {
+		return nil, err
+	}
+	return alert, nil
+}
+
+func (r *PostgresServiceAlertRepository) Add(alert *models.ServiceAlert) error {
+	if strings.TrimSpace(alert.ID) == "" {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return err
+		}
+		alert.ID = id.String()
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec(`INSERT into service_alerts (id, title, description, cause, effect, severity, starts_at, ends_at, published_by) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		alert.ID,
+		alert.Title,
+		alert.Description,
+		alert.Cause,
+		alert.Effect,
+		alert.Severity,
+		alert.StartsAt,
+		alert.EndsAt,
+		alert.PublishedBy,
+	)
+	if err != nil {
+		return err
+	}
+	for _, routeId := range alert.RouteIDs {
+		_, err = tx.Exec(`INSERT into service_alert_routes (alert_id, route_id) VALUES ($1, $2)`, alert.ID, routeId)
+		if err != nil {
+			return err
+		}
+	}
+	for _, stopId := range alert.StopIDs {
+		_, err = tx.Exec(`INSERT into service_alert_stops (alert_id, stop_id) VALUES ($1, $2)`, alert.ID, stopId)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// End closes the alert window at the given time
+func (r *PostgresServiceAlertRepository) End(id string, at time.Time) error {
+	result, err := r.db.Exec(`UPDATE service_alerts SET ends_at = $1 WHERE id = $2`, at, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("Service alert not found")
+	}
+	return nil
+}
+
+// GetActive returns the alerts whose window contains the given time, most
+// recently started first. Empty routeId and stopId don't filter.
+func (r *PostgresServiceAlertRepository) GetActive(at time.Time, routeId, stopId string) ([]models.ServiceAlert, error) {
+	rows, err := r.db.Query(`
+		SELECT id, title, description, cause, effect, severity, starts_at, ends_at, published_by
+		FROM service_alerts a
+		WHERE a.starts_at <= $1 AND (a.ends_at IS NULL OR a.ends_at > $1)
+		AND ($2 = '' OR EXISTS (SELECT 1 FROM service_alert_routes ar WHERE ar.alert_id = a.id AND ar.route_id = $2))
+		AND ($3 = '' OR EXISTS (SELECT 1 FROM service_alert_stops ast WHERE ast.alert_id = a.id AND ast.stop_id = $3))
+		ORDER BY a.starts_at DESC`, at, routeId, stopId)
+	if err != nil {
+		return nil, err
+	}
+	alerts, err := scanServiceAlerts(rows)
+	if err != nil {
+		return nil, err
+	}
+	for i := range alerts {
+		err = r.loadLinks(&alerts[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return alerts, nil
+}
+
+// loadLinks fills in the routes and stops affected by the alert
+func (r *PostgresServiceAlertRepository) loadLinks(alert *models.ServiceAlert) error {
+	var err error
+	alert.RouteIDs, err = r.queryIds(`SELECT route_id FROM service_alert_routes WHERE alert_id = $1`, alert.ID)
+	if err != nil {
+		return err
+	}
+	alert.StopIDs, err = r.queryIds(`SELECT stop_id FROM service_alert_stops WHERE alert_id = $1`, alert.ID)
+	return err
+}
+
+func (r *PostgresServiceAlertRepository) queryIds(query, alertId string) ([]string, error) {
+	rows, err := r.db.Query(query, alertId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func scanServiceAlerts(rows *sql.Rows) ([]models.ServiceAlert, error) {
+	defer rows.Close()
+	var alerts []models.ServiceAlert
+	for rows.Next() {
+		alert := &models.ServiceAlert{}
+		err := rows.Scan(
+			&alert.ID,
+			&alert.Title,
+			&alert.Description,
+			&alert.Cause,
+			&alert.Effect,
+			&alert.Severity,
+			&alert.StartsAt,
+			&alert.EndsAt,
+			&alert.PublishedBy,
+		)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, *alert)
+	}
+	return alerts, nil
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"database/sql/driver"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func setupMockServiceAlert(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *PostgresServiceAlertRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Ошибка создания mock базы данных: %v", err)
+	}
+	repo := &PostgresServiceAlertRepository{db: db}
+	return db, mock, repo
+}
+
+func newTestServiceAlert() *models.ServiceAlert {
+	return &models.ServiceAlert{
+		ID:          uuid.New().String(),
+		Title:       "Объезд по Садовой улице",
+		Description: "Из-за ремонта дороги автобусы следуют в объезд, остановка Театральная не обслуживается",
+		Cause:       models.CauseConstruction,
+		Effect:      models.EffectDetour,
+		Severity:    models.AlertWarning,
+		StartsAt:    time.Date(2025, 5, 12, 6, 0, 0, 0, time.UTC),
+		RouteIDs:    []string{uuid.New().String()},
+		StopIDs:     []string{uuid.New().String(), uuid.New().String()},
+		PublishedBy: uuid.New().String(),
+	}
+}
+
+func serviceAlertRow(alert *models.ServiceAlert) []driver.Value {
+	return []driver.Value{alert.ID, alert.Title, alert.Description, alert.Cause, alert.Effect, alert.Severity,
+		alert.StartsAt, nil, alert.PublishedBy}
+}
+
+func expectServiceAlertLinks(mock sqlmock.Sqlmock, alert *models.ServiceAlert) {
+	routes := sqlmock.NewRows([]string{"route_id"})
+	for _, id := range alert.RouteIDs {
+		routes.AddRow(id)
+	}
+	stops := sqlmock.NewRows([]string{"stop_id"})
+	for _, id := range alert.StopIDs {
+		stops.AddRow(id)
+	}
+	mock.ExpectQuery(`SELECT route_id FROM service_alert_routes WHERE alert_id = \$1`).
+		WithArgs(alert.ID).
+		WillReturnRows(routes)
+	mock.ExpectQuery(`SELECT stop_id FROM service_alert_stops WHERE alert_id = \$1`).
+		WithArgs(alert.ID).
+		WillReturnRows(stops)
+}
+
+func TestPostgresServiceAlertRepository(t *testing.T) {
+	columns := []string{"id", "title", "description", "cause", "effect", "severity", "starts_at", "ends_at", "published_by"}
+	selectQuery := `SELECT id, title, description, cause, effect, severity, starts_at, ends_at, published_by FROM service_alerts`
+
+	t.Run("NewPostgresServiceAlertRepository", func(t *testing.T) {
+		db, _, _ := setupMockServiceAlert(t)
+		defer db.Close()
+
+		repo, err := NewPostgresServiceAlertRepository(db)
+		if err != nil {
+			t.Errorf("Ошибка при создании репозитория: %v", err)
+		}
+		if repo == nil {
+			t.Error("Репозиторий не должен быть nil")
+		}
+	})
+
+	t.Run("GetById", func(t *testing.T) {
+		db, mock, repo := setupMockServiceAlert(t)
+		defer db.Close()
+
+		alert := newTestServiceAlert()
+		mock.ExpectQuery(selectQuery + ` WHERE id = \$1`).
+			WithArgs(alert.ID).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(serviceAlertRow(alert)...))
+		expectServiceAlertLinks(mock, alert)
+
+		retrieved, err := repo.GetById(alert.ID)
+		if err != nil {
+			t.Errorf("Ошибка при получении объявления по ID: %v", err)
+		}
+		if !reflect.DeepEqual(alert, retrieved) {
+			t.Errorf("Полученное объявление не совпадает: ожидалось %v, получено %v", alert, retrieved)
+		}
+
+		mock.ExpectQuery(selectQuery + ` WHERE id = \$1`).
+			WithArgs("nonexistent").
+			WillReturnError(sql.ErrNoRows)
+
+		_, err = repo.GetById("nonexistent")
+		if err == nil || err.Error() != "Service alert not found" {
+			t.Errorf("Ожидалась ошибка 'Service alert not found', получена: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("Add", func(t *testing.T) {
+		db, mock, repo := setupMockServiceAlert(t)
+		defer db.Close()
+
+		alert := newTestServiceAlert()
+		alert.ID = ""
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT into service_alerts \(id, title, description, cause, effect, severity, starts_at, ends_at, published_by\)`).
+			WithArgs(sqlmock.AnyArg(), alert.Title, alert.Description, alert.Cause, alert.Effect, alert.Severity,
+				alert.StartsAt, alert.EndsAt, alert.PublishedBy).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`INSERT into service_alert_routes \(alert_id, route_id\)`).
+			WithArgs(sqlmock.AnyArg(), alert.RouteIDs[0]).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		for _, stopId := range alert.StopIDs {
+			mock.ExpectExec(`INSERT into service_alert_stops \(alert_id, stop_id\)`).
+				WithArgs(sqlmock.AnyArg(), stopId).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+		}
+		mock.ExpectCommit()
+
+		err := repo.Add(alert)
+		if err != nil {
+			t.Errorf("Ошибка при добавлении объявления: %v", err)
+		}
+		if alert.ID == "" {
+			t.Error("ID объявления должен быть сгенерирован")
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("End", func(t *testing.T) {
+		db, mock, repo := setupMockServiceAlert(t)
+		defer db.Close()
+
+		at := time.Date(2025, 5, 20, 18, 0, 0, 0, time.UTC)
+		mock.ExpectExec(`UPDATE service_alerts SET ends_at = \$1 WHERE id = \$2`).
+			WithArgs(at, "alert-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE service_alerts SET ends_at = \$1 WHERE id = \$2`).
+			WithArgs(at, "nonexistent").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.End("alert-1", at)
+		if err != nil {
+			t.Errorf("Ошибка при завершении объявления: %v", err)
+		}
+		err = repo.End("nonexistent", at)
+		if err == nil || err.Error() != "Service alert not found" {
+			t.Errorf("Ожидалась ошибка 'Service alert not found', получена: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("GetActive", func(t *testing.T) {
+		db, mock, repo := setupMockServiceAlert(t)
+		defer db.Close()
+
+		alert := newTestServiceAlert()
+		at := time.Date(2025, 5, 13, 9, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`FROM service_alerts a WHERE a.starts_at <= \$1 AND \(a.ends_at IS NULL OR a.ends_at > \$1\)`).
+			WithArgs(at, alert.RouteIDs[0], "").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(serviceAlertRow(alert)...))
+		expectServiceAlertLinks(mock, alert)
+
+		alerts, err := repo.GetActive(at, alert.RouteIDs[0], "")
+		if err != nil {
+			t.Errorf("Ошибка при получении действующих объявлений: %v", err)
+		}
+		if len(alerts) != 1 || !reflect.DeepEqual(*alert, alerts[0]) {
+			t.Errorf("Действующие объявления не совпадают: получено %v", alerts)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+type PostgresSessionRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSessionRepository(db *sql.DB) (*PostgresSessionRepository, error) {
+	repo := &PostgresSessionRepository{db: db}
+	return repo, nil
+}
+
+func (r *PostgresSessionRepository) GetById(id string) (*models.Session, error) {
+	return r.getSession(`
+		SELECT id, user_id, refresh_token_hash, created_at, expires_at, revoked_at
+		FROM user_sessions
+		WHERE id = $1`, id)
+}
+
+func (r *PostgresSessionRepository) GetByRefreshTokenHash(hash string) (*models.Session, error) {
+	return r.getSession(`
+		SELECT id, user_id, refresh_token_hash, created_at, expires_at, revoked_at
+		FROM user_sessions
+		WHERE refresh_token_hash = $1`, hash)
+}
+
+// GetByRotatedRefreshTokenHash finds the session that has already exchanged
+// the refresh token
+func (r *PostgresSessionRepository) GetByRotatedRefreshTokenHash(hash string) (*models.Session, error) {
+	return r.getSession(`
+		SELECT s.id, s.user_id, s.refresh_token_hash, s.created_at, s.expires_at, s.revoked_at
+		FROM user_session_rotated_tokens r
+		JOIN user_sessions s ON s.id = r.session_id
+		WHERE r.refresh_token_hash = $1`, hash)
+}
+
+func (r *PostgresSessionRepository) getSession(query string, arg string) (*models.Session, error) {
+	session := &models.Session{}
+	err := r.db.QueryRow(query, arg).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.RefreshTokenHash,
+		&session.CreatedAt,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("Session not found")
+		}
+		return nil, err
+	}
+	return session, nil
+}
+
+func (r *PostgresSessionRepository) Add(session *models.Session) error {
+	if strings.TrimSpace(session.ID) == "" {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return err
+		}
+		session.ID = id.String()
+	}
+	_, err := r.db.Exec(`INSERT into user_sessions (id, user_id, refresh_token_hash, created_at, expires_at, revoked_at)
+VALUES ($1, $2, $3, $4, $5, $6)`,
+		session.ID,
+		session.UserID,
+		session.RefreshTokenHash,
+		session.CreatedAt,
+		session.ExpiresAt,
+		session.RevokedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Rotate replaces the refresh token of an active session. It only succeeds
+// for the current token, so of two refreshes with the same token one fails.
+// The old token is kept so that its reuse can be recognised.
+func (r *PostgresSessionRepository) Rotate(id, oldHash, newHash string, expiresAt time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE user_sessions SET refresh_token_hash = $1, expires_at = $2
+		WHERE id = $3 AND refresh_token_hash = $4 AND revoked_at IS NULL`,
+		newHash, expiresAt, id, oldHash)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("Session not found")
+	}
+	_, err = tx.Exec(`INSERT into user_session_rotated_tokens (refresh_token_hash, session_id) VALUES ($1, $2)`, oldHash, id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresSessionRepository) Revoke(id string, at time.Time) error {
+	result, err := r.db.Exec(`UPDATE user_sessions SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`, at, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("Session not found")
+	}
+	return nil
+}
+
+// RevokeByUserId revokes every active session of the user
+func (r *PostgresSessionRepository) RevokeByUserId(userId string, at time.Time) error {
+	_, err := r.db.Exec(`UPDATE user_sessions SET revoked_at = $1 WHERE user_id = $2 AND revoked_at IS NULL`, at, userId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
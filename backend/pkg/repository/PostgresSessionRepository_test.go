@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func setupMockSession(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *PostgresSessionRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Ошибка создания mock базы данных: %v", err)
+	}
+	repo := &PostgresSessionRepository{db: db}
+	return db, mock, repo
+}
+
+func newTestSession() *models.Session {
+	created := time.Date(2025, 5, 12, 8, 0, 0, 0, time.UTC)
+	return &models.Session{
+		ID:               uuid.New().String(),
+		UserID:           uuid.New().String(),
+		RefreshTokenHash: "5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8",
+		CreatedAt:        created,
+		ExpiresAt:        created.AddDate(0, 0, 30),
+	}
+}
+
+func TestPostgresSessionRepository(t *testing.T) {
+	columns := []string{"id", "user_id", "refresh_token_hash", "created_at", "expires_at", "revoked_at"}
+
+	t.Run("NewPostgresSessionRepository", func(t *testing.T) {
+		db, _, _ := setupMockSession(t)
+		defer db.Close()
+
+		repo, err := NewPostgresSessionRepository(db)
+		if err != nil {
+			t.Errorf("Ошибка при создании репозитория: %v", err)
+		}
+		if repo == nil {
+			t.Error("Репозиторий не должен быть nil")
+		}
+	})
+
+	t.Run("GetById", func(t *testing.T) {
+		db, mock, repo := setupMockSession(t)
+		defer db.Close()
+
+		session := newTestSession()
+		mock.ExpectQuery(`SELECT id, user_id, refresh_token_hash, created_at, expires_at, revoked_at FROM user_sessions WHERE id = \$1`).
+			WithArgs(session.ID).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(session.ID, session.UserID, session.RefreshTokenHash, session.CreatedAt, session.ExpiresAt, nil))
+		mock.ExpectQuery(`SELECT id, user_id, refresh_token_hash, created_at, expires_at, revoked_at FROM user_sessions WHERE id = \$1`).
+			WithArgs("missing").
+			WillReturnError(sql.ErrNoRows)
+
+		retrieved, err := repo.GetById(session.ID)
+		if err != nil {
+			t.Errorf("Ошибка при получении сессии: %v", err)
+		}
+		if !reflect.DeepEqual(session, retrieved) {
+			t.Errorf("Полученная сессия не совпадает: ожидалось %v, получено %v", session, retrieved)
+		}
+		_, err = repo.GetById("missing")
+		if err == nil || err.Error() != "Session not found" {
+			t.Errorf("Ожидалась ошибка 'Session not found', получена: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("GetByRefreshTokenHash", func(t *testing.T) {
+		db, mock, repo := setupMockSession(t)
+		defer db.Close()
+
+		session := newTestSession()
+		revoked := session.CreatedAt.Add(time.Hour)
+		session.RevokedAt = &revoked
+		mock.ExpectQuery(`SELECT id, user_id, refresh_token_hash, created_at, expires_at, revoked_at FROM user_sessions WHERE refresh_token_hash = \$1`).
+			WithArgs(session.RefreshTokenHash).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(session.ID, session.UserID, session.RefreshTokenHash, session.CreatedAt, session.ExpiresAt, revoked))
+
+		retrieved, err := repo.GetByRefreshTokenHash(session.RefreshTokenHash)
+		if err != nil {
+			t.Errorf("Ошибка при получении сессии: %v", err)
+		}
+		if !reflect.DeepEqual(session, retrieved) {
+			t.Errorf("Полученная сессия не совпадает: ожидалось %v, получено %v", session, retrieved)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("Add", func(t *testing.T) {
+		db, mock, repo := setupMockSession(t)
+		defer db.Close()
+
+		session := newTestSession()
+		session.ID = ""
+		mock.ExpectExec(`INSERT into user_sessions \(id, user_id, refresh_token_hash, created_at, expires_at, revoked_at\)`).
+			WithArgs(sqlmock.AnyArg(), session.UserID, session.RefreshTokenHash, session.CreatedAt, session.ExpiresAt, session.RevokedAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.Add(session)
+		if err != nil {
+			t.Errorf("Ошибка при добавлении сессии: %v", err)
+		}
+		if session.ID == "" {
+			t.Error("ID сессии должен быть сгенерирован")
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("GetByRotatedRefreshTokenHash", func(t *testing.T) {
+		db, mock, repo := setupMockSession(t)
+		defer db.Close()
+
+		session := newTestSession()
+		mock.ExpectQuery(`SELECT s.id, s.user_id, s.refresh_token_hash, s.created_at, s.expires_at, s.revoked_at FROM user_session_rotated_tokens r JOIN user_sessions s ON s.id = r.session_id WHERE r.refresh_token_hash = \$1`).
+			WithArgs("old-hash").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(session.ID, session.UserID, session.RefreshTokenHash, session.CreatedAt, session.ExpiresAt, nil))
+
+		retrieved, err := repo.GetByRotatedRefreshTokenHash("old-hash")
+		if err != nil {
+			t.Errorf("Ошибка при получении сессии: %v", err)
+		}
+		if !reflect.DeepEqual(session, retrieved) {
+			t.Errorf("Полученная сессия не совпадает: ожидалось %v, получено %v", session, retrieved)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("Rotate", func(t *testing.T) {
+		db, mock, repo := setupMockSession(t)
+		defer db.Close()
+
+		session := newTestSession()
+		expires := session.ExpiresAt.AddDate(0, 0, 1)
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE user_sessions SET refresh_token_hash = \$1, expires_at = \$2 WHERE id = \$3 AND refresh_token_hash = \$4 AND revoked_at IS NULL`).
+			WithArgs("new-hash", expires, session.ID, session.RefreshTokenHash).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT into user_session_rotated_tokens \(refresh_token_hash, session_id\) VALUES \(\$1, \$2\)`).
+			WithArgs(session.RefreshTokenHash, session.ID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE user_sessions SET refresh_token_hash = \$1, expires_at = \$2 WHERE id = \$3 AND refresh_token_hash = \$4 AND revoked_at IS NULL`).
+			WithArgs("newer-hash", expires, session.ID, session.RefreshTokenHash).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.Rotate(session.ID, session.RefreshTokenHash, "new-hash", expires)
+		if err != nil {
+			t.Errorf("Ошибка при обновлении сессии: %v", err)
+		}
+		err = repo.Rotate(session.ID, session.RefreshTokenHash, "newer-hash", expires)
+		if err == nil || err.Error() != "Session not found" {
+			t.Errorf("Ожидалась ошибка 'Session not found', получена: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		db, mock, repo := setupMockSession(t)
+		defer db.Close()
+
+		at := time.Date(2025, 5, 12, 18, 0, 0, 0, time.UTC)
+		mock.ExpectExec(`UPDATE user_sessions SET revoked_at = \$1 WHERE id = \$2 AND revoked_at IS NULL`).
+			WithArgs(at, "session-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE user_sessions SET revoked_at = \$1 WHERE id = \$2 AND revoked_at IS NULL`).
+			WithArgs(at, "session-1").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.Revoke("session-1", at)
+		if err != nil {
+			t.Errorf("Ошибка при завершении сессии: %v", err)
+		}
+		err = repo.Revoke("session-1", at)
+		if err == nil || err.Error() != "Session not found" {
+			t.Errorf("Ожидалась ошибка 'Session not found', получена: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("RevokeByUserId", func(t *testing.T) {
+		db, mock, repo := setupMockSession(t)
+		defer db.Close()
+
+		at := time.Date(2025, 5, 12, 18, 0, 0, 0, time.UTC)
+		mock.ExpectExec(`UPDATE user_sessions SET revoked_at = \$1 WHERE user_id = \$2 AND revoked_at IS NULL`).
+			WithArgs(at, "user-1").
+			WillReturnResult(sqlmock.NewResult(0, 3))
+
+		err := repo.RevokeByUserId("user-1", at)
+		if err != nil {
+			t.Errorf("Ошибка при завершении сессий: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+}
@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+type PostgresTripRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresTripRepository(db *sql.DB) (*PostgresTripRepository, error) {
+	repo := &PostgresTripRepository{db: db}
+	return repo, nil
+}
+
+func (r *PostgresTripRepository) GetById(id string) (*models.Trip, error) {
+	trip := &models.Trip{}
+	err := r.db.QueryRow(`
+		SELECT id, duty_id, route_id, starts_at, ends_at
+		FROM trips
+		WHERE id = $1`, id).Scan(
+		&trip.ID,
+		&trip.DutyID,
+		&trip.RouteID,
+		&trip.StartsAt,
+		&trip.EndsAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("Trip not found")
+		}
+		return nil, err
+	}
+	trip.StopTimes, err = r.getStopTimes(trip.ID)
+	if err != nil {
+		return nil, err
+	}
+	return trip, nil
+}
+
+func (r *PostgresTripRepository) Add(trip *models.Trip) error {
+	if strings.TrimSpace(trip.ID) == "" {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return err
+		}
+		trip.ID = id.String()
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT into trips (id, duty_id, route_id, starts_at, ends_at) 
+VALUES ($1, $2, $3, $4, $5)`, trip.ID, trip.DutyID, trip.RouteID, trip.StartsAt, trip.EndsAt)
+	if err != nil {
+		return err
+	}
+	for _, stopTime := range trip.StopTimes {
+		_, err = tx.Exec(`INSERT into trip_stop_times (trip_id, stop_sequence, stop_id, arrival, departure) 
+VALUES ($1, $2, $3, $4, $5)`, trip.ID, stopTime.StopSequence, stopTime.StopID, stopTime.Arrival, stopTime.Departure)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresTripRepository) DeleteById(id string) error {
+	exist, err := r.GetById(id)
+	if exist == nil {
+		return errors.New("Trip not found")
+	}
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM trip_stop_times WHERE trip_id = $1`, id)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`DELETE FROM trips WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresTripRepository) GetByDutyId(dutyId string) ([]models.Trip, error) {
+	rows, err := r.db.Query(`
+		SELECT id, duty_id, route_id, starts_at, ends_at
+		FROM trips
+		WHERE duty_id = $1
+		ORDER BY starts_at
+	`, dutyId)
+	if err != nil {
+		return nil, err
+	}
+	return r.scanTrips(rows)
+}
+
+// GetByBusId returns the trips of the bus overlapping [from, to].
+func (r *PostgresTripRepository) GetByBusId(busId string, from, to time.Time) ([]models.Trip, error) {
+	rows, err := r.db.Query(`
+		SELECT t.id, t.duty_id, t.route_id, t.starts_at, t.ends_at
+		FROM trips t
+		JOIN duties d ON d.id = t.duty_id
+		WHERE d.bus_id = $1 AND t.starts_at <= $3 AND t.ends_at >= $2
+		ORDER BY t.starts_at
+	`, busId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return r.scanTrips(rows)
+}
+
+func (r *PostgresTripRepository) SetActual(tripId string, stopSequence int, arrival, departure time.Time) error {
+	_, err := r.db.Exec(`UPDATE trip_stop_times SET actual_arrival = $1, actual_departure = $2 WHERE trip_id = $3 AND stop_sequence = $4`,
+		arrival, departure, tripId, stopSequence)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetObserved returns the stop times of trips starting in [from, to) the
+// bus was seen at.
+func (r *PostgresTripRepository) GetObserved(from, to time.Time) ([]models.ObservedStopTime, error) {
+	rows, err := r.db.Query(`
+		SELECT t.id, t.route_id, d.driver_id,
+			s.stop_sequence = (SELECT MAX(m.stop_sequence) FROM trip_stop_times m WHERE m.trip_id = s.trip_id),
+			s.stop_sequence, s.stop_id, s.arrival, s.departure, s.actual_arrival, s.actual_departure
+		FROM trip_stop_times s
+		JOIN trips t ON t.id = s.trip_id
+		JOIN duties d ON d.id = t.duty_id
+		WHERE t.starts_at >= $1 AND t.starts_at < $2 AND s.actual_arrival IS NOT NULL
+		ORDER BY t.starts_at, s.stop_sequence
+	`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	var observed []models.ObservedStopTime
+	for rows.Next() {
+		stopTime := &models.ObservedStopTime{}
+		err := rows.Scan(
+			&stopTime.TripID,
+			&stopTime.RouteID,
+			&stopTime.DriverID,
+			&stopTime.Terminal,
+			&stopTime.StopSequence,
+			&stopTime.StopID,
+			&stopTime.Arrival,
+			&stopTime.Departure,
+			&stopTime.ActualArrival,
+			&stopTime.ActualDeparture,
+		)
+		if err != nil {
+			return nil, err
+		}
+		observed = append(observed, *stopTime)
+	}
+	return observed, nil
+}
+
+// GetStopDepartures returns the trips scheduled to leave the stop in
+// [from, to), earliest first. Trips ending at the stop don't leave it and
+// are left out.
+func (r *PostgresTripRepository) GetStopDepartures(stopId string, from, to time.Time) ([]models.ScheduledDeparture, error) {
+	rows, err := r.db.Query(`
+		SELECT t.id, t.route_id, d.bus_id, s.stop_sequence, s.departure, s.actual_departure
+		FROM trip_stop_times s
+		JOIN trips t ON t.id = s.trip_id
+		JOIN duties d ON d.id = t.duty_id
+		WHERE s.stop_id = $1 AND s.departure >= $2 AND s.departure < $3
+		AND s.stop_sequence < (SELECT MAX(m.stop_sequence) FROM trip_stop_times m WHERE m.trip_id = s.trip_id)
+		ORDER BY s.departure
+	`, stopId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var departures []models.ScheduledDeparture
+	for rows.Next() {
+		departure := &models.ScheduledDeparture{}
+		err := rows.Scan(
+			&departure.TripID,
+			&departure.RouteID,
+			&departure.BusID,
+			&departure.StopSequence,
+			&departure.Departure,
+			&departure.ActualDeparture,
+		)
+		if err != nil {
+			return nil, err
+		}
+		departures = append(departures, *departure)
+	}
+	return departures, nil
+}
+
+func (r *PostgresTripRepository) getStopTimes(tripId string) ([]models.TripStopTime, error) {
+	rows, err := r.db.Query(`
+		SELECT stop_sequence, stop_id, arrival, departure, actual_arrival, actual_departure
+		FROM trip_stop_times
+		WHERE trip_id = $1
+		ORDER BY stop_sequence
+	`, tripId)
+	if err != nil {
+		return nil, err
+	}
+	var stopTimes []models.TripStopTime
+	for rows.Next() {
+		stopTime := &models.TripStopTime{}
+		err := rows.Scan(
+			&stopTime.StopSequence,
+			&stopTime.StopID,
+			&stopTime.Arrival,
+			&stopTime.Departure,
+			&stopTime.ActualArrival,
+			&stopTime.ActualDeparture,
+		)
+		if err != nil {
+			return nil, err
+		}
+		stopTimes = append(stopTimes, *stopTime)
+	}
+	return stopTimes, nil
+}
+
+// scanTrips reads the trips and loads their stop times.
+func (r *PostgresTripRepository) scanTrips(rows *sql.Rows) ([]models.Trip, error) {
+	var trips []models.Trip
+	for rows.Next() {
+		trip := &models.Trip{}
+		err := rows.Scan(
+			&trip.ID,
+			&trip.DutyID,
+			&trip.RouteID,
+			&trip.StartsAt,
+			&trip.EndsAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		trips = append(trips, *trip)
+	}
+	rows.Close()
+	for i := range trips {
+		stopTimes, err := r.getStopTimes(trips[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		trips[i].StopTimes = stopTimes
+	}
+	return trips, nil
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"backend/pkg/models"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func setupMockTrip(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *PostgresTripRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Ошибка создания mock базы данных: %v", err)
+	}
+	repo := &PostgresTripRepository{db: db}
+	return db, mock, repo
+}
+
+func newTestTrip() *models.Trip {
+	start := time.Date(2025, 3, 3, 7, 0, 0, 0, time.UTC)
+	return &models.Trip{
+		ID:       uuid.New().String(),
+		DutyID:   uuid.New().String(),
+		RouteID:  uuid.New().String(),
+		StartsAt: start,
+		EndsAt:   start.Add(10 * time.Minute),
+		StopTimes: []models.TripStopTime{
+			{StopSequence: 1, StopID: uuid.New().String(), Arrival: start, Departure: start},
+			{StopSequence: 2, StopID: uuid.New().String(), Arrival: start.Add(10 * time.Minute), Departure: start.Add(10 * time.Minute)},
+		},
+	}
+}
+
+func TestPostgresTripRepository(t *testing.T) {
+	tripColumns := []string{"id", "duty_id", "route_id", "starts_at", "ends_at"}
+	stopTimeColumns := []string{"stop_sequence", "stop_id", "arrival", "departure", "actual_arrival", "actual_departure"}
+
+	t.Run("NewPostgresTripRepository", func(t *testing.T) {
+		db, _, _ := setupMockTrip(t)
+		defer db.Close()
+
+		repo, err := NewPostgresTripRepository(db)
+		if err != nil {
+			t.Errorf("Ошибка при создании репозитория: %v", err)
+		}
+		if repo == nil {
+			t.Error("Репозиторий не должен быть nil")
+		}
+	})
+
+	t.Run("GetById", func(t *testing.T) {
+		db, mock, repo := setupMockTrip(t)
+		defer db.Close()
+
+		trip := newTestTrip()
+		mock.ExpectQuery(`SELECT id, duty_id, route_id, starts_at, ends_at FROM trips WHERE id = \$1`).
+			WithArgs(trip.ID).
+			WillReturnRows(sqlmock.NewRows(tripColumns).
+				AddRow(trip.ID, trip.DutyID, trip.RouteID, trip.StartsAt, trip.EndsAt))
+		rows := sqlmock.NewRows(stopTimeColumns)
+		for _, stopTime := range trip.StopTimes {
+			rows.AddRow(stopTime.StopSequence, stopTime.StopID, stopTime.Arrival, stopTime.Departure, nil, nil)
+		}
+		mock.ExpectQuery(`SELECT stop_sequence, stop_id, arrival, departure, actual_arrival, actual_departure FROM trip_stop_times WHERE trip_id = \$1 ORDER BY stop_sequence`).
+			WithArgs(trip.ID).
+			WillReturnRows(rows)
+
+		retrieved, err := repo.GetById(trip.ID)
+		if err != nil {
+			t.Errorf("Ошибка при получении рейса по ID: %v", err)
+		}
+		if !reflect.DeepEqual(trip, retrieved) {
+			t.Errorf("Полученный рейс не совпадает: ожидалось %v, получено %v", trip, retrieved)
+		}
+
+		mock.ExpectQuery(`SELECT id, duty_id, route_id, starts_at, ends_at FROM trips WHERE id = \$1`).
+			WithArgs("nonexistent").
+			WillReturnError(sql.ErrNoRows)
+
+		_, err = repo.GetById("nonexistent")
+		if err == nil || err.Error() != "Trip not found" {
+			t.Errorf("Ожидалась ошибка 'Trip not found', получена: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("Add", func(t *testing.T) {
+		db, mock, repo := setupMockTrip(t)
+		defer db.Close()
+
+		trip := newTestTrip()
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT into trips \(id, duty_id, route_id, starts_at, ends_at\) VALUES \(\$1, \$2, \$3, \$4, \$5\)`).
+			WithArgs(trip.ID, trip.DutyID, trip.RouteID, trip.StartsAt, trip.EndsAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		for _, stopTime := range trip.StopTimes {
+			mock.ExpectExec(`INSERT into trip_stop_times \(trip_id, stop_sequence, stop_id, arrival, departure\) VALUES \(\$1, \$2, \$3, \$4, \$5\)`).
+				WithArgs(trip.ID, stopTime.StopSequence, stopTime.StopID, stopTime.Arrival, stopTime.Departure).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+		}
+		mock.ExpectCommit()
+
+		err := repo.Add(trip)
+		if err != nil {
+			t.Errorf("Ошибка при добавлении рейса: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("SetActual", func(t *testing.T) {
+		db, mock, repo := setupMockTrip(t)
+		defer db.Close()
+
+		trip := newTestTrip()
+		arrival := trip.StartsAt.Add(time.Minute)
+		departure := arrival.Add(30 * time.Second)
+		mock.ExpectExec(`UPDATE trip_stop_times SET actual_arrival = \$1, actual_departure = \$2 WHERE trip_id = \$3 AND stop_sequence = \$4`).
+			WithArgs(arrival, departure, trip.ID, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.SetActual(trip.ID, 1, arrival, departure)
+		if err != nil {
+			t.Errorf("Ошибка при сохранении фактического времени: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("GetObserved", func(t *testing.T) {
+		db, mock, repo := setupMockTrip(t)
+		defer db.Close()
+
+		trip := newTestTrip()
+		driverID := uuid.New().String()
+		actual := trip.StartsAt.Add(2 * time.Minute)
+		from := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(0, 0, 1)
+		stopTime := trip.StopTimes[1]
+		mock.ExpectQuery(`SELECT t\.id, t\.route_id, d\.driver_id, .* FROM trip_stop_times s JOIN trips t ON t\.id = s\.trip_id JOIN duties d ON d\.id = t\.duty_id WHERE t\.starts_at >= \$1 AND t\.starts_at < \$2 AND s\.actual_arrival IS NOT NULL`).
+			WithArgs(from, to).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "route_id", "driver_id", "terminal", "stop_sequence", "stop_id", "arrival", "departure", "actual_arrival", "actual_departure"}).
+				AddRow(trip.ID, trip.RouteID, driverID, true, stopTime.StopSequence, stopTime.StopID, stopTime.Arrival, stopTime.Departure, actual, actual))
+
+		observed, err := repo.GetObserved(from, to)
+		if err != nil {
+			t.Errorf("Ошибка при получении фактических времен: %v", err)
+		}
+		if len(observed) != 1 || observed[0].DriverID != driverID || !observed[0].Terminal || !observed[0].ActualArrival.Equal(actual) {
+			t.Errorf("Полученные времена не совпадают: получено %v", observed)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+
+	t.Run("GetStopDepartures", func(t *testing.T) {
+		db, mock, repo := setupMockTrip(t)
+		defer db.Close()
+
+		trip := newTestTrip()
+		busID := uuid.New().String()
+		from := time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC)
+		to := from.Add(2 * time.Hour)
+		stopTime := trip.StopTimes[0]
+		mock.ExpectQuery(`SELECT t\.id, t\.route_id, d\.bus_id, s\.stop_sequence, s\.departure, s\.actual_departure FROM trip_stop_times s JOIN trips t ON t\.id = s\.trip_id JOIN duties d ON d\.id = t\.duty_id WHERE s\.stop_id = \$1 AND s\.departure >= \$2 AND s\.departure < \$3`).
+			WithArgs(stopTime.StopID, from, to).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "route_id", "bus_id", "stop_sequence", "departure", "actual_departure"}).
+				AddRow(trip.ID, trip.RouteID, busID, stopTime.StopSequence, stopTime.Departure, nil))
+
+		departures, err := repo.GetStopDepartures(stopTime.StopID, from, to)
+		if err != nil {
+			t.Errorf("Ошибка при получении отправлений с остановки: %v", err)
+		}
+		if len(departures) != 1 || departures[0].BusID != busID || !departures[0].Departure.Equal(stopTime.Departure) || departures[0].ActualDeparture != nil {
+			t.Errorf("Полученные отправления не совпадают: получено %v", departures)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Не все ожидаемые SQL запросы были выполнены: %v", err)
+		}
+	})
+}
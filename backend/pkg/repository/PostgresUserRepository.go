@@ -20,7 +20,7 @@ func NewPostgresUserRepository(db *sql.DB) (*PostgresUserRepository, error) {
 func (r *PostgresUserRepository) GetById(id string) (*models.User, error) {
 	user := &models.User{}
 	err := r.db.QueryRow(`
-		SELECT id, username, password_hash 
+		SELECT id, username, password_hash
 		FROM users 
 		WHERE id = $1`, id).Scan(
 		&user.ID,
@@ -33,16 +33,19 @@ func (r *PostgresUserRepository) GetById(id string) (*models.User, error) {
 		}
 		return nil, err
 	}
-
+	user.Roles, err = r.getRoles(user.ID)
+	if err != nil {
+		return nil, err
+	}
 	return user, nil
 }
 
-func (r *PostgresUserRepository) GetByUsername(username, password string) (*models.User, error) {
+func (r *PostgresUserRepository) GetByUsername(username string) (*models.User, error) {
 	user := &models.User{}
 	err := r.db.QueryRow(`
 		SELECT id, username, password_hash
 		FROM users 
-		WHERE username = $1 AND password_hash = $2`, username, password).Scan(
+		WHERE username = $1`, username).Scan(
 		&user.ID,
 		&user.Username,
 		&user.Password,
@@ -53,7 +56,10 @@ func (r *PostgresUserRepository) GetByUsername(username, password string) (*mode
 		}
 		return nil, err
 	}
-
+	user.Roles, err = r.getRoles(user.ID)
+	if err != nil {
+		return nil, err
+	}
 	return user, nil
 }
 
@@ -74,7 +80,16 @@ func (r *PostgresUserRepository) Add(user *models.User) error {
 		}
 		user.ID = id.String()
 	}
-	_, err := r.db.Exec(`INSERT into users (id, username, password_hash) 
+	if len(user.Roles) == 0 {
+		user.Roles = []string{models.RoleViewer}
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT into users (id, username, password_hash) 
 VALUES ($1, $2, $3)`, &user.ID,
 		&user.Username,
 		&user.Password,
@@ -82,5 +97,110 @@ VALUES ($1, $2, $3)`, &user.ID,
 	if err != nil {
 		return err
 	}
+	for _, role := range user.Roles {
+		_, err = tx.Exec(`INSERT into user_roles (user_id, role) VALUES ($1, $2)`, user.ID, role)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresUserRepository) GetAll() ([]models.User, error) {
+	rows, err := r.db.Query(`
+		SELECT id, username, password_hash
+		FROM users
+		ORDER BY username
+	`)
+	if err != nil {
+		return nil, err
+	}
+	var users []models.User
+	for rows.Next() {
+		user := &models.User{}
+		err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.Password,
+		)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+	rows.Close()
+	for i := range users {
+		users[i].Roles, err = r.getRoles(users[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return users, nil
+}
+
+// UpdatePassword replaces the stored password hash of the user
+func (r *PostgresUserRepository) UpdatePassword(userId, passwordHash string) error {
+	result, err := r.db.Exec(`UPDATE users SET password_hash = $1 WHERE id = $2`, passwordHash, userId)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("User not found")
+	}
 	return nil
 }
+
+// GrantRole adds the role to the user, granting a role twice is a no-op
+func (r *PostgresUserRepository) GrantRole(userId, role string) error {
+	_, err := r.db.Exec(`INSERT into user_roles (user_id, role) VALUES ($1, $2) ON CONFLICT DO NOTHING`, userId, role)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *PostgresUserRepository) RevokeRole(userId, role string) error {
+	result, err := r.db.Exec(`DELETE FROM user_roles WHERE user_id = $1 AND role = $2`, userId, role)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("Role not found")
+	}
+	return nil
+}
+
+func (r *PostgresUserRepository) CountByRole(role string) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM user_roles WHERE role = $1`, role).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *PostgresUserRepository) getRoles(userId string) ([]string, error) {
+	rows, err := r.db.Query(`SELECT role FROM user_roles WHERE user_id = $1 ORDER BY role`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	roles := []string{}
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/repository"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// absenceKinds describes each kind of absence in error messages.
+var absenceKinds = map[string]string{
+	models.AbsenceVacation:  "on vacation",
+	models.AbsenceSickLeave: "on sick leave",
+	models.AbsenceTraining:  "in training",
+}
+
+type AbsenceService struct {
+	repo       repository.IDriverAbsenceRepository
+	driverRepo repository.IDriverRepository
+	dutyRepo   repository.IDutyRepository
+}
+
+func NewAbsenceService(r repository.IDriverAbsenceRepository, driverRepo repository.IDriverRepository, dutyRepo repository.IDutyRepository) *AbsenceService {
+	s := &AbsenceService{r, driverRepo, dutyRepo}
+	return s
+}
+
+func (s AbsenceService) GetById(id string) (*models.DriverAbsence, error) {
+	absence, err := s.repo.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if absence == nil {
+		return nil, errors.New("Absence not found")
+	}
+	return absence, nil
+}
+
+// Add records the absence unless the driver is already absent or rostered on
+// one of its days; such duties have to be moved first.
+func (s AbsenceService) Add(absence *models.DriverAbsence) error {
+	driver, err := s.driverRepo.GetById(absence.DriverID)
+	if driver == nil {
+		return errors.New("Driver not found")
+	}
+	if err != nil {
+		return err
+	}
+	absence.Kind = strings.ToLower(strings.TrimSpace(absence.Kind))
+	if _, ok := absenceKinds[absence.Kind]; !ok {
+		return fmt.Errorf("Unknown absence kind %s", absence.Kind)
+	}
+	absence.StartsOn = startOfDay(absence.StartsOn)
+	absence.EndsOn = startOfDay(absence.EndsOn)
+	if absence.EndsOn.Before(absence.StartsOn) {
+		return errors.New("Absence can't end before it starts")
+	}
+	overlapping, err := s.repo.GetOverlapping(absence.DriverID, absence.StartsOn, absence.EndsOn)
+	if err != nil {
+		return err
+	}
+	if len(overlapping) > 0 {
+		return absenceError("Driver is already", overlapping[0])
+	}
+	duties, err := s.dutyRepo.GetByDriverId(absence.DriverID, absence.StartsOn, absence.EndsOn.AddDate(0, 0, 1))
+	if err != nil {
+		return err
+	}
+	if len(duties) > 0 {
+		return fmt.Errorf("Driver is rostered on route %s on %s, move the duty first",
+			duties[0].RouteID, duties[0].Date.Format(time.DateOnly))
+	}
+	return s.repo.Add(absence)
+}
+
+func (s AbsenceService) DeleteById(id string) error {
+	err := s.repo.DeleteById(id)
+	return err
+}
+
+func (s AbsenceService) GetByDriverId(driverId string) ([]models.DriverAbsence, error) {
+	return s.repo.GetByDriverId(driverId)
+}
+
+// GetAvailable returns drivers without an absence on the date.
+func (s AbsenceService) GetAvailable(date time.Time) ([]models.Driver, error) {
+	drivers, err := s.driverRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	absences, err := s.repo.GetByDate(startOfDay(date))
+	if err != nil {
+		return nil, err
+	}
+	absent := make(map[string]bool)
+	for _, absence := range absences {
+		absent[absence.DriverID] = true
+	}
+	available := []models.Driver{}
+	for _, driver := range drivers {
+		if !absent[driver.ID] {
+			available = append(available, driver)
+		}
+	}
+	return available, nil
+}
+
+// CheckAvailable returns an error if the driver is absent on any day
+// touched by [from, to].
+func (s AbsenceService) CheckAvailable(driverId string, from, to time.Time) error {
+	absences, err := s.repo.GetOverlapping(driverId, startOfDay(from), startOfDay(to))
+	if err != nil {
+		return err
+	}
+	if len(absences) > 0 {
+		return absenceError("Driver is", absences[0])
+	}
+	return nil
+}
+
+func absenceError(prefix string, absence models.DriverAbsence) error {
+	return fmt.Errorf("%s %s from %s to %s", prefix, absenceKinds[absence.Kind],
+		absence.StartsOn.Format(time.DateOnly), absence.EndsOn.Format(time.DateOnly))
+}
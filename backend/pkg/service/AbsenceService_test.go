@@ -0,0 +1,175 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"errors"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+type MockDriverAbsenceRepository struct {
+	getByIdResp        *models.DriverAbsence
+	getByIdErr         error
+	addErr             error
+	deleteByIdErr      error
+	getByDriverIdResp  []models.DriverAbsence
+	getByDriverIdErr   error
+	getOverlappingResp []models.DriverAbsence
+	getOverlappingErr  error
+	getByDateResp      []models.DriverAbsence
+	getByDateErr       error
+	overlappingFrom    time.Time
+	overlappingTo      time.Time
+}
+
+func (m *MockDriverAbsenceRepository) GetById(id string) (*models.DriverAbsence, error) {
+	return m.getByIdResp, m.getByIdErr
+}
+
+func (m *MockDriverAbsenceRepository) Add(absence *models.DriverAbsence) error {
+	return m.addErr
+}
+
+func (m *MockDriverAbsenceRepository) DeleteById(id string) error {
+	return m.deleteByIdErr
+}
+
+func (m *MockDriverAbsenceRepository) GetByDriverId(driverId string) ([]models.DriverAbsence, error) {
+	return m.getByDriverIdResp, m.getByDriverIdErr
+}
+
+func (m *MockDriverAbsenceRepository) GetOverlapping(driverId string, from, to time.Time) ([]models.DriverAbsence, error) {
+	m.overlappingFrom, m.overlappingTo = from, to
+	return m.getOverlappingResp, m.getOverlappingErr
+}
+
+func (m *MockDriverAbsenceRepository) GetByDate(date time.Time) ([]models.DriverAbsence, error) {
+	return m.getByDateResp, m.getByDateErr
+}
+
+func TestAbsenceService_Add(t *testing.T) {
+	driver := &models.Driver{ID: uuid.New().String(), Name: "John", Surname: "Doe"}
+	newAbsence := func() *models.DriverAbsence {
+		return &models.DriverAbsence{
+			DriverID: driver.ID,
+			Kind:     " Vacation ",
+			StartsOn: time.Date(2025, 7, 1, 10, 0, 0, 0, time.UTC),
+			EndsOn:   time.Date(2025, 7, 14, 0, 0, 0, 0, time.UTC),
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		service := NewAbsenceService(&MockDriverAbsenceRepository{}, &MockDriverRepository{getByIdResp: driver}, &MockDutyRepository{})
+		absence := newAbsence()
+
+		err := service.Add(absence)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if absence.Kind != models.AbsenceVacation || absence.StartsOn.Hour() != 0 {
+			t.Errorf("Expected normalized kind and start day, got %v", absence)
+		}
+	})
+
+	t.Run("Unknown kind", func(t *testing.T) {
+		service := NewAbsenceService(&MockDriverAbsenceRepository{}, &MockDriverRepository{getByIdResp: driver}, &MockDutyRepository{})
+		absence := newAbsence()
+		absence.Kind = "holiday"
+
+		err := service.Add(absence)
+		if err == nil || err.Error() != "Unknown absence kind holiday" {
+			t.Errorf("Expected 'Unknown absence kind holiday' error, got %v", err)
+		}
+	})
+
+	t.Run("Ends before start", func(t *testing.T) {
+		service := NewAbsenceService(&MockDriverAbsenceRepository{}, &MockDriverRepository{getByIdResp: driver}, &MockDutyRepository{})
+		absence := newAbsence()
+		absence.EndsOn = absence.StartsOn.AddDate(0, 0, -1)
+
+		err := service.Add(absence)
+		if err == nil || err.Error() != "Absence can't end before it starts" {
+			t.Errorf("Expected 'Absence can't end before it starts' error, got %v", err)
+		}
+	})
+
+	t.Run("Overlaps existing absence", func(t *testing.T) {
+		existing := models.DriverAbsence{
+			DriverID: driver.ID,
+			Kind:     models.AbsenceTraining,
+			StartsOn: time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC),
+			EndsOn:   time.Date(2025, 7, 11, 0, 0, 0, 0, time.UTC),
+		}
+		service := NewAbsenceService(&MockDriverAbsenceRepository{getOverlappingResp: []models.DriverAbsence{existing}}, &MockDriverRepository{getByIdResp: driver}, &MockDutyRepository{})
+
+		err := service.Add(newAbsence())
+		if err == nil || err.Error() != "Driver is already in training from 2025-07-10 to 2025-07-11" {
+			t.Errorf("Expected overlap error, got %v", err)
+		}
+	})
+
+	t.Run("Driver rostered during the absence", func(t *testing.T) {
+		duty := models.Duty{ID: uuid.New().String(), DriverID: driver.ID, RouteID: "route-1", Date: time.Date(2025, 7, 3, 0, 0, 0, 0, time.UTC)}
+		service := NewAbsenceService(&MockDriverAbsenceRepository{}, &MockDriverRepository{getByIdResp: driver}, &MockDutyRepository{getByDriverIdResp: []models.Duty{duty}})
+
+		err := service.Add(newAbsence())
+		if err == nil || err.Error() != "Driver is rostered on route route-1 on 2025-07-03, move the duty first" {
+			t.Errorf("Expected rostered duty error, got %v", err)
+		}
+	})
+
+	t.Run("Duty lookup fails", func(t *testing.T) {
+		service := NewAbsenceService(&MockDriverAbsenceRepository{}, &MockDriverRepository{getByIdResp: driver}, &MockDutyRepository{getByDriverIdErr: errors.New("Database error")})
+
+		err := service.Add(newAbsence())
+		if err == nil || err.Error() != "Database error" {
+			t.Errorf("Expected 'Database error', got %v", err)
+		}
+	})
+
+	t.Run("Driver not found", func(t *testing.T) {
+		service := NewAbsenceService(&MockDriverAbsenceRepository{}, &MockDriverRepository{getByIdErr: errors.New("Driver not found")}, &MockDutyRepository{})
+
+		err := service.Add(newAbsence())
+		if err == nil || err.Error() != "Driver not found" {
+			t.Errorf("Expected 'Driver not found' error, got %v", err)
+		}
+	})
+}
+
+func TestAbsenceService_GetAvailable(t *testing.T) {
+	drivers := []models.Driver{{ID: "d1"}, {ID: "d2"}, {ID: "d3"}}
+	absences := []models.DriverAbsence{{DriverID: "d2", Kind: models.AbsenceSickLeave}}
+	service := NewAbsenceService(&MockDriverAbsenceRepository{getByDateResp: absences}, &MockDriverRepository{getAllResp: drivers}, nil)
+
+	available, err := service.GetAvailable(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(available) != 2 || available[0].ID != "d1" || available[1].ID != "d3" {
+		t.Errorf("Expected drivers d1 and d3, got %v", available)
+	}
+}
+
+func TestAbsenceService_CheckAvailable(t *testing.T) {
+	shiftStart := time.Date(2025, 7, 1, 6, 0, 0, 0, time.UTC)
+
+	t.Run("Available", func(t *testing.T) {
+		service := NewAbsenceService(&MockDriverAbsenceRepository{}, nil, nil)
+
+		err := service.CheckAvailable("d1", shiftStart, shiftStart.Add(8*time.Hour))
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		service := NewAbsenceService(&MockDriverAbsenceRepository{getOverlappingErr: errors.New("Database error")}, nil, nil)
+
+		err := service.CheckAvailable("d1", shiftStart, shiftStart.Add(8*time.Hour))
+		if err == nil || err.Error() != "Database error" {
+			t.Errorf("Expected 'Database error', got %v", err)
+		}
+	})
+}
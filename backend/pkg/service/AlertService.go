@@ -0,0 +1,192 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/repository"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var alertCauses = map[string]bool{
+	models.CauseTechnicalProblem: true,
+	models.CauseStrike:           true,
+	models.CauseDemonstration:    true,
+	models.CauseAccident:         true,
+	models.CauseHoliday:          true,
+	models.CauseWeather:          true,
+	models.CauseMaintenance:      true,
+	models.CauseConstruction:     true,
+	models.CausePoliceActivity:   true,
+	models.CauseMedicalEmergency: true,
+	models.CauseOther:            true,
+}
+
+var alertEffects = map[string]bool{
+	models.EffectNoService:         true,
+	models.EffectReducedService:    true,
+	models.EffectSignificantDelays: true,
+	models.EffectDetour:            true,
+	models.EffectAdditionalService: true,
+	models.EffectModifiedService:   true,
+	models.EffectStopMoved:         true,
+	models.EffectOther:             true,
+}
+
+var alertSeverities = map[string]bool{
+	models.AlertInfo:    true,
+	models.AlertWarning: true,
+	models.AlertSevere:  true,
+}
+
+type AlertService struct {
+	repo        repository.IServiceAlertRepository
+	routeRepo   repository.IRouteRepository
+	busStopRepo repository.IBusStopRepository
+}
+
+func NewAlertService(r repository.IServiceAlertRepository, routeRepo repository.IRouteRepository, busStopRepo repository.IBusStopRepository) *AlertService {
+	s := &AlertService{r, routeRepo, busStopRepo}
+	return s
+}
+
+func (s AlertService) GetById(id string) (*models.ServiceAlert, error) {
+	alert, err := s.repo.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if alert == nil {
+		return nil, errors.New("Service alert not found")
+	}
+	return alert, nil
+}
+
+// Publish validates the alert and stores it. Each alert has to affect at
+// least one route or stop.
+func (s AlertService) Publish(alert *models.ServiceAlert) error {
+	alert.Title = strings.TrimSpace(alert.Title)
+	if alert.Title == "" {
+		return errors.New("Alert title is required")
+	}
+	alert.Description = strings.TrimSpace(alert.Description)
+	alert.Cause = strings.ToLower(strings.TrimSpace(alert.Cause))
+	if !alertCauses[alert.Cause] {
+		return fmt.Errorf("Unknown alert cause %s", alert.Cause)
+	}
+	alert.Effect = strings.ToLower(strings.TrimSpace(alert.Effect))
+	if !alertEffects[alert.Effect] {
+		return fmt.Errorf("Unknown alert effect %s", alert.Effect)
+	}
+	alert.Severity = strings.ToLower(strings.TrimSpace(alert.Severity))
+	if !alertSeverities[alert.Severity] {
+		return fmt.Errorf("Unknown alert severity %s", alert.Severity)
+	}
+	if alert.StartsAt.IsZero() {
+		return errors.New("Alert start is required")
+	}
+	if alert.EndsAt != nil && !alert.EndsAt.After(alert.StartsAt) {
+		return errors.New("Alert end must be after its start")
+	}
+	alert.RouteIDs = uniqueIds(alert.RouteIDs)
+	alert.StopIDs = uniqueIds(alert.StopIDs)
+	if len(alert.RouteIDs) == 0 && len(alert.StopIDs) == 0 {
+		return errors.New("Alert must affect at least one route or stop")
+	}
+	for _, routeId := range alert.RouteIDs {
+		route, _ := s.routeRepo.GetById(routeId)
+		if route == nil {
+			return fmt.Errorf("Route %s not found", routeId)
+		}
+	}
+	for _, stopId := range alert.StopIDs {
+		stop, _ := s.busStopRepo.GetById(stopId)
+		if stop == nil {
+			return fmt.Errorf("Bus stop %s not found", stopId)
+		}
+	}
+	return s.repo.Add(alert)
+}
+
+// End closes the alert window now, for example when a diversion is lifted
+// earlier than announced.
+func (s AlertService) End(id string) (*models.ServiceAlert, error) {
+	alert, err := s.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if alert.EndsAt != nil && !alert.EndsAt.After(now) {
+		return nil, errors.New("Service alert has already ended")
+	}
+	err = s.repo.End(id, now)
+	if err != nil {
+		return nil, err
+	}
+	alert.EndsAt = &now
+	return alert, nil
+}
+
+// GetActive returns the alerts in effect at the given time, optionally
+// only those affecting a route or a stop
+func (s AlertService) GetActive(routeId, stopId string, at time.Time) ([]models.ServiceAlert, error) {
+	return s.repo.GetActive(at, routeId, stopId)
+}
+
+// AttachToRoutes fills in the alerts active at the given time for each route
+func (s AlertService) AttachToRoutes(routes []models.Route, at time.Time) error {
+	alerts, err := s.repo.GetActive(at, "", "")
+	if err != nil {
+		return err
+	}
+	for i := range routes {
+		routes[i].Alerts = []models.ServiceAlert{}
+		for _, alert := range alerts {
+			if containsId(alert.RouteIDs, routes[i].ID) {
+				routes[i].Alerts = append(routes[i].Alerts, alert)
+			}
+		}
+	}
+	return nil
+}
+
+// AttachToStops fills in the alerts active at the given time for each stop
+func (s AlertService) AttachToStops(stops []models.BusStop, at time.Time) error {
+	alerts, err := s.repo.GetActive(at, "", "")
+	if err != nil {
+		return err
+	}
+	for i := range stops {
+		stops[i].Alerts = []models.ServiceAlert{}
+		for _, alert := range alerts {
+			if containsId(alert.StopIDs, stops[i].ID) {
+				stops[i].Alerts = append(stops[i].Alerts, alert)
+			}
+		}
+	}
+	return nil
+}
+
+// uniqueIds trims the ids and drops empty and repeated ones, keeping order
+func uniqueIds(ids []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
+
+func containsId(ids []string, id string) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
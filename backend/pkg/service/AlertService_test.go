@@ -0,0 +1,170 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"testing"
+	"time"
+)
+
+type MockServiceAlertRepository struct {
+	getByIdResp   *models.ServiceAlert
+	getByIdErr    error
+	addErr        error
+	endErr        error
+	getActiveResp []models.ServiceAlert
+	getActiveErr  error
+	added         *models.ServiceAlert
+}
+
+func (m *MockServiceAlertRepository) GetById(id string) (*models.ServiceAlert, error) {
+	return m.getByIdResp, m.getByIdErr
+}
+
+func (m *MockServiceAlertRepository) Add(alert *models.ServiceAlert) error {
+	m.added = alert
+	return m.addErr
+}
+
+func (m *MockServiceAlertRepository) End(id string, at time.Time) error {
+	return m.endErr
+}
+
+func (m *MockServiceAlertRepository) GetActive(at time.Time, routeId, stopId string) ([]models.ServiceAlert, error) {
+	return m.getActiveResp, m.getActiveErr
+}
+
+func TestAlertService_Publish(t *testing.T) {
+	route := &models.Route{ID: "route-1", Number: "15"}
+	stop := &models.BusStop{ID: "stop-1"}
+	newAlert := func() *models.ServiceAlert {
+		return &models.ServiceAlert{
+			Title:    " Объезд по Садовой улице ",
+			Cause:    "Construction",
+			Effect:   " detour",
+			Severity: "WARNING",
+			StartsAt: time.Date(2025, 5, 12, 6, 0, 0, 0, time.UTC),
+			RouteIDs: []string{route.ID, " route-1 ", ""},
+			StopIDs:  []string{stop.ID},
+		}
+	}
+	newService := func(repo *MockServiceAlertRepository) *AlertService {
+		return NewAlertService(repo, &MockRouteRepository{getByIdResp: route}, &MockBusStopRepository{getByIdResp: stop})
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		repo := &MockServiceAlertRepository{}
+		alert := newAlert()
+
+		err := newService(repo).Publish(alert)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if repo.added != alert {
+			t.Fatal("Expected alert to be saved")
+		}
+		if alert.Title != "Объезд по Садовой улице" || alert.Cause != models.CauseConstruction || alert.Effect != models.EffectDetour || alert.Severity != models.AlertWarning {
+			t.Errorf("Expected normalized alert, got %v", alert)
+		}
+		if len(alert.RouteIDs) != 1 || alert.RouteIDs[0] != route.ID {
+			t.Errorf("Expected repeated routes to be dropped, got %v", alert.RouteIDs)
+		}
+	})
+
+	t.Run("Unknown effect", func(t *testing.T) {
+		alert := newAlert()
+		alert.Effect = "teleport"
+
+		err := newService(&MockServiceAlertRepository{}).Publish(alert)
+		if err == nil || err.Error() != "Unknown alert effect teleport" {
+			t.Errorf("Expected 'Unknown alert effect teleport' error, got %v", err)
+		}
+	})
+
+	t.Run("End before start", func(t *testing.T) {
+		alert := newAlert()
+		endsAt := alert.StartsAt.Add(-time.Hour)
+		alert.EndsAt = &endsAt
+
+		err := newService(&MockServiceAlertRepository{}).Publish(alert)
+		if err == nil || err.Error() != "Alert end must be after its start" {
+			t.Errorf("Expected 'Alert end must be after its start' error, got %v", err)
+		}
+	})
+
+	t.Run("Nothing affected", func(t *testing.T) {
+		alert := newAlert()
+		alert.RouteIDs = nil
+		alert.StopIDs = []string{" "}
+
+		err := newService(&MockServiceAlertRepository{}).Publish(alert)
+		if err == nil || err.Error() != "Alert must affect at least one route or stop" {
+			t.Errorf("Expected 'Alert must affect at least one route or stop' error, got %v", err)
+		}
+	})
+
+	t.Run("Unknown stop", func(t *testing.T) {
+		alert := newAlert()
+		alert.StopIDs = []string{"missing"}
+		service := NewAlertService(&MockServiceAlertRepository{}, &MockRouteRepository{getByIdResp: route}, &MockBusStopRepository{})
+
+		err := service.Publish(alert)
+		if err == nil || err.Error() != "Bus stop missing not found" {
+			t.Errorf("Expected 'Bus stop missing not found' error, got %v", err)
+		}
+	})
+}
+
+func TestAlertService_End(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := &MockServiceAlertRepository{getByIdResp: &models.ServiceAlert{ID: "alert-1", StartsAt: time.Now().Add(-time.Hour)}}
+		service := NewAlertService(repo, &MockRouteRepository{}, &MockBusStopRepository{})
+
+		alert, err := service.End("alert-1")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if alert.EndsAt == nil {
+			t.Error("Expected end time to be set")
+		}
+	})
+
+	t.Run("Already ended", func(t *testing.T) {
+		endsAt := time.Now().Add(-time.Minute)
+		repo := &MockServiceAlertRepository{getByIdResp: &models.ServiceAlert{ID: "alert-1", EndsAt: &endsAt}}
+		service := NewAlertService(repo, &MockRouteRepository{}, &MockBusStopRepository{})
+
+		_, err := service.End("alert-1")
+		if err == nil || err.Error() != "Service alert has already ended" {
+			t.Errorf("Expected 'Service alert has already ended' error, got %v", err)
+		}
+	})
+}
+
+func TestAlertService_Attach(t *testing.T) {
+	repo := &MockServiceAlertRepository{getActiveResp: []models.ServiceAlert{
+		{ID: "alert-1", RouteIDs: []string{"route-1"}, StopIDs: []string{"stop-2"}},
+		{ID: "alert-2", StopIDs: []string{"stop-1", "stop-2"}},
+	}}
+	service := NewAlertService(repo, &MockRouteRepository{}, &MockBusStopRepository{})
+
+	routes := []models.Route{{ID: "route-1"}, {ID: "route-2"}}
+	err := service.AttachToRoutes(routes, time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(routes[0].Alerts) != 1 || routes[0].Alerts[0].ID != "alert-1" {
+		t.Errorf("Expected alert-1 on route-1, got %v", routes[0].Alerts)
+	}
+	if routes[1].Alerts == nil || len(routes[1].Alerts) != 0 {
+		t.Errorf("Expected empty alert list on route-2, got %v", routes[1].Alerts)
+	}
+
+	stops := []models.BusStop{{ID: "stop-1"}, {ID: "stop-2"}}
+	err = service.AttachToStops(stops, time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(stops[0].Alerts) != 1 || len(stops[1].Alerts) != 2 {
+		t.Errorf("Expected 1 and 2 alerts on the stops, got %v and %v", stops[0].Alerts, stops[1].Alerts)
+	}
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/repository"
+	"math"
+	"time"
+)
+
+const (
+	earthRadius = 6371000.0
+	// below this speed in km/h the bus is treated as standing at a stop or
+	// in traffic and the average speed is used instead
+	minPredictionSpeed     = 5.0
+	defaultPredictionSpeed = 20.0
+	// a heading further than this in degrees from the course of the route
+	// means the bus runs the route backwards
+	maxCourseDeviation = 90.0
+)
+
+// distance returns the great-circle distance in meters.
+func distance(lat1, long1, lat2, long2 float64) float64 {
+	toRad := math.Pi / 180
+	dLat := (lat2 - lat1) * toRad
+	dLong := (long2 - long1) * toRad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*toRad)*math.Cos(lat2*toRad)*math.Sin(dLong/2)*math.Sin(dLong/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+// bearing returns the initial course from the first point to the second in
+// degrees clockwise from north.
+func bearing(lat1, long1, lat2, long2 float64) float64 {
+	toRad := math.Pi / 180
+	dLong := (long2 - long1) * toRad
+	y := math.Sin(dLong) * math.Cos(lat2*toRad)
+	x := math.Cos(lat1*toRad)*math.Sin(lat2*toRad) - math.Sin(lat1*toRad)*math.Cos(lat2*toRad)*math.Cos(dLong)
+	return math.Mod(math.Atan2(y, x)/toRad+360, 360)
+}
+
+type segmentKey struct {
+	from string
+	to   string
+}
+
+// segmentTimes holds the average seconds between two stops by hour of day.
+type segmentTimes map[segmentKey]map[int]float64
+
+func newSegmentTimes(travelTimes []models.SegmentTravelTime) segmentTimes {
+	times := make(segmentTimes)
+	for _, travelTime := range travelTimes {
+		key := segmentKey{travelTime.FromStopID, travelTime.ToStopID}
+		if times[key] == nil {
+			times[key] = make(map[int]float64)
+		}
+		times[key][travelTime.Hour] = travelTime.AvgSeconds
+	}
+	return times
+}
+
+// lookup prefers the average of the given hour and falls back to the mean
+// over the hours the segment was travelled at.
+func (st segmentTimes) lookup(from, to string, hour int) (float64, bool) {
+	hours := st[segmentKey{from, to}]
+	if len(hours) == 0 {
+		return 0, false
+	}
+	if seconds, ok := hours[hour]; ok {
+		return seconds, true
+	}
+	total := 0.0
+	for _, seconds := range hours {
+		total += seconds
+	}
+	return total / float64(len(hours)), true
+}
+
+// stopsInDirection orders the route stops the way the bus runs, so buses
+// on the way back get the stops ahead of them. Once the bus has moved on
+// from the stop it passed last towards another one, that decides;
+// otherwise its heading is compared with the course of the route there.
+func stopsInDirection(position models.VehiclePosition, stops []models.BusStop, last *models.StopPassage) []models.BusStop {
+	if len(stops) < 2 {
+		return stops
+	}
+	nearest := nearestStopIndex(position, stops)
+	forward := true
+	passed := -1
+	if last != nil && position.RecordedAt.Sub(last.PassedAt) <= maxSegmentTime {
+		for i, stop := range stops {
+			if stop.ID == last.StopID {
+				passed = i
+			}
+		}
+	}
+	if passed >= 0 && passed != nearest {
+		forward = passed < nearest
+	} else {
+		from, to := nearest, nearest+1
+		if to == len(stops) {
+			from, to = nearest-1, nearest
+		}
+		course := bearing(stops[from].Lat, stops[from].Long, stops[to].Lat, stops[to].Long)
+		deviation := math.Abs(math.Mod(position.Heading-course+540, 360) - 180)
+		forward = deviation <= maxCourseDeviation
+	}
+	if forward {
+		return stops
+	}
+	return reversedStops(stops)
+}
+
+func reversedStops(stops []models.BusStop) []models.BusStop {
+	reversed := make([]models.BusStop, len(stops))
+	for i, stop := range stops {
+		reversed[len(stops)-1-i] = stop
+	}
+	return reversed
+}
+
+// lastStops holds the stop every bus passed last, by bus.
+type lastStops map[string]models.StopPassage
+
+func loadLastStops(segmentRepo repository.ISegmentRepository) (lastStops, error) {
+	passages, err := segmentRepo.GetAllLastStops()
+	if err != nil {
+		return nil, err
+	}
+	stops := make(lastStops)
+	for _, passage := range passages {
+		stops[passage.BusID] = passage
+	}
+	return stops, nil
+}
+
+// on returns the stop the bus passed last on the route, if any.
+func (l lastStops) on(busId, routeId string) *models.StopPassage {
+	passage, ok := l[busId]
+	if !ok || passage.RouteID != routeId {
+		return nil
+	}
+	return &passage
+}
+
+// nextStopIndex finds the stop the bus is heading to. The nearest stop is
+// taken unless the bus is already closer to the stop after it than that
+// stop is, which means it has passed the nearest one.
+func nextStopIndex(position models.VehiclePosition, stops []models.BusStop) int {
+	nearest := nearestStopIndex(position, stops)
+	if nearest+1 < len(stops) {
+		next := stops[nearest+1]
+		if distance(position.Lat, position.Long, next.Lat, next.Long) < distance(stops[nearest].Lat, stops[nearest].Long, next.Lat, next.Long) {
+			return nearest + 1
+		}
+	}
+	return nearest
+}
+
+func nearestStopIndex(position models.VehiclePosition, stops []models.BusStop) int {
+	nearest := 0
+	best := math.MaxFloat64
+	for i, stop := range stops {
+		d := distance(position.Lat, position.Long, stop.Lat, stop.Long)
+		if d < best {
+			nearest, best = i, d
+		}
+	}
+	return nearest
+}
+
+// predictArrivals estimates arrival at the remaining stops, in the order
+// the bus runs them, see stopsInDirection. Segments with a history use
+// their average travel time for the hour, the others the distance and the
+// current speed of the bus.
+func predictArrivals(position models.VehiclePosition, stops []models.BusStop, times segmentTimes) []models.GtfsStopTimeUpdate {
+	if len(stops) == 0 {
+		return nil
+	}
+	speed := position.Speed
+	if speed < minPredictionSpeed {
+		speed = defaultPredictionSpeed
+	}
+	metersPerSecond := speed * 1000 / 3600
+
+	var updates []models.GtfsStopTimeUpdate
+	at := position.RecordedAt
+	next := nextStopIndex(position, stops)
+	for i := next; i < len(stops); i++ {
+		var seconds float64
+		if i == next {
+			remaining := distance(position.Lat, position.Long, stops[i].Lat, stops[i].Long)
+			seconds = remaining / metersPerSecond
+			if i > 0 {
+				if avg, ok := times.lookup(stops[i-1].ID, stops[i].ID, at.Local().Hour()); ok {
+					// share of the segment still ahead of the bus
+					length := distance(stops[i-1].Lat, stops[i-1].Long, stops[i].Lat, stops[i].Long)
+					seconds = avg * math.Min(remaining/length, 1)
+				}
+			}
+		} else if avg, ok := times.lookup(stops[i-1].ID, stops[i].ID, at.Local().Hour()); ok {
+			seconds = avg
+		} else {
+			seconds = distance(stops[i-1].Lat, stops[i-1].Long, stops[i].Lat, stops[i].Long) / metersPerSecond
+		}
+		at = at.Add(time.Duration(seconds * float64(time.Second)))
+		updates = append(updates, models.GtfsStopTimeUpdate{
+			StopID:       stops[i].ID,
+			StopSequence: i + 1,
+			Arrival:      at.Truncate(time.Second),
+		})
+	}
+	return updates
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/repository"
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// a bus closer than this in meters to a stop is at the stop
+	stopRadius = 40.0
+	// longer gaps between two stops mean the bus left service in between
+	maxSegmentTime      = 30 * time.Minute
+	MaxArrivalsPerRoute = 3
+)
+
+type ArrivalService struct {
+	positionRepo repository.IPositionRepository
+	routeRepo    repository.IRouteRepository
+	busRepo      repository.IBusRepository
+	busStopRepo  repository.IBusStopRepository
+	segmentRepo  repository.ISegmentRepository
+	placement    *placementCache
+}
+
+func NewArrivalService(positionRepo repository.IPositionRepository, routeRepo repository.IRouteRepository, busRepo repository.IBusRepository, busStopRepo repository.IBusStopRepository, segmentRepo repository.ISegmentRepository) *ArrivalService {
+	s := &ArrivalService{positionRepo, routeRepo, busRepo, busStopRepo, segmentRepo, &placementCache{}}
+	return s
+}
+
+// Observe records the stops the buses reach and the time they needed from
+// the previous stop of their route.
+func (s ArrivalService) Observe(positions []models.VehiclePosition) error {
+	if len(positions) == 0 {
+		return nil
+	}
+	sorted := append([]models.VehiclePosition(nil), positions...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].RecordedAt.Before(sorted[j].RecordedAt) })
+	network, err := s.placement.load(s.routeRepo, s.segmentRepo, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, position := range sorted {
+		routeId := network.busRoutes[position.BusID]
+		if routeId == "" {
+			continue
+		}
+		stops, err := network.Stops(routeId)
+		if err != nil {
+			return err
+		}
+		if len(stops) == 0 {
+			continue
+		}
+		i := nearestStopIndex(position, stops)
+		if distance(position.Lat, position.Long, stops[i].Lat, stops[i].Long) > stopRadius {
+			continue
+		}
+		last, _ := s.segmentRepo.GetLastStop(position.BusID)
+		if last != nil && last.RouteID == routeId {
+			if last.StopID == stops[i].ID || !position.RecordedAt.After(last.PassedAt) {
+				continue
+			}
+			elapsed := position.RecordedAt.Sub(last.PassedAt)
+			// buses run the route both ways, each way has its own segments
+			adjacent := i > 0 && stops[i-1].ID == last.StopID || i+1 < len(stops) && stops[i+1].ID == last.StopID
+			if adjacent && elapsed <= maxSegmentTime {
+				err = s.segmentRepo.AddTravelTime(routeId, last.StopID, stops[i].ID, last.PassedAt.Local().Hour(), elapsed.Seconds())
+				if err != nil {
+					return err
+				}
+			}
+		}
+		err = s.segmentRepo.SaveLastStop(&models.StopPassage{
+			BusID:    position.BusID,
+			RouteID:  routeId,
+			StopID:   stops[i].ID,
+			PassedAt: position.RecordedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetStopArrivals returns the next buses of every route serving the stop.
+// Routes without a bus on the way are listed with no vehicles.
+func (s ArrivalService) GetStopArrivals(stopId string, now time.Time) ([]models.RouteArrivals, error) {
+	stop, _ := s.busStopRepo.GetById(stopId)
+	if stop == nil {
+		return nil, errors.New("Bus stop not found")
+	}
+	network, err := loadRouteNetwork(s.routeRepo, s.segmentRepo, now)
+	if err != nil {
+		return nil, err
+	}
+	result := []models.RouteArrivals{}
+	index := make(map[string]int)
+	for _, route := range network.routes {
+		stops, err := network.Stops(route.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, routeStop := range stops {
+			if routeStop.ID == stopId {
+				index[route.ID] = len(result)
+				result = append(result, models.RouteArrivals{RouteID: route.ID, RouteNumber: route.Number, Vehicles: []models.VehicleArrival{}})
+				break
+			}
+		}
+	}
+	if len(result) == 0 {
+		return result, nil
+	}
+
+	positions, err := s.positionRepo.GetAllLatest()
+	if err != nil {
+		return nil, err
+	}
+	buses, err := s.busRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	labels := make(map[string]string)
+	for _, bus := range buses {
+		labels[bus.ID] = bus.RegisterNumber
+	}
+	lastStops, err := loadLastStops(s.segmentRepo)
+	if err != nil {
+		return nil, err
+	}
+	for _, position := range positions {
+		if now.Sub(position.RecordedAt) > staleAfter {
+			continue
+		}
+		routeId := network.busRoutes[position.BusID]
+		i, ok := index[routeId]
+		if !ok {
+			continue
+		}
+		stops, err := network.Stops(routeId)
+		if err != nil {
+			return nil, err
+		}
+		times, err := network.TravelTimes(routeId)
+		if err != nil {
+			return nil, err
+		}
+		stops = stopsInDirection(position, stops, lastStops.on(position.BusID, routeId))
+		for _, update := range predictArrivals(position, stops, times) {
+			if update.StopID != stopId {
+				continue
+			}
+			minutes := int(math.Ceil(update.Arrival.Sub(now).Minutes()))
+			if minutes < 0 {
+				minutes = 0
+			}
+			result[i].Vehicles = append(result[i].Vehicles, models.VehicleArrival{
+				BusID:     position.BusID,
+				Label:     labels[position.BusID],
+				ArrivesAt: update.Arrival,
+				Minutes:   minutes,
+			})
+		}
+	}
+	for i := range result {
+		vehicles := result[i].Vehicles
+		sort.Slice(vehicles, func(a, b int) bool { return vehicles[a].ArrivesAt.Before(vehicles[b].ArrivesAt) })
+		if len(vehicles) > MaxArrivalsPerRoute {
+			result[i].Vehicles = vehicles[:MaxArrivalsPerRoute]
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+type MockSegmentRepository struct {
+	added              []models.SegmentTravelTime
+	addTravelTimeErr   error
+	getTravelTimesResp []models.SegmentTravelTime
+	getTravelTimesErr  error
+	lastStop           *models.StopPassage
+	getLastStopErr     error
+	saveLastStopErr    error
+	allLastStopsResp   []models.StopPassage
+	allLastStopsErr    error
+}
+
+func (m *MockSegmentRepository) AddTravelTime(routeId, fromStopId, toStopId string, hour int, seconds float64) error {
+	m.added = append(m.added, models.SegmentTravelTime{RouteID: routeId, FromStopID: fromStopId, ToStopID: toStopId, Hour: hour, AvgSeconds: seconds})
+	return m.addTravelTimeErr
+}
+
+func (m *MockSegmentRepository) GetTravelTimes(routeId string) ([]models.SegmentTravelTime, error) {
+	return m.getTravelTimesResp, m.getTravelTimesErr
+}
+
+func (m *MockSegmentRepository) GetLastStop(busId string) (*models.StopPassage, error) {
+	return m.lastStop, m.getLastStopErr
+}
+
+func (m *MockSegmentRepository) GetAllLastStops() ([]models.StopPassage, error) {
+	return m.allLastStopsResp, m.allLastStopsErr
+}
+
+func (m *MockSegmentRepository) SaveLastStop(passage *models.StopPassage) error {
+	m.lastStop = passage
+	return m.saveLastStopErr
+}
+
+func newArrivalRouteRepo() *MockRouteRepository {
+	return &MockRouteRepository{
+		getAllResp:          []models.Route{{ID: "route-1", Number: "42"}},
+		getAllBusesByIdResp: []models.Bus{{ID: "bus-1"}, {ID: "bus-2"}},
+		getAllBusStopsByIdResp: []models.BusStop{
+			{ID: "s1", Lat: 55.70, Long: 37.60},
+			{ID: "s2", Lat: 55.71, Long: 37.60},
+			{ID: "s3", Lat: 55.72, Long: 37.60},
+		},
+	}
+}
+
+func TestArrivalService_Observe(t *testing.T) {
+	start := time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC)
+
+	t.Run("Segment between consecutive stops", func(t *testing.T) {
+		segments := &MockSegmentRepository{}
+		service := NewArrivalService(&MockPositionRepository{}, newArrivalRouteRepo(), &MockBusRepository{}, &MockBusStopRepository{}, segments)
+
+		err := service.Observe([]models.VehiclePosition{
+			{BusID: "bus-1", RecordedAt: start.Add(3 * time.Minute), Lat: 55.7101, Long: 37.60},
+			{BusID: "bus-1", RecordedAt: start, Lat: 55.7001, Long: 37.60},
+			{BusID: "bus-1", RecordedAt: start.Add(time.Minute), Lat: 55.705, Long: 37.60},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(segments.added) != 1 || segments.added[0].FromStopID != "s1" || segments.added[0].ToStopID != "s2" {
+			t.Fatalf("Expected one s1-s2 travel time, got %v", segments.added)
+		}
+		if segments.added[0].AvgSeconds != 180 || segments.added[0].Hour != start.Local().Hour() {
+			t.Errorf("Expected 180s at hour %d, got %v", start.Local().Hour(), segments.added[0])
+		}
+		if segments.lastStop == nil || segments.lastStop.StopID != "s2" {
+			t.Errorf("Expected last stop s2, got %v", segments.lastStop)
+		}
+	})
+
+	t.Run("Skipped stop is not a segment", func(t *testing.T) {
+		segments := &MockSegmentRepository{lastStop: &models.StopPassage{BusID: "bus-1", RouteID: "route-1", StopID: "s1", PassedAt: start}}
+		service := NewArrivalService(&MockPositionRepository{}, newArrivalRouteRepo(), &MockBusRepository{}, &MockBusStopRepository{}, segments)
+
+		err := service.Observe([]models.VehiclePosition{{BusID: "bus-1", RecordedAt: start.Add(5 * time.Minute), Lat: 55.72, Long: 37.60}})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(segments.added) != 0 || segments.lastStop.StopID != "s3" {
+			t.Errorf("Expected no travel time and last stop s3, got %v and %v", segments.added, segments.lastStop)
+		}
+	})
+
+	t.Run("Placement is reused between batches", func(t *testing.T) {
+		segments := &MockSegmentRepository{}
+		routeRepo := newArrivalRouteRepo()
+		service := NewArrivalService(&MockPositionRepository{}, routeRepo, &MockBusRepository{}, &MockBusStopRepository{}, segments)
+
+		err := service.Observe([]models.VehiclePosition{{BusID: "bus-1", RecordedAt: start, Lat: 55.7001, Long: 37.60}})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		routeRepo.getAllErr = errors.New("db error")
+		err = service.Observe([]models.VehiclePosition{{BusID: "bus-1", RecordedAt: start.Add(3 * time.Minute), Lat: 55.7101, Long: 37.60}})
+		if err != nil {
+			t.Fatalf("Expected the cached placement to be used, got %v", err)
+		}
+		if len(segments.added) != 1 {
+			t.Errorf("Expected one travel time, got %v", segments.added)
+		}
+	})
+
+	t.Run("Segment on the way back", func(t *testing.T) {
+		segments := &MockSegmentRepository{lastStop: &models.StopPassage{BusID: "bus-1", RouteID: "route-1", StopID: "s3", PassedAt: start}}
+		service := NewArrivalService(&MockPositionRepository{}, newArrivalRouteRepo(), &MockBusRepository{}, &MockBusStopRepository{}, segments)
+
+		err := service.Observe([]models.VehiclePosition{{BusID: "bus-1", RecordedAt: start.Add(2 * time.Minute), Lat: 55.71, Long: 37.60}})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(segments.added) != 1 || segments.added[0].FromStopID != "s3" || segments.added[0].ToStopID != "s2" {
+			t.Errorf("Expected one s3-s2 travel time, got %v", segments.added)
+		}
+	})
+
+	t.Run("Long gap is not a segment", func(t *testing.T) {
+		segments := &MockSegmentRepository{lastStop: &models.StopPassage{BusID: "bus-1", RouteID: "route-1", StopID: "s1", PassedAt: start}}
+		service := NewArrivalService(&MockPositionRepository{}, newArrivalRouteRepo(), &MockBusRepository{}, &MockBusStopRepository{}, segments)
+
+		err := service.Observe([]models.VehiclePosition{{BusID: "bus-1", RecordedAt: start.Add(2 * time.Hour), Lat: 55.71, Long: 37.60}})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(segments.added) != 0 {
+			t.Errorf("Expected no travel time, got %v", segments.added)
+		}
+	})
+}
+
+func TestArrivalService_GetStopArrivals(t *testing.T) {
+	now := time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC)
+	positions := []models.VehiclePosition{
+		{BusID: "bus-1", RecordedAt: now, Lat: 55.715, Long: 37.60, Speed: 36},
+		{BusID: "bus-2", RecordedAt: now, Lat: 55.70, Long: 37.60, Speed: 36},
+		{BusID: "bus-3", RecordedAt: now, Lat: 55.70, Long: 37.60, Speed: 36},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		service := NewArrivalService(
+			&MockPositionRepository{getAllLatestResp: positions},
+			newArrivalRouteRepo(),
+			&MockBusRepository{getAllResp: []models.Bus{{ID: "bus-1", RegisterNumber: "А123ВС77"}}},
+			&MockBusStopRepository{getByIdResp: &models.BusStop{ID: "s3"}},
+			&MockSegmentRepository{},
+		)
+
+		arrivals, err := service.GetStopArrivals("s3", now)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(arrivals) != 1 || arrivals[0].RouteNumber != "42" {
+			t.Fatalf("Expected arrivals of route 42, got %v", arrivals)
+		}
+		vehicles := arrivals[0].Vehicles
+		if len(vehicles) != 2 || vehicles[0].BusID != "bus-1" || vehicles[1].BusID != "bus-2" {
+			t.Fatalf("Expected bus-1 then bus-2, got %v", vehicles)
+		}
+		// 556 m and 2224 m at 10 m/s
+		if vehicles[0].Minutes != 1 || vehicles[1].Minutes != 4 || vehicles[0].Label != "А123ВС77" {
+			t.Errorf("Expected 1 and 4 minutes, got %v", vehicles)
+		}
+	})
+
+	t.Run("Bus on the way back", func(t *testing.T) {
+		service := NewArrivalService(
+			&MockPositionRepository{getAllLatestResp: []models.VehiclePosition{{BusID: "bus-1", RecordedAt: now, Lat: 55.712, Long: 37.60, Speed: 36}}},
+			newArrivalRouteRepo(),
+			&MockBusRepository{},
+			&MockBusStopRepository{getByIdResp: &models.BusStop{ID: "s3"}},
+			&MockSegmentRepository{allLastStopsResp: []models.StopPassage{{BusID: "bus-1", RouteID: "route-1", StopID: "s3", PassedAt: now.Add(-time.Minute)}}},
+		)
+
+		arrivals, err := service.GetStopArrivals("s3", now)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(arrivals) != 1 || len(arrivals[0].Vehicles) != 0 {
+			t.Errorf("Expected no arrival of a bus that left s3, got %v", arrivals)
+		}
+	})
+
+	t.Run("Stop not found", func(t *testing.T) {
+		service := NewArrivalService(&MockPositionRepository{}, newArrivalRouteRepo(), &MockBusRepository{}, &MockBusStopRepository{}, &MockSegmentRepository{})
+
+		_, err := service.GetStopArrivals("missing", now)
+		if err == nil || err.Error() != "Bus stop not found" {
+			t.Errorf("Expected 'Bus stop not found' error, got %v", err)
+		}
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"backend/pkg/models"
 	"backend/pkg/repository"
 	"errors"
+	"fmt"
+	"strings"
 )
 
 type BusService struct {
@@ -39,7 +41,11 @@ func (bs BusService) GetByNumber(number string) (*models.Bus, error) {
 }
 
 func (bs BusService) Add(bus *models.Bus) error {
-	err := bs.repo.Add(bus)
+	err := normalizeVehicleClass(bus)
+	if err != nil {
+		return err
+	}
+	err = bs.repo.Add(bus)
 	return err
 }
 
@@ -56,6 +62,22 @@ func (bs BusService) DeleteById(id string) error {
 }
 
 func (bs BusService) UpdateById(bus *models.Bus) error {
-	err := bs.repo.UpdateById(bus)
+	err := normalizeVehicleClass(bus)
+	if err != nil {
+		return err
+	}
+	err = bs.repo.UpdateById(bus)
 	return err
 }
+
+// normalizeVehicleClass defaults the vehicle class to a full-size bus (D).
+func normalizeVehicleClass(bus *models.Bus) error {
+	bus.VehicleClass = strings.ToUpper(strings.TrimSpace(bus.VehicleClass))
+	if bus.VehicleClass == "" {
+		bus.VehicleClass = "D"
+	}
+	if !busVehicleClasses[bus.VehicleClass] {
+		return fmt.Errorf("Unknown vehicle class %q", bus.VehicleClass)
+	}
+	return nil
+}
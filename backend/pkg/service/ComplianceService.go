@@ -0,0 +1,208 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/repository"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	RuleMaxDailyDriving  = "max_daily_driving"
+	RuleMinDailyRest     = "min_daily_rest"
+	RuleMaxWeeklyDriving = "max_weekly_driving"
+	RuleBreaks           = "breaks"
+)
+
+// WorkTimeRules are the driver working time limits checked against the roster.
+type WorkTimeRules struct {
+	MaxDailyDriving  time.Duration
+	MinDailyRest     time.Duration
+	MaxWeeklyDriving time.Duration
+	// BreakAfter is the longest stretch of driving allowed without a break
+	// of at least MinBreak.
+	BreakAfter time.Duration
+	MinBreak   time.Duration
+}
+
+func DefaultWorkTimeRules() WorkTimeRules {
+	return WorkTimeRules{
+		MaxDailyDriving:  9 * time.Hour,
+		MinDailyRest:     11 * time.Hour,
+		MaxWeeklyDriving: 40 * time.Hour,
+		BreakAfter:       4*time.Hour + 30*time.Minute,
+		MinBreak:         45 * time.Minute,
+	}
+}
+
+type ComplianceService struct {
+	repo  repository.IDutyRepository
+	rules WorkTimeRules
+}
+
+func NewComplianceService(r repository.IDutyRepository, rules WorkTimeRules) *ComplianceService {
+	s := &ComplianceService{r, rules}
+	return s
+}
+
+// CheckDuty returns the violations the duty would cause together with the
+// driver's other duties. Violations that do not involve the duty are left out,
+// so an earlier overridden duty does not block unrelated entries.
+func (s ComplianceService) CheckDuty(duty models.Duty) ([]models.ComplianceViolation, error) {
+	from := startOfWeek(duty.Date).AddDate(0, 0, -1)
+	to := startOfWeek(duty.Date).AddDate(0, 0, 8)
+	duties, err := s.repo.GetByDriverId(duty.DriverID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	merged := []models.Duty{duty}
+	for _, other := range duties {
+		if other.ID != duty.ID {
+			merged = append(merged, other)
+		}
+	}
+	var violations []models.ComplianceViolation
+	for _, violation := range checkWorkTime(s.rules, merged) {
+		for _, id := range violation.DutyIDs {
+			if id == duty.ID {
+				violations = append(violations, violation)
+				break
+			}
+		}
+	}
+	return violations, nil
+}
+
+func (s ComplianceService) GetDriverReport(driverId string, from, to time.Time) (*models.ComplianceReport, error) {
+	if !to.After(from) {
+		return nil, errors.New("Period end must be after its start")
+	}
+	from = startOfDay(from)
+	to = startOfDay(to)
+	// Whole weeks plus the day before are loaded so weekly totals and the rest
+	// before the first day of the period are computed correctly.
+	duties, err := s.repo.GetByDriverId(driverId, startOfWeek(from).AddDate(0, 0, -1), startOfWeek(to).AddDate(0, 0, 7))
+	if err != nil {
+		return nil, err
+	}
+	report := &models.ComplianceReport{DriverID: driverId, From: from, To: to}
+	for _, day := range dailyTotals(duties) {
+		if day.Date.Before(from) || !day.Date.Before(to) {
+			continue
+		}
+		report.TotalDrivingMinutes += day.DrivingMinutes
+		report.Days = append(report.Days, day)
+	}
+	for _, violation := range checkWorkTime(s.rules, duties) {
+		if violation.Date.Before(from) || !violation.Date.Before(to) {
+			continue
+		}
+		report.Violations = append(report.Violations, violation)
+	}
+	return report, nil
+}
+
+func drivingTime(duty models.Duty) time.Duration {
+	return duty.ShiftEnd.Sub(duty.ShiftStart) - time.Duration(duty.BreakMinutes)*time.Minute
+}
+
+func dailyTotals(duties []models.Duty) []models.ComplianceDay {
+	var days []models.ComplianceDay
+	index := map[time.Time]int{}
+	for _, duty := range duties {
+		date := startOfDay(duty.Date)
+		i, ok := index[date]
+		if !ok {
+			i = len(days)
+			index[date] = i
+			days = append(days, models.ComplianceDay{Date: date})
+		}
+		days[i].DrivingMinutes += int(drivingTime(duty).Minutes())
+		days[i].BreakMinutes += duty.BreakMinutes
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date.Before(days[j].Date) })
+	return days
+}
+
+// checkWorkTime validates one driver's duties against the rules.
+func checkWorkTime(rules WorkTimeRules, duties []models.Duty) []models.ComplianceViolation {
+	sorted := append([]models.Duty(nil), duties...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ShiftStart.Before(sorted[j].ShiftStart) })
+
+	var violations []models.ComplianceViolation
+	newViolation := func(rule string, date time.Time, group []models.Duty, actual, limit time.Duration, message string) models.ComplianceViolation {
+		violation := models.ComplianceViolation{
+			Rule:          rule,
+			Date:          date,
+			ActualMinutes: int(actual.Minutes()),
+			LimitMinutes:  int(limit.Minutes()),
+			Message:       message,
+		}
+		for _, duty := range group {
+			violation.DriverID = duty.DriverID
+			violation.DutyIDs = append(violation.DutyIDs, duty.ID)
+		}
+		return violation
+	}
+
+	for _, duty := range sorted {
+		driving := drivingTime(duty)
+		if rules.BreakAfter <= 0 || driving <= rules.BreakAfter {
+			continue
+		}
+		required := time.Duration((driving-1)/rules.BreakAfter) * rules.MinBreak
+		taken := time.Duration(duty.BreakMinutes) * time.Minute
+		if taken < required {
+			violations = append(violations, newViolation(RuleBreaks, startOfDay(duty.Date), []models.Duty{duty}, taken, required,
+				fmt.Sprintf("Shift on %s needs at least %s of breaks", duty.Date.Format(time.DateOnly), required)))
+		}
+	}
+
+	byDay := map[time.Time][]models.Duty{}
+	byWeek := map[time.Time][]models.Duty{}
+	for _, duty := range sorted {
+		day := startOfDay(duty.Date)
+		byDay[day] = append(byDay[day], duty)
+		week := startOfWeek(duty.Date)
+		byWeek[week] = append(byWeek[week], duty)
+	}
+	for day, group := range byDay {
+		var total time.Duration
+		for _, duty := range group {
+			total += drivingTime(duty)
+		}
+		if total > rules.MaxDailyDriving {
+			violations = append(violations, newViolation(RuleMaxDailyDriving, day, group, total, rules.MaxDailyDriving,
+				fmt.Sprintf("Driving time on %s exceeds %s", day.Format(time.DateOnly), rules.MaxDailyDriving)))
+		}
+	}
+	for week, group := range byWeek {
+		var total time.Duration
+		for _, duty := range group {
+			total += drivingTime(duty)
+		}
+		if total > rules.MaxWeeklyDriving {
+			violations = append(violations, newViolation(RuleMaxWeeklyDriving, week, group, total, rules.MaxWeeklyDriving,
+				fmt.Sprintf("Driving time in the week of %s exceeds %s", week.Format(time.DateOnly), rules.MaxWeeklyDriving)))
+		}
+	}
+
+	// Daily rest is the gap between the last shift of one working day and the
+	// first shift of the next; split shifts within a day are not rest.
+	for i := 1; i < len(sorted); i++ {
+		prev, next := sorted[i-1], sorted[i]
+		if sameDay(prev.Date, next.Date) {
+			continue
+		}
+		rest := next.ShiftStart.Sub(prev.ShiftEnd)
+		if rest < rules.MinDailyRest {
+			violations = append(violations, newViolation(RuleMinDailyRest, startOfDay(next.Date), []models.Duty{prev, next}, rest, rules.MinDailyRest,
+				fmt.Sprintf("Rest before the shift on %s is shorter than %s", next.Date.Format(time.DateOnly), rules.MinDailyRest)))
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Date.Before(violations[j].Date) })
+	return violations
+}
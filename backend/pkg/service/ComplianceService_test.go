@@ -0,0 +1,117 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newShift(id string, start time.Time, hours, breakMinutes int) models.Duty {
+	return models.Duty{
+		ID:           id,
+		Date:         startOfDay(start),
+		DriverID:     "driver",
+		ShiftStart:   start,
+		ShiftEnd:     start.Add(time.Duration(hours) * time.Hour),
+		BreakMinutes: breakMinutes,
+	}
+}
+
+func TestCheckWorkTime(t *testing.T) {
+	rules := DefaultWorkTimeRules()
+	monday := time.Date(2025, 3, 3, 6, 0, 0, 0, time.UTC)
+
+	t.Run("Compliant week", func(t *testing.T) {
+		var duties []models.Duty
+		for i := 0; i < 5; i++ {
+			duties = append(duties, newShift(string(rune('a'+i)), monday.AddDate(0, 0, i), 8, 45))
+		}
+		violations := checkWorkTime(rules, duties)
+		if len(violations) != 0 {
+			t.Errorf("Expected no violations, got %v", violations)
+		}
+	})
+
+	t.Run("Daily driving limit", func(t *testing.T) {
+		duties := []models.Duty{
+			newShift("a", monday, 6, 60),
+			newShift("b", monday.Add(8*time.Hour), 5, 30),
+		}
+		violations := checkWorkTime(rules, duties)
+		if len(violations) != 1 || violations[0].Rule != RuleMaxDailyDriving || violations[0].ActualMinutes != 570 {
+			t.Errorf("Expected daily driving violation of 570 minutes, got %v", violations)
+		}
+	})
+
+	t.Run("Missing break", func(t *testing.T) {
+		violations := checkWorkTime(rules, []models.Duty{newShift("a", monday, 8, 15)})
+		if len(violations) != 1 || violations[0].Rule != RuleBreaks || violations[0].LimitMinutes != 45 {
+			t.Errorf("Expected break violation, got %v", violations)
+		}
+	})
+
+	t.Run("Short daily rest", func(t *testing.T) {
+		duties := []models.Duty{
+			newShift("a", monday.Add(8*time.Hour), 8, 45),
+			newShift("b", monday.Add(24*time.Hour), 8, 45),
+		}
+		violations := checkWorkTime(rules, duties)
+		if len(violations) != 1 || violations[0].Rule != RuleMinDailyRest || len(violations[0].DutyIDs) != 2 {
+			t.Errorf("Expected daily rest violation, got %v", violations)
+		}
+	})
+
+	t.Run("Weekly limit", func(t *testing.T) {
+		var duties []models.Duty
+		for i := 0; i < 6; i++ {
+			duties = append(duties, newShift(string(rune('a'+i)), monday.AddDate(0, 0, i), 8, 45))
+		}
+		violations := checkWorkTime(rules, duties)
+		if len(violations) != 1 || violations[0].Rule != RuleMaxWeeklyDriving || !violations[0].Date.Equal(startOfDay(monday)) {
+			t.Errorf("Expected weekly violation, got %v", violations)
+		}
+	})
+}
+
+func TestComplianceService_GetDriverReport(t *testing.T) {
+	monday := time.Date(2025, 3, 3, 6, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		repo := &MockDutyRepository{getByDriverIdResp: []models.Duty{
+			newShift("a", monday, 8, 45),
+			newShift("b", monday.AddDate(0, 0, 1), 8, 0),
+			newShift("c", monday.AddDate(0, 0, 7), 8, 45),
+		}}
+		service := NewComplianceService(repo, DefaultWorkTimeRules())
+
+		report, err := service.GetDriverReport("driver", monday, monday.AddDate(0, 0, 7))
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if report == nil || len(report.Days) != 2 || report.TotalDrivingMinutes != 435+480 {
+			t.Errorf("Unexpected report days: %v", report)
+		}
+		if len(report.Violations) != 1 || report.Violations[0].Rule != RuleBreaks {
+			t.Errorf("Expected one break violation, got %v", report.Violations)
+		}
+	})
+
+	t.Run("Invalid period", func(t *testing.T) {
+		service := NewComplianceService(&MockDutyRepository{}, DefaultWorkTimeRules())
+
+		_, err := service.GetDriverReport("driver", monday, monday)
+		if err == nil || err.Error() != "Period end must be after its start" {
+			t.Errorf("Expected 'Period end must be after its start' error, got %v", err)
+		}
+	})
+
+	t.Run("Repo error", func(t *testing.T) {
+		service := NewComplianceService(&MockDutyRepository{getByDriverIdErr: errors.New("Database error")}, DefaultWorkTimeRules())
+
+		_, err := service.GetDriverReport("driver", monday, monday.AddDate(0, 0, 7))
+		if err == nil || err.Error() != "Database error" {
+			t.Errorf("Expected 'Database error', got %v", err)
+		}
+	})
+}
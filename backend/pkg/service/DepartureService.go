@@ -0,0 +1,145 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/repository"
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// scheduled departures are listed this far ahead
+	boardWindow = 2 * time.Hour
+	// a late bus stays on the board this long after its scheduled departure
+	maxBoardDelay      = 30 * time.Minute
+	MaxBoardDepartures = 12
+)
+
+type DepartureService struct {
+	tripRepo    repository.ITripRepository
+	routeRepo   repository.IRouteRepository
+	busRepo     repository.IBusRepository
+	busStopRepo repository.IBusStopRepository
+	arrivals    IArrivalService
+	alerts      IAlertService
+}
+
+func NewDepartureService(tripRepo repository.ITripRepository, routeRepo repository.IRouteRepository, busRepo repository.IBusRepository, busStopRepo repository.IBusStopRepository, arrivals IArrivalService, alerts IAlertService) *DepartureService {
+	s := &DepartureService{tripRepo, routeRepo, busRepo, busStopRepo, arrivals, alerts}
+	return s
+}
+
+// predictedArrival is the next predicted arrival of a bus at the stop
+type predictedArrival struct {
+	routeId     string
+	routeNumber string
+	vehicle     models.VehicleArrival
+}
+
+// GetBoard returns the next departures from the stop. A scheduled trip whose
+// bus is on the way takes the predicted time; trips without a live bus keep
+// their scheduled time and drop off the board once it has passed. Buses on
+// the way without a scheduled trip are listed too.
+func (s DepartureService) GetBoard(stopId string, now time.Time) (*models.DepartureBoard, error) {
+	stop, err := s.busStopRepo.GetById(stopId)
+	if err != nil && err.Error() != "Bus stop not found" {
+		return nil, err
+	}
+	if stop == nil {
+		return nil, errors.New("Bus stop not found")
+	}
+	scheduled, err := s.tripRepo.GetStopDepartures(stopId, now.Add(-maxBoardDelay), now.Add(boardWindow))
+	if err != nil {
+		return nil, err
+	}
+	predicted, err := s.arrivals.GetStopArrivals(stopId, now)
+	if err != nil {
+		return nil, err
+	}
+	routeNumbers := make(map[string]string)
+	next := make(map[string]predictedArrival)
+	for _, route := range predicted {
+		routeNumbers[route.RouteID] = route.RouteNumber
+		for _, vehicle := range route.Vehicles {
+			current, ok := next[vehicle.BusID]
+			if !ok || vehicle.ArrivesAt.Before(current.vehicle.ArrivesAt) {
+				next[vehicle.BusID] = predictedArrival{route.RouteID, route.RouteNumber, vehicle}
+			}
+		}
+	}
+	buses, err := s.busRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	labels := make(map[string]string)
+	for _, bus := range buses {
+		labels[bus.ID] = bus.RegisterNumber
+	}
+
+	departures := []models.Departure{}
+	for _, trip := range scheduled {
+		if trip.ActualDeparture != nil {
+			continue
+		}
+		scheduledAt := trip.Departure
+		departure := models.Departure{
+			TripID:    trip.TripID,
+			RouteID:   trip.RouteID,
+			BusID:     trip.BusID,
+			Label:     labels[trip.BusID],
+			Scheduled: &scheduledAt,
+			Expected:  scheduledAt,
+		}
+		if prediction, ok := next[trip.BusID]; ok && prediction.routeId == trip.RouteID {
+			departure.Expected = prediction.vehicle.ArrivesAt
+			departure.Realtime = true
+			delete(next, trip.BusID)
+		} else if scheduledAt.Before(now) {
+			continue
+		}
+		departures = append(departures, departure)
+	}
+	for busId, prediction := range next {
+		departures = append(departures, models.Departure{
+			RouteID:     prediction.routeId,
+			RouteNumber: prediction.routeNumber,
+			BusID:       busId,
+			Label:       prediction.vehicle.Label,
+			Expected:    prediction.vehicle.ArrivesAt,
+			Realtime:    true,
+		})
+	}
+	sort.SliceStable(departures, func(i, j int) bool { return departures[i].Expected.Before(departures[j].Expected) })
+	if len(departures) > MaxBoardDepartures {
+		departures = departures[:MaxBoardDepartures]
+	}
+
+	for i := range departures {
+		departure := &departures[i]
+		if departure.RouteNumber == "" {
+			if _, ok := routeNumbers[departure.RouteID]; !ok {
+				route, _ := s.routeRepo.GetById(departure.RouteID)
+				if route != nil {
+					routeNumbers[departure.RouteID] = route.Number
+				}
+			}
+			departure.RouteNumber = routeNumbers[departure.RouteID]
+		}
+		departure.Minutes = int(math.Ceil(departure.Expected.Sub(now).Minutes()))
+		if departure.Minutes < 0 {
+			departure.Minutes = 0
+		}
+		if departure.Scheduled != nil {
+			departure.DelayMinutes = int(math.Round(departure.Expected.Sub(*departure.Scheduled).Minutes()))
+		}
+	}
+
+	stops := []models.BusStop{*stop}
+	err = s.alerts.AttachToStops(stops, now)
+	if err != nil {
+		return nil, err
+	}
+	return &models.DepartureBoard{Stop: stops[0], GeneratedAt: now, Departures: departures}, nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDepartureService_GetBoard(t *testing.T) {
+	now := time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC)
+	stop := &models.BusStop{ID: "stop-1", Name: "Театральная"}
+	buses := []models.Bus{{ID: "bus-1", RegisterNumber: "А123ВС77"}, {ID: "bus-2", RegisterNumber: "В456ЕК77"}, {ID: "bus-3", RegisterNumber: "Е789КМ77"}}
+	departed := now.Add(-5 * time.Minute)
+	scheduled := []models.ScheduledDeparture{
+		// left already
+		{TripID: "trip-0", RouteID: "route-1", BusID: "bus-1", Departure: now.Add(-6 * time.Minute), ActualDeparture: &departed},
+		// late, its bus is on the way
+		{TripID: "trip-1", RouteID: "route-1", BusID: "bus-1", Departure: now.Add(-3 * time.Minute)},
+		// passed without a live bus
+		{TripID: "trip-2", RouteID: "route-2", BusID: "bus-2", Departure: now.Add(-time.Minute)},
+		{TripID: "trip-3", RouteID: "route-2", BusID: "bus-2", Departure: now.Add(20 * time.Minute)},
+	}
+	arrivals := &MockArrivalService{arrivalsResp: []models.RouteArrivals{
+		{RouteID: "route-1", RouteNumber: "15", Vehicles: []models.VehicleArrival{
+			{BusID: "bus-1", Label: "А123ВС77", ArrivesAt: now.Add(4 * time.Minute)},
+			{BusID: "bus-3", Label: "Е789КМ77", ArrivesAt: now.Add(9*time.Minute + 10*time.Second)},
+		}},
+	}}
+	alerts := NewAlertService(&MockServiceAlertRepository{getActiveResp: []models.ServiceAlert{{ID: "alert-1", StopIDs: []string{stop.ID}}}}, &MockRouteRepository{}, &MockBusStopRepository{})
+	newService := func(trips *MockTripRepository) *DepartureService {
+		return NewDepartureService(trips, &MockRouteRepository{getByIdResp: &models.Route{ID: "route-2", Number: "42"}}, &MockBusRepository{getAllResp: buses}, &MockBusStopRepository{getByIdResp: stop}, arrivals, alerts)
+	}
+
+	t.Run("Merged board", func(t *testing.T) {
+		board, err := newService(&MockTripRepository{departuresResp: scheduled}).GetBoard(stop.ID, now)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(board.Departures) != 3 {
+			t.Fatalf("Expected 3 departures, got %v", board.Departures)
+		}
+		first := board.Departures[0]
+		if first.TripID != "trip-1" || !first.Realtime || first.Minutes != 4 || first.DelayMinutes != 7 {
+			t.Errorf("Expected late trip-1 in 4 minutes, 7 minutes behind, got %v", first)
+		}
+		second := board.Departures[1]
+		if second.TripID != "" || second.BusID != "bus-3" || second.Scheduled != nil || second.Minutes != 10 || second.RouteNumber != "15" {
+			t.Errorf("Expected unscheduled bus-3 of route 15 in 10 minutes, got %v", second)
+		}
+		third := board.Departures[2]
+		if third.TripID != "trip-3" || third.Realtime || third.Minutes != 20 || third.RouteNumber != "42" || third.Label != "В456ЕК77" {
+			t.Errorf("Expected scheduled trip-3 of route 42 in 20 minutes, got %v", third)
+		}
+		if len(board.Stop.Alerts) != 1 {
+			t.Errorf("Expected stop alert on the board, got %v", board.Stop.Alerts)
+		}
+	})
+
+	t.Run("Unknown stop", func(t *testing.T) {
+		service := NewDepartureService(&MockTripRepository{}, &MockRouteRepository{}, &MockBusRepository{}, &MockBusStopRepository{}, arrivals, alerts)
+
+		_, err := service.GetBoard("missing", now)
+		if err == nil || err.Error() != "Bus stop not found" {
+			t.Errorf("Expected 'Bus stop not found' error, got %v", err)
+		}
+	})
+
+	t.Run("Stop lookup fails", func(t *testing.T) {
+		service := NewDepartureService(&MockTripRepository{}, &MockRouteRepository{}, &MockBusRepository{}, &MockBusStopRepository{getByIdErr: errors.New("Database error")}, arrivals, alerts)
+
+		_, err := service.GetBoard(stop.ID, now)
+		if err == nil || err.Error() != "Database error" {
+			t.Errorf("Expected 'Database error', got %v", err)
+		}
+	})
+}
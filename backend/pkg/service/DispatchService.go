@@ -0,0 +1,249 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/repository"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type DispatchService struct {
+	repairRepo     repository.IBusRepairRepository
+	routeRepo      repository.IRouteRepository
+	busRepo        repository.IBusRepository
+	qualifications IQualificationService
+}
+
+func NewDispatchService(repairRepo repository.IBusRepairRepository, routeRepo repository.IRouteRepository, busRepo repository.IBusRepository, qualifications IQualificationService) *DispatchService {
+	s := &DispatchService{repairRepo, routeRepo, busRepo, qualifications}
+	return s
+}
+
+// ProposeReplacements lists, for every route the bus serves, the spare buses
+// that could take it over: buses neither assigned anywhere nor in repair,
+// of a vehicle class already running on the route.
+func (s DispatchService) ProposeReplacements(busId string) (*models.ReplacementProposal, error) {
+	bus, _ := s.busRepo.GetById(busId)
+	if bus == nil {
+		return nil, errors.New("Bus not found")
+	}
+	return s.propose(*bus, time.Now())
+}
+
+// ReportBreakdown takes the bus off the routes it serves now into repair and
+// puts the chosen spare buses on them until the bus's periods were to end,
+// in one step.
+func (s DispatchService) ReportBreakdown(breakdown *models.Breakdown) (*models.BreakdownResult, error) {
+	breakdown.Reason = strings.TrimSpace(breakdown.Reason)
+	if breakdown.Reason == "" {
+		return nil, errors.New("Breakdown reason is required")
+	}
+	bus, _ := s.busRepo.GetById(breakdown.BusID)
+	if bus == nil {
+		return nil, errors.New("Bus not found")
+	}
+	repair, err := s.repairRepo.GetOpen(bus.ID)
+	if err != nil && err.Error() != "Repair not found" {
+		return nil, err
+	}
+	if repair != nil {
+		return nil, errors.New("Bus is already in repair")
+	}
+	now := time.Now()
+	proposal, err := s.propose(*bus, now)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make(map[string]map[string]models.Bus)
+	for _, route := range proposal.Routes {
+		candidates[route.RouteID] = make(map[string]models.Bus)
+		for _, spare := range route.Candidates {
+			candidates[route.RouteID][spare.ID] = spare
+		}
+	}
+
+	periods, err := s.currentAssignments(bus.ID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.BreakdownResult{Assignments: []models.BusAssignment{}, Warnings: []string{}}
+	replaced := make(map[string]bool)
+	used := make(map[string]bool)
+	for _, replacement := range breakdown.Replacements {
+		routeCandidates, ok := candidates[replacement.RouteID]
+		if !ok {
+			return nil, fmt.Errorf("Bus doesn't serve route %s", replacement.RouteID)
+		}
+		if replaced[replacement.RouteID] {
+			return nil, fmt.Errorf("Route %s is replaced twice", replacement.RouteID)
+		}
+		spare, ok := routeCandidates[replacement.SpareBusID]
+		if !ok {
+			return nil, fmt.Errorf("Bus %s can't replace the bus on route %s", replacement.SpareBusID, replacement.RouteID)
+		}
+		if used[spare.ID] {
+			return nil, fmt.Errorf("Bus %s can replace the bus on one route only", spare.ID)
+		}
+		replaced[replacement.RouteID] = true
+		used[spare.ID] = true
+		warnings, err := checkDriversQualified(s.routeRepo, s.qualifications, replacement.RouteID, spare)
+		if err != nil {
+			return nil, err
+		}
+		result.Warnings = append(result.Warnings, warnings...)
+		// the spare takes over the rest of the period the bus served
+		result.Assignments = append(result.Assignments, models.BusAssignment{
+			RouteID:  replacement.RouteID,
+			BusID:    spare.ID,
+			StartsAt: now,
+			EndsAt:   periods[replacement.RouteID].EndsAt,
+		})
+	}
+	for _, route := range proposal.Routes {
+		if !replaced[route.RouteID] {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Route %s is left without a replacement bus", route.RouteNumber))
+		}
+	}
+
+	result.Repair = models.BusRepair{
+		BusID:      bus.ID,
+		Reason:     breakdown.Reason,
+		IncidentID: strings.TrimSpace(breakdown.IncidentID),
+		StartedAt:  now,
+	}
+	err = s.repairRepo.ReplaceBus(&result.Repair, result.Assignments)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FinishRepair returns the bus to the spare pool
+func (s DispatchService) FinishRepair(busId string) (*models.BusRepair, error) {
+	repair, err := s.repairRepo.GetOpen(busId)
+	if err != nil {
+		return nil, err
+	}
+	if repair == nil {
+		return nil, errors.New("Repair not found")
+	}
+	now := time.Now()
+	err = s.repairRepo.Finish(busId, now)
+	if err != nil {
+		return nil, err
+	}
+	repair.FinishedAt = &now
+	return repair, nil
+}
+
+func (s DispatchService) propose(bus models.Bus, now time.Time) (*models.ReplacementProposal, error) {
+	periods, err := s.currentAssignments(bus.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	proposal := &models.ReplacementProposal{Bus: bus, Routes: []models.RouteReplacement{}}
+	if len(periods) == 0 {
+		return proposal, nil
+	}
+	spares, err := s.spareBuses(now)
+	if err != nil {
+		return nil, err
+	}
+	routeIds := make([]string, 0, len(periods))
+	for routeId := range periods {
+		routeIds = append(routeIds, routeId)
+	}
+	sort.Strings(routeIds)
+	for _, routeId := range routeIds {
+		route, err := s.routeRepo.GetById(routeId)
+		if err != nil {
+			return nil, err
+		}
+		routeBuses, err := s.routeRepo.GetAllBusesAt(routeId, now)
+		if err != nil {
+			return nil, err
+		}
+		classes := map[string]bool{bus.VehicleClass: true}
+		for _, routeBus := range routeBuses {
+			classes[routeBus.VehicleClass] = true
+		}
+		replacement := models.RouteReplacement{RouteID: route.ID, RouteNumber: route.Number, Candidates: []models.Bus{}}
+		for _, spare := range spares {
+			if classes[spare.VehicleClass] {
+				replacement.Candidates = append(replacement.Candidates, spare)
+			}
+		}
+		proposal.Routes = append(proposal.Routes, replacement)
+	}
+	return proposal, nil
+}
+
+// currentAssignments returns the periods the bus serves at the given time,
+// by route. Periods that start later are left to the bus.
+func (s DispatchService) currentAssignments(busId string, now time.Time) (map[string]models.BusAssignment, error) {
+	assignments, err := s.routeRepo.GetBusAssignments(busId)
+	if err != nil {
+		return nil, err
+	}
+	periods := make(map[string]models.BusAssignment)
+	for _, assignment := range assignments {
+		if assignment.StartsAt.After(now) || (assignment.EndsAt != nil && !assignment.EndsAt.After(now)) {
+			continue
+		}
+		periods[assignment.RouteID] = assignment
+	}
+	return periods, nil
+}
+
+// activeRoutes returns the routes the bus is assigned to now or later
+func (s DispatchService) activeRoutes(busId string, now time.Time) ([]string, error) {
+	assignments, err := s.routeRepo.GetBusAssignments(busId)
+	if err != nil {
+		return nil, err
+	}
+	remaining := models.BusAssignment{StartsAt: now}
+	var routeIds []string
+	seen := make(map[string]bool)
+	for _, assignment := range assignments {
+		if seen[assignment.RouteID] || !assignmentsOverlap(assignment, remaining) {
+			continue
+		}
+		seen[assignment.RouteID] = true
+		routeIds = append(routeIds, assignment.RouteID)
+	}
+	return routeIds, nil
+}
+
+// spareBuses returns the buses free from now on and not in repair
+func (s DispatchService) spareBuses(now time.Time) ([]models.Bus, error) {
+	buses, err := s.busRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	repairs, err := s.repairRepo.GetAllOpen()
+	if err != nil {
+		return nil, err
+	}
+	inRepair := make(map[string]bool)
+	for _, repair := range repairs {
+		inRepair[repair.BusID] = true
+	}
+	var spares []models.Bus
+	for _, bus := range buses {
+		if inRepair[bus.ID] {
+			continue
+		}
+		routeIds, err := s.activeRoutes(bus.ID, now)
+		if err != nil {
+			return nil, err
+		}
+		if len(routeIds) == 0 {
+			spares = append(spares, bus)
+		}
+	}
+	return spares, nil
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+type MockBusRepairRepository struct {
+	getOpenResp    *models.BusRepair
+	getOpenErr     error
+	getAllOpenResp []models.BusRepair
+	getAllOpenErr  error
+	replaceBusErr  error
+	finishErr      error
+	repair         *models.BusRepair
+	replacements   []models.BusAssignment
+}
+
+func (m *MockBusRepairRepository) GetOpen(busId string) (*models.BusRepair, error) {
+	return m.getOpenResp, m.getOpenErr
+}
+
+func (m *MockBusRepairRepository) GetAllOpen() ([]models.BusRepair, error) {
+	return m.getAllOpenResp, m.getAllOpenErr
+}
+
+func (m *MockBusRepairRepository) ReplaceBus(repair *models.BusRepair, replacements []models.BusAssignment) error {
+	m.repair = repair
+	m.replacements = replacements
+	return m.replaceBusErr
+}
+
+func (m *MockBusRepairRepository) Finish(busId string, at time.Time) error {
+	return m.finishErr
+}
+
+func TestDispatchService(t *testing.T) {
+	failing := models.Bus{ID: "bus-1", Brand: "ЛиАЗ", BusModel: "5292", VehicleClass: "D"}
+	spare := models.Bus{ID: "bus-2", Brand: "ЛиАЗ", BusModel: "5292", VehicleClass: "D"}
+	small := models.Bus{ID: "bus-3", Brand: "ПАЗ", BusModel: "3204", VehicleClass: "D1"}
+	busy := models.Bus{ID: "bus-4", Brand: "ЛиАЗ", BusModel: "5292", VehicleClass: "D"}
+	repaired := models.Bus{ID: "bus-5", Brand: "ЛиАЗ", BusModel: "5292", VehicleClass: "D"}
+	ended := time.Now().Add(-24 * time.Hour)
+	ends := time.Now().Add(48 * time.Hour)
+	assignments := map[string][]models.BusAssignment{
+		failing.ID: {
+			{RouteID: "route-1", BusID: failing.ID, StartsAt: time.Now().Add(-48 * time.Hour), EndsAt: &ends},
+			// a later period stays with the bus
+			{RouteID: "route-3", BusID: failing.ID, StartsAt: time.Now().Add(72 * time.Hour)},
+		},
+		busy.ID: {{RouteID: "route-2", BusID: busy.ID, StartsAt: time.Now().Add(-48 * time.Hour)}},
+		// assignment that is over doesn't keep the bus busy
+		spare.ID: {{RouteID: "route-2", BusID: spare.ID, StartsAt: time.Now().Add(-48 * time.Hour), EndsAt: &ended}},
+	}
+	newService := func(repairs *MockBusRepairRepository) *DispatchService {
+		routeRepo := &MockRouteRepository{
+			getByIdResp:         &models.Route{ID: "route-1", Number: "15"},
+			getAllBusesByIdResp: []models.Bus{failing},
+			busAssignments:      assignments,
+		}
+		busRepo := &MockBusRepository{getByIdResp: &failing, getAllResp: []models.Bus{failing, spare, small, busy, repaired}}
+		qualifications := NewQualificationService(&MockDriverQualificationRepository{}, nil, nil)
+		return NewDispatchService(repairs, routeRepo, busRepo, qualifications)
+	}
+	inRepair := []models.BusRepair{{BusID: repaired.ID}}
+
+	t.Run("Proposal", func(t *testing.T) {
+		proposal, err := newService(&MockBusRepairRepository{getAllOpenResp: inRepair}).ProposeReplacements(failing.ID)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(proposal.Routes) != 1 || proposal.Routes[0].RouteNumber != "15" {
+			t.Fatalf("Expected route 15 to be replaced, got %v", proposal.Routes)
+		}
+		candidates := proposal.Routes[0].Candidates
+		if len(candidates) != 1 || candidates[0].ID != spare.ID {
+			t.Errorf("Expected only the free bus of the same class, got %v", candidates)
+		}
+	})
+
+	t.Run("Breakdown with replacement", func(t *testing.T) {
+		repairs := &MockBusRepairRepository{getAllOpenResp: inRepair}
+		breakdown := &models.Breakdown{
+			BusID:        failing.ID,
+			Reason:       " Отказ тормозной системы ",
+			Replacements: []models.BusReplacement{{RouteID: "route-1", SpareBusID: spare.ID}},
+		}
+
+		result, err := newService(repairs).ReportBreakdown(breakdown)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if repairs.repair == nil || repairs.repair.BusID != failing.ID || repairs.repair.Reason != "Отказ тормозной системы" {
+			t.Errorf("Expected the failing bus to go into repair, got %v", repairs.repair)
+		}
+		if len(repairs.replacements) != 1 || repairs.replacements[0].BusID != spare.ID || repairs.replacements[0].RouteID != "route-1" {
+			t.Fatalf("Expected the spare bus on route-1, got %v", repairs.replacements)
+		}
+		if endsAt := repairs.replacements[0].EndsAt; endsAt == nil || !endsAt.Equal(ends) {
+			t.Errorf("Expected the spare to take over until %v, got %v", ends, endsAt)
+		}
+		// the route has no drivers trained on the spare
+		if len(result.Warnings) != 1 || result.Warnings[0] != "None of the route drivers is qualified for bus model ЛиАЗ 5292" {
+			t.Errorf("Expected qualification warning only, got %v", result.Warnings)
+		}
+	})
+
+	t.Run("Breakdown without replacement", func(t *testing.T) {
+		repairs := &MockBusRepairRepository{}
+		breakdown := &models.Breakdown{BusID: failing.ID, Reason: "Перегрев двигателя"}
+
+		result, err := newService(repairs).ReportBreakdown(breakdown)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(result.Warnings) != 1 || result.Warnings[0] != "Route 15 is left without a replacement bus" {
+			t.Errorf("Expected warning about route 15, got %v", result.Warnings)
+		}
+	})
+
+	t.Run("Spare of another class", func(t *testing.T) {
+		breakdown := &models.Breakdown{
+			BusID:        failing.ID,
+			Reason:       "Перегрев двигателя",
+			Replacements: []models.BusReplacement{{RouteID: "route-1", SpareBusID: small.ID}},
+		}
+
+		_, err := newService(&MockBusRepairRepository{}).ReportBreakdown(breakdown)
+		if err == nil || err.Error() != "Bus bus-3 can't replace the bus on route route-1" {
+			t.Errorf("Expected 'Bus bus-3 can't replace the bus on route route-1' error, got %v", err)
+		}
+	})
+
+	t.Run("Route the bus doesn't serve", func(t *testing.T) {
+		breakdown := &models.Breakdown{
+			BusID:        failing.ID,
+			Reason:       "Перегрев двигателя",
+			Replacements: []models.BusReplacement{{RouteID: "route-2", SpareBusID: spare.ID}},
+		}
+
+		_, err := newService(&MockBusRepairRepository{}).ReportBreakdown(breakdown)
+		if err == nil || err.Error() != "Bus doesn't serve route route-2" {
+			t.Errorf("Expected 'Bus doesn't serve route route-2' error, got %v", err)
+		}
+	})
+
+	t.Run("Already in repair", func(t *testing.T) {
+		repairs := &MockBusRepairRepository{getOpenResp: &models.BusRepair{BusID: failing.ID}}
+
+		_, err := newService(repairs).ReportBreakdown(&models.Breakdown{BusID: failing.ID, Reason: "Перегрев двигателя"})
+		if err == nil || err.Error() != "Bus is already in repair" {
+			t.Errorf("Expected 'Bus is already in repair' error, got %v", err)
+		}
+	})
+
+	t.Run("Repair lookup fails", func(t *testing.T) {
+		repairs := &MockBusRepairRepository{getOpenErr: errors.New("connection refused")}
+
+		_, err := newService(repairs).ReportBreakdown(&models.Breakdown{BusID: failing.ID, Reason: "Перегрев двигателя"})
+		if err == nil || err.Error() != "connection refused" {
+			t.Errorf("Expected 'connection refused' error, got %v", err)
+		}
+	})
+
+	t.Run("Finish repair", func(t *testing.T) {
+		repairs := &MockBusRepairRepository{getOpenResp: &models.BusRepair{BusID: failing.ID}}
+
+		repair, err := newService(repairs).FinishRepair(failing.ID)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if repair.FinishedAt == nil {
+			t.Error("Expected repair to be finished")
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	passportPattern = regexp.MustCompile(`^\d{10}$`)
+	snilsPattern    = regexp.MustCompile(`^\d{11}$`)
+	licensePattern  = regexp.MustCompile(`^(\d{2})(\d{2}|[АВЕКМНОРСТУХ]{2})(\d{6})$`)
+)
+
+// latin letters that look the same as the cyrillic ones allowed in a licence series
+var licenseLatinToCyrillic = strings.NewReplacer(
+	"A", "А", "B", "В", "E", "Е", "K", "К", "M", "М", "H", "Н",
+	"O", "О", "P", "Р", "C", "С", "T", "Т", "Y", "У", "X", "Х",
+)
+
+// stripSeparators removes spaces and dashes people put into document numbers
+func stripSeparators(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '-' {
+			return -1
+		}
+		return r
+	}, value)
+}
+
+// normalizePassportSeries returns the passport as "NNNN NNNNNN"
+func normalizePassportSeries(value string) (string, error) {
+	digits := stripSeparators(value)
+	if !passportPattern.MatchString(digits) {
+		return "", errors.New("Invalid passport series, expected NNNN NNNNNN")
+	}
+	return digits[:4] + " " + digits[4:], nil
+}
+
+// normalizeSnils checks the SNILS control number and returns it as "NNN-NNN-NNN NN"
+func normalizeSnils(value string) (string, error) {
+	digits := stripSeparators(value)
+	if !snilsPattern.MatchString(digits) {
+		return "", errors.New("Invalid SNILS, expected NNN-NNN-NNN NN")
+	}
+	if snilsChecksum(digits[:9]) != digits[9:] {
+		return "", errors.New("Invalid SNILS checksum")
+	}
+	return fmt.Sprintf("%s-%s-%s %s", digits[:3], digits[3:6], digits[6:9], digits[9:]), nil
+}
+
+// snilsChecksum computes the control number: digits weighted 9..1,
+// sums of 100 and 101 give 00, larger sums are taken modulo 101
+func snilsChecksum(number string) string {
+	sum := 0
+	for i, r := range number {
+		sum += int(r-'0') * (9 - i)
+	}
+	if sum > 101 {
+		sum %= 101
+	}
+	if sum == 100 || sum == 101 {
+		sum = 0
+	}
+	return fmt.Sprintf("%02d", sum)
+}
+
+// normalizeLicenseSeries returns the licence as "NN XX NNNNNN", where XX is
+// either two digits or two cyrillic letters on older licences
+func normalizeLicenseSeries(value string) (string, error) {
+	series := licenseLatinToCyrillic.Replace(strings.ToUpper(stripSeparators(value)))
+	parts := licensePattern.FindStringSubmatch(series)
+	if parts == nil {
+		return "", errors.New("Invalid license series, expected NN NN NNNNNN")
+	}
+	return parts[1] + " " + parts[2] + " " + parts[3], nil
+}
+
+// normalizeDriverDocuments validates the identity documents of a driver
+// and rewrites them in their stored form
+func normalizeDriverDocuments(driver *models.Driver) error {
+	passport, err := normalizePassportSeries(driver.PassportSeries)
+	if err != nil {
+		return err
+	}
+	snils, err := normalizeSnils(driver.Snils)
+	if err != nil {
+		return err
+	}
+	license, err := normalizeLicenseSeries(driver.LicenseSeries)
+	if err != nil {
+		return err
+	}
+	driver.PassportSeries = passport
+	driver.Snils = snils
+	driver.LicenseSeries = license
+	return nil
+}
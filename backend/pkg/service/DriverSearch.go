@@ -0,0 +1,96 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	DefaultSearchPageSize = 20
+	MaxSearchPageSize     = 100
+)
+
+// normalizeName lowercases the value and treats ё as е.
+func normalizeName(value string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(value)), "ё", "е")
+}
+
+// matchDriver scores how well the query words match the driver's full name.
+// Every word must match one of the name parts, otherwise the score is 0.
+func matchDriver(driver models.Driver, words []string) float64 {
+	parts := []string{normalizeName(driver.Surname), normalizeName(driver.Name), normalizeName(driver.Patronymic)}
+	total := 0.0
+	for _, word := range words {
+		best := 0.0
+		for _, part := range parts {
+			if score := matchWord(part, word); score > best {
+				best = score
+			}
+		}
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+	return total / float64(len(words))
+}
+
+// matchWord scores a single query word against a name part: exact matches
+// score 1, prefixes 0.9, and words within the allowed edit distance of the
+// part, or of its beginning, score lower the more typos they have.
+func matchWord(part, word string) float64 {
+	if part == "" {
+		return 0
+	}
+	if part == word {
+		return 1
+	}
+	length := utf8.RuneCountInString(word)
+	if length >= 2 && strings.HasPrefix(part, word) {
+		return 0.9
+	}
+	if length < 3 {
+		return 0
+	}
+	allowed := 1
+	if length > 5 {
+		allowed = 2
+	}
+	if d := levenshtein(part, word); d <= allowed {
+		return 0.8 * (1 - float64(d)/float64(max(length, utf8.RuneCountInString(part))))
+	}
+	if d := levenshtein(prefixOf(part, length), word); d <= allowed {
+		return 0.7 * (1 - float64(d)/float64(length))
+	}
+	return 0
+}
+
+func prefixOf(value string, n int) string {
+	runes := []rune(value)
+	if len(runes) <= n {
+		return value
+	}
+	return string(runes[:n])
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
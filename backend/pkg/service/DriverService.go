@@ -4,6 +4,8 @@ import (
 	"backend/pkg/models"
 	"backend/pkg/repository"
 	"errors"
+	"sort"
+	"strings"
 )
 
 type DriverService struct {
@@ -28,6 +30,9 @@ func (ds DriverService) GetById(id string) (*models.Driver, error) {
 }
 
 func (ds DriverService) GetByPassportSeries(series string) (*models.Driver, error) {
+	if normalized, err := normalizePassportSeries(series); err == nil {
+		series = normalized
+	}
 	driver, err := ds.repo.GetByPassportSeries(series)
 	if err != nil {
 		return nil, err
@@ -39,6 +44,9 @@ func (ds DriverService) GetByPassportSeries(series string) (*models.Driver, erro
 }
 
 func (ds DriverService) Add(driver *models.Driver) error {
+	if err := normalizeDriverDocuments(driver); err != nil {
+		return err
+	}
 	err := ds.repo.Add(driver)
 	return err
 }
@@ -56,6 +64,57 @@ func (ds DriverService) DeleteById(id string) error {
 }
 
 func (ds DriverService) UpdateById(driver *models.Driver) error {
+	if err := normalizeDriverDocuments(driver); err != nil {
+		return err
+	}
 	err := ds.repo.UpdateById(driver)
 	return err
 }
+
+// Search finds drivers by name, surname and patronymic, tolerating typos,
+// and returns the requested page of results ranked by relevance.
+func (ds DriverService) Search(query string, page, pageSize int) (*models.DriverSearchPage, error) {
+	words := strings.Fields(normalizeName(query))
+	if len(words) == 0 {
+		return nil, errors.New("Search query is empty")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultSearchPageSize
+	}
+	if pageSize > MaxSearchPageSize {
+		pageSize = MaxSearchPageSize
+	}
+	drivers, err := ds.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	hits := []models.DriverSearchHit{}
+	for _, driver := range drivers {
+		if score := matchDriver(driver, words); score > 0 {
+			hits = append(hits, models.DriverSearchHit{Driver: driver, Score: score})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		a, b := hits[i].Driver, hits[j].Driver
+		if a.Surname != b.Surname {
+			return a.Surname < b.Surname
+		}
+		return a.Name < b.Name
+	})
+
+	result := &models.DriverSearchPage{Items: []models.DriverSearchHit{}, Total: len(hits), Page: page, PageSize: pageSize}
+	// Compare page numbers rather than offsets so that a huge page cannot
+	// overflow (page-1)*pageSize.
+	if pages := (len(hits) + pageSize - 1) / pageSize; page-1 < pages {
+		start := (page - 1) * pageSize
+		end := min(start+pageSize, len(hits))
+		result.Items = hits[start:end]
+	}
+	return result, nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"github.com/google/uuid"
 
+	"math"
 	"testing"
 	"time"
 )
@@ -15,6 +16,7 @@ type MockDriverRepository struct {
 	getByIdErr        error
 	getByPassportResp *models.Driver
 	getByPassportErr  error
+	passportQuery     string
 	addErr            error
 	getAllResp        []models.Driver
 	deleteByIdErr     error
@@ -26,6 +28,7 @@ func (m *MockDriverRepository) GetById(id string) (*models.Driver, error) {
 }
 
 func (m *MockDriverRepository) GetByPassportSeries(series string) (*models.Driver, error) {
+	m.passportQuery = series
 	return m.getByPassportResp, m.getByPassportErr
 }
 
@@ -53,9 +56,9 @@ func TestDriverService_GetById(t *testing.T) {
 		Surname:        "Doe",
 		Patronymic:     "Ivanovich",
 		BirthDate:      fixedTime,
-		PassportSeries: "AB123456",
-		Snils:          "123-456-789 00",
-		LicenseSeries:  "CD789012",
+		PassportSeries: "4510 123456",
+		Snils:          "112-233-445 95",
+		LicenseSeries:  "77 12 345678",
 	}
 
 	t.Run("Get existing driver by ID", func(t *testing.T) {
@@ -90,9 +93,9 @@ func TestDriverService_GetByPassportSeries(t *testing.T) {
 		Surname:        "Doe",
 		Patronymic:     "Ivanovich",
 		BirthDate:      fixedTime,
-		PassportSeries: "AB123456",
-		Snils:          "123-456-789 00",
-		LicenseSeries:  "CD789012",
+		PassportSeries: "4510 123456",
+		Snils:          "112-233-445 95",
+		LicenseSeries:  "77 12 345678",
 	}
 
 	t.Run("Get existing driver by passport series", func(t *testing.T) {
@@ -108,11 +111,24 @@ func TestDriverService_GetByPassportSeries(t *testing.T) {
 		}
 	})
 
+	t.Run("Passport series matches regardless of spacing", func(t *testing.T) {
+		mockRepo := &MockDriverRepository{getByPassportResp: driver}
+		service := NewDriverService(mockRepo)
+
+		_, err := service.GetByPassportSeries("4510123456")
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if mockRepo.passportQuery != "4510 123456" {
+			t.Errorf("Expected lookup by '4510 123456', got '%s'", mockRepo.passportQuery)
+		}
+	})
+
 	t.Run("Get non-existent driver by passport series", func(t *testing.T) {
 		mockRepo := &MockDriverRepository{getByPassportErr: errors.New("Driver not found")}
 		service := NewDriverService(mockRepo)
 
-		_, err := service.GetByPassportSeries("XY789012")
+		_, err := service.GetByPassportSeries("4511 654321")
 		if err == nil || err.Error() != "Driver not found" {
 			t.Errorf("Expected 'Driver not found' error, got %v", err)
 		}
@@ -126,9 +142,9 @@ func TestDriverService_Add(t *testing.T) {
 		Surname:        "Doe",
 		Patronymic:     "Ivanovich",
 		BirthDate:      fixedTime,
-		PassportSeries: "AB123456",
-		Snils:          "123-456-789 00",
-		LicenseSeries:  "CD789012",
+		PassportSeries: "4510 123456",
+		Snils:          "112-233-445 95",
+		LicenseSeries:  "77 12 345678",
 	}
 
 	t.Run("Add new driver", func(t *testing.T) {
@@ -150,6 +166,67 @@ func TestDriverService_Add(t *testing.T) {
 			t.Errorf("Expected 'Database error', got %v", err)
 		}
 	})
+
+	t.Run("Documents are stored normalized", func(t *testing.T) {
+		mockRepo := &MockDriverRepository{}
+		service := NewDriverService(mockRepo)
+		unformatted := *driver
+		unformatted.PassportSeries = " 4510-123456"
+		unformatted.Snils = "11223344595"
+		unformatted.LicenseSeries = "99ab123456"
+
+		err := service.Add(&unformatted)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if unformatted.PassportSeries != "4510 123456" {
+			t.Errorf("Expected passport '4510 123456', got '%s'", unformatted.PassportSeries)
+		}
+		if unformatted.Snils != "112-233-445 95" {
+			t.Errorf("Expected SNILS '112-233-445 95', got '%s'", unformatted.Snils)
+		}
+		if unformatted.LicenseSeries != "99 АВ 123456" {
+			t.Errorf("Expected license '99 АВ 123456', got '%s'", unformatted.LicenseSeries)
+		}
+	})
+
+	invalid := []struct {
+		name   string
+		modify func(d *models.Driver)
+		err    string
+	}{
+		{"Invalid passport", func(d *models.Driver) { d.PassportSeries = "AB123456" }, "Invalid passport series, expected NNNN NNNNNN"},
+		{"Invalid SNILS format", func(d *models.Driver) { d.Snils = "112-233-445" }, "Invalid SNILS, expected NNN-NNN-NNN NN"},
+		{"Invalid SNILS checksum", func(d *models.Driver) { d.Snils = "112-233-445 96" }, "Invalid SNILS checksum"},
+		{"Invalid license", func(d *models.Driver) { d.LicenseSeries = "АБВ 1234567" }, "Invalid license series, expected NN NN NNNNNN"},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &MockDriverRepository{}
+			service := NewDriverService(mockRepo)
+			bad := *driver
+			tc.modify(&bad)
+
+			err := service.Add(&bad)
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("Expected '%s', got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestSnilsChecksum(t *testing.T) {
+	cases := map[string]string{
+		"112233445": "95",
+		"987654321": "83",
+		"000000000": "00",
+		"920000003": "00",
+	}
+	for number, want := range cases {
+		if got := snilsChecksum(number); got != want {
+			t.Errorf("Expected checksum %s for %s, got %s", want, number, got)
+		}
+	}
 }
 
 func TestDriverService_GetAll(t *testing.T) {
@@ -160,9 +237,9 @@ func TestDriverService_GetAll(t *testing.T) {
 		Surname:        "Doe",
 		Patronymic:     "Ivanovich",
 		BirthDate:      fixedTime,
-		PassportSeries: "AB123456",
-		Snils:          "123-456-789 00",
-		LicenseSeries:  "CD789012",
+		PassportSeries: "4510 123456",
+		Snils:          "112-233-445 95",
+		LicenseSeries:  "77 12 345678",
 	}
 	driver2 := models.Driver{
 		ID:             uuid.New().String(),
@@ -170,9 +247,9 @@ func TestDriverService_GetAll(t *testing.T) {
 		Surname:        "Doe",
 		Patronymic:     "Ivanovna",
 		BirthDate:      fixedTime,
-		PassportSeries: "XY789012",
-		Snils:          "987-654-321 00",
-		LicenseSeries:  "EF345678",
+		PassportSeries: "4511 654321",
+		Snils:          "987-654-321 83",
+		LicenseSeries:  "99 АВ 123456",
 	}
 
 	t.Run("Get all drivers", func(t *testing.T) {
@@ -226,9 +303,9 @@ func TestDriverService_UpdateById(t *testing.T) {
 		Surname:        "Doe",
 		Patronymic:     "Ivanovich",
 		BirthDate:      fixedTime,
-		PassportSeries: "AB123456",
-		Snils:          "123-456-789 00",
-		LicenseSeries:  "CD789012",
+		PassportSeries: "4510 123456",
+		Snils:          "112-233-445 95",
+		LicenseSeries:  "77 12 345678",
 	}
 
 	t.Run("Update existing driver", func(t *testing.T) {
@@ -251,3 +328,90 @@ func TestDriverService_UpdateById(t *testing.T) {
 		}
 	})
 }
+
+func TestDriverService_Search(t *testing.T) {
+	drivers := []models.Driver{
+		{ID: "d1", Name: "Пётр", Surname: "Иванов", Patronymic: "Сергеевич"},
+		{ID: "d2", Name: "Иван", Surname: "Петров", Patronymic: "Алексеевич"},
+		{ID: "d3", Name: "Алексей", Surname: "Смирнов", Patronymic: "Иванович"},
+		{ID: "d4", Name: "Фёдор", Surname: "Ковалёв", Patronymic: "Петрович"},
+	}
+	service := NewDriverService(&MockDriverRepository{getAllResp: drivers})
+
+	ids := func(page *models.DriverSearchPage) []string {
+		var result []string
+		for _, hit := range page.Items {
+			result = append(result, hit.Driver.ID)
+		}
+		return result
+	}
+
+	t.Run("Exact match ranks first", func(t *testing.T) {
+		page, err := service.Search("иван", 1, 10)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		got := ids(page)
+		if len(got) != 3 || got[0] != "d2" {
+			t.Errorf("Expected Ivan first among 3 matches, got %v", got)
+		}
+	})
+
+	t.Run("Case-insensitive and ё equals е", func(t *testing.T) {
+		page, err := service.Search("ПЕТР", 1, 10)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if got := ids(page); len(got) == 0 || got[0] != "d1" {
+			t.Errorf("Expected Пётр first, got %v", got)
+		}
+
+		page, _ = service.Search("ковалев федор", 1, 10)
+		if got := ids(page); len(got) != 1 || got[0] != "d4" || page.Items[0].Score != 1 {
+			t.Errorf("Expected exact match for Ковалёв Фёдор, got %v", page.Items)
+		}
+	})
+
+	t.Run("Tolerates typos", func(t *testing.T) {
+		page, err := service.Search("Смирнав", 1, 10)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if got := ids(page); len(got) != 1 || got[0] != "d3" {
+			t.Errorf("Expected Смирнов, got %v", got)
+		}
+	})
+
+	t.Run("Every word must match", func(t *testing.T) {
+		page, _ := service.Search("Иванов Фёдор", 1, 10)
+		if page.Total != 0 {
+			t.Errorf("Expected no matches, got %v", ids(page))
+		}
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		page, err := service.Search("иван", 2, 2)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if page.Total != 3 || len(page.Items) != 1 || page.Page != 2 || page.PageSize != 2 {
+			t.Errorf("Expected second page with 1 of 3 matches, got %+v", page)
+		}
+
+		page, _ = service.Search("иван", 5, 2)
+		if len(page.Items) != 0 {
+			t.Errorf("Expected empty page past the end, got %v", ids(page))
+		}
+		page, _ = service.Search("иван", math.MaxInt/2+2, 2)
+		if len(page.Items) != 0 {
+			t.Errorf("Expected empty page for an overflowing offset, got %v", ids(page))
+		}
+	})
+
+	t.Run("Empty query", func(t *testing.T) {
+		_, err := service.Search("  ", 1, 10)
+		if err == nil || err.Error() != "Search query is empty" {
+			t.Errorf("Expected 'Search query is empty' error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"google.golang.org/protobuf/encoding/protowire"
+	"math"
+)
+
+// Field numbers of gtfs-realtime.proto, version 2.0.
+const (
+	feedMessageHeader = 1
+	feedMessageEntity = 2
+
+	feedHeaderVersion   = 1
+	feedHeaderTimestamp = 3
+
+	feedEntityId         = 1
+	feedEntityTripUpdate = 3
+	feedEntityVehicle    = 4
+
+	tripDescriptorTripId  = 1
+	tripDescriptorRouteId = 5
+
+	vehicleDescriptorId    = 1
+	vehicleDescriptorLabel = 2
+
+	vehiclePositionTrip      = 1
+	vehiclePositionPosition  = 2
+	vehiclePositionTimestamp = 5
+	vehiclePositionVehicle   = 8
+
+	positionLatitude  = 1
+	positionLongitude = 2
+	positionBearing   = 3
+	positionSpeed     = 5
+
+	tripUpdateTrip           = 1
+	tripUpdateStopTimeUpdate = 2
+	tripUpdateVehicle        = 3
+	tripUpdateTimestamp      = 4
+
+	stopTimeUpdateStopSequence = 1
+	stopTimeUpdateArrival      = 2
+	stopTimeUpdateStopId       = 4
+
+	stopTimeEventTime = 2
+)
+
+// EncodeVehiclePositions encodes the vehicles of the feed as a GTFS-Realtime
+// FeedMessage.
+func EncodeVehiclePositions(feed *models.GtfsFeed) []byte {
+	b := encodeFeedHeader(feed)
+	for _, vehicle := range feed.Vehicles {
+		var v []byte
+		if vehicle.RouteID != "" {
+			v = appendMessage(v, vehiclePositionTrip, encodeTripDescriptor(vehicle.TripID, vehicle.RouteID))
+		}
+		var p []byte
+		p = appendFloat(p, positionLatitude, vehicle.Position.Lat)
+		p = appendFloat(p, positionLongitude, vehicle.Position.Long)
+		p = appendFloat(p, positionBearing, vehicle.Position.Heading)
+		// GTFS-Realtime speed is in meters per second
+		p = appendFloat(p, positionSpeed, vehicle.Position.Speed/3.6)
+		v = appendMessage(v, vehiclePositionPosition, p)
+		v = protowire.AppendTag(v, vehiclePositionTimestamp, protowire.VarintType)
+		v = protowire.AppendVarint(v, uint64(vehicle.Position.RecordedAt.Unix()))
+		v = appendMessage(v, vehiclePositionVehicle, encodeVehicleDescriptor(vehicle.BusID, vehicle.Label))
+
+		var e []byte
+		e = appendString(e, feedEntityId, vehicle.BusID)
+		e = appendMessage(e, feedEntityVehicle, v)
+		b = appendMessage(b, feedMessageEntity, e)
+	}
+	return b
+}
+
+// EncodeTripUpdates encodes the predicted arrivals of the feed as a
+// GTFS-Realtime FeedMessage.
+func EncodeTripUpdates(feed *models.GtfsFeed) []byte {
+	b := encodeFeedHeader(feed)
+	for _, update := range feed.TripUpdates {
+		var u []byte
+		u = appendMessage(u, tripUpdateTrip, encodeTripDescriptor(update.TripID, update.RouteID))
+		for _, stopTime := range update.StopTimes {
+			var arrival []byte
+			arrival = protowire.AppendTag(arrival, stopTimeEventTime, protowire.VarintType)
+			arrival = protowire.AppendVarint(arrival, uint64(stopTime.Arrival.Unix()))
+
+			var s []byte
+			s = protowire.AppendTag(s, stopTimeUpdateStopSequence, protowire.VarintType)
+			s = protowire.AppendVarint(s, uint64(stopTime.StopSequence))
+			s = appendMessage(s, stopTimeUpdateArrival, arrival)
+			s = appendString(s, stopTimeUpdateStopId, stopTime.StopID)
+			u = appendMessage(u, tripUpdateStopTimeUpdate, s)
+		}
+		u = appendMessage(u, tripUpdateVehicle, encodeVehicleDescriptor(update.BusID, update.Label))
+		u = protowire.AppendTag(u, tripUpdateTimestamp, protowire.VarintType)
+		u = protowire.AppendVarint(u, uint64(update.Timestamp.Unix()))
+
+		var e []byte
+		e = appendString(e, feedEntityId, update.BusID)
+		e = appendMessage(e, feedEntityTripUpdate, u)
+		b = appendMessage(b, feedMessageEntity, e)
+	}
+	return b
+}
+
+func encodeFeedHeader(feed *models.GtfsFeed) []byte {
+	var h []byte
+	h = appendString(h, feedHeaderVersion, "2.0")
+	h = protowire.AppendTag(h, feedHeaderTimestamp, protowire.VarintType)
+	h = protowire.AppendVarint(h, uint64(feed.Timestamp.Unix()))
+	return appendMessage(nil, feedMessageHeader, h)
+}
+
+func encodeTripDescriptor(tripId, routeId string) []byte {
+	var b []byte
+	if tripId != "" {
+		b = appendString(b, tripDescriptorTripId, tripId)
+	}
+	return appendString(b, tripDescriptorRouteId, routeId)
+}
+
+func encodeVehicleDescriptor(id, label string) []byte {
+	b := appendString(nil, vehicleDescriptorId, id)
+	if label != "" {
+		b = appendString(b, vehicleDescriptorLabel, label)
+	}
+	return b
+}
+
+func appendMessage(b []byte, num protowire.Number, message []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, message)
+}
+
+func appendString(b []byte, num protowire.Number, value string) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, value)
+}
+
+func appendFloat(b []byte, num protowire.Number, value float64) []byte {
+	b = protowire.AppendTag(b, num, protowire.Fixed32Type)
+	return protowire.AppendFixed32(b, math.Float32bits(float32(value)))
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/repository"
+	"sort"
+	"time"
+)
+
+// staleAfter drops buses that stopped reporting from the feed.
+const staleAfter = 10 * time.Minute
+
+type GtfsRealtimeService struct {
+	positionRepo repository.IPositionRepository
+	routeRepo    repository.IRouteRepository
+	busRepo      repository.IBusRepository
+	segmentRepo  repository.ISegmentRepository
+	tripRepo     repository.ITripRepository
+}
+
+func NewGtfsRealtimeService(positionRepo repository.IPositionRepository, routeRepo repository.IRouteRepository, busRepo repository.IBusRepository, segmentRepo repository.ISegmentRepository, tripRepo repository.ITripRepository) *GtfsRealtimeService {
+	s := &GtfsRealtimeService{positionRepo, routeRepo, busRepo, segmentRepo, tripRepo}
+	return s
+}
+
+// GetFeed builds the vehicle positions and trip updates from the latest
+// positions. Consumers match trip updates by trip, so only buses on a
+// scheduled trip of their route get one.
+func (s GtfsRealtimeService) GetFeed(now time.Time) (*models.GtfsFeed, error) {
+	positions, err := s.positionRepo.GetAllLatest()
+	if err != nil {
+		return nil, err
+	}
+	buses, err := s.busRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	labels := make(map[string]string)
+	for _, bus := range buses {
+		labels[bus.ID] = bus.RegisterNumber
+	}
+	network, err := loadRouteNetwork(s.routeRepo, s.segmentRepo, now)
+	if err != nil {
+		return nil, err
+	}
+	lastStops, err := loadLastStops(s.segmentRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &models.GtfsFeed{Timestamp: now, Vehicles: []models.GtfsVehicle{}, TripUpdates: []models.GtfsTripUpdate{}}
+	sort.Slice(positions, func(i, j int) bool { return positions[i].BusID < positions[j].BusID })
+	for _, position := range positions {
+		if now.Sub(position.RecordedAt) > staleAfter {
+			continue
+		}
+		routeId := network.busRoutes[position.BusID]
+		var trip *models.Trip
+		if routeId != "" {
+			trips, err := s.tripRepo.GetByBusId(position.BusID, position.RecordedAt.Add(-tripMatchMargin), position.RecordedAt.Add(tripMatchMargin))
+			if err != nil {
+				return nil, err
+			}
+			trip = matchTrip(trips, position.RecordedAt)
+			if trip != nil && trip.RouteID != routeId {
+				trip = nil
+			}
+		}
+		vehicle := models.GtfsVehicle{
+			BusID:    position.BusID,
+			Label:    labels[position.BusID],
+			RouteID:  routeId,
+			Position: position,
+		}
+		if trip != nil {
+			vehicle.TripID = trip.ID
+		}
+		feed.Vehicles = append(feed.Vehicles, vehicle)
+		if trip == nil {
+			continue
+		}
+		routeStops, err := network.Stops(routeId)
+		if err != nil {
+			return nil, err
+		}
+		times, err := network.TravelTimes(routeId)
+		if err != nil {
+			return nil, err
+		}
+		stops, ok := stopsInTripDirection(trip, routeStops)
+		if !ok {
+			stops = stopsInDirection(position, routeStops, lastStops.on(position.BusID, routeId))
+		}
+		arrivals := tripArrivals(trip, predictArrivals(position, stops, times))
+		if len(arrivals) == 0 {
+			continue
+		}
+		feed.TripUpdates = append(feed.TripUpdates, models.GtfsTripUpdate{
+			BusID:     position.BusID,
+			Label:     labels[position.BusID],
+			RouteID:   routeId,
+			TripID:    trip.ID,
+			Timestamp: position.RecordedAt,
+			StopTimes: arrivals,
+		})
+	}
+	return feed, nil
+}
+
+// stopsInTripDirection orders the route stops the way the trip runs them,
+// which is backwards on the way back. It fails when the trip has fewer
+// than two stops on the route to tell.
+func stopsInTripDirection(trip *models.Trip, routeStops []models.BusStop) ([]models.BusStop, bool) {
+	index := make(map[string]int)
+	for i, stop := range routeStops {
+		index[stop.ID] = i
+	}
+	first, last := -1, -1
+	for _, stopTime := range trip.StopTimes {
+		if i, ok := index[stopTime.StopID]; ok {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first == last {
+		return nil, false
+	}
+	if first < last {
+		return routeStops, true
+	}
+	return reversedStops(routeStops), true
+}
+
+// tripArrivals keeps the arrivals at stops of the trip, numbered by the
+// stop sequence of the trip.
+func tripArrivals(trip *models.Trip, arrivals []models.GtfsStopTimeUpdate) []models.GtfsStopTimeUpdate {
+	sequence := make(map[string]int)
+	for _, stopTime := range trip.StopTimes {
+		sequence[stopTime.StopID] = stopTime.StopSequence
+	}
+	var result []models.GtfsStopTimeUpdate
+	for _, arrival := range arrivals {
+		if seq, ok := sequence[arrival.StopID]; ok {
+			arrival.StopSequence = seq
+			result = append(result, arrival)
+		}
+	}
+	return result
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"google.golang.org/protobuf/encoding/protowire"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPredictArrivals(t *testing.T) {
+	recordedAt := time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC)
+	// stops about 1.1 km apart along a meridian
+	stops := []models.BusStop{
+		{ID: "s1", Lat: 55.70, Long: 37.60},
+		{ID: "s2", Lat: 55.71, Long: 37.60},
+		{ID: "s3", Lat: 55.72, Long: 37.60},
+	}
+
+	t.Run("Heading to the nearest stop", func(t *testing.T) {
+		position := models.VehiclePosition{Lat: 55.708, Long: 37.60, Speed: 36, RecordedAt: recordedAt}
+
+		updates := predictArrivals(position, stops, nil)
+		if len(updates) != 2 || updates[0].StopID != "s2" || updates[0].StopSequence != 2 {
+			t.Fatalf("Expected arrivals at s2 and s3, got %v", updates)
+		}
+		// 222 m at 10 m/s
+		if got := updates[0].Arrival.Sub(recordedAt); got < 21*time.Second || got > 23*time.Second {
+			t.Errorf("Expected arrival at s2 in about 22s, got %v", got)
+		}
+		if got := updates[1].Arrival.Sub(updates[0].Arrival); got < 110*time.Second || got > 112*time.Second {
+			t.Errorf("Expected about 111s between s2 and s3, got %v", got)
+		}
+	})
+
+	t.Run("Nearest stop already passed", func(t *testing.T) {
+		position := models.VehiclePosition{Lat: 55.712, Long: 37.60, Speed: 36, RecordedAt: recordedAt}
+
+		updates := predictArrivals(position, stops, nil)
+		if len(updates) != 1 || updates[0].StopID != "s3" {
+			t.Errorf("Expected arrival at s3 only, got %v", updates)
+		}
+	})
+
+	t.Run("Segment history", func(t *testing.T) {
+		position := models.VehiclePosition{Lat: 55.705, Long: 37.60, Speed: 36, RecordedAt: recordedAt}
+		times := newSegmentTimes([]models.SegmentTravelTime{
+			{FromStopID: "s1", ToStopID: "s2", Hour: recordedAt.Local().Hour(), AvgSeconds: 200},
+			{FromStopID: "s2", ToStopID: "s3", Hour: (recordedAt.Local().Hour() + 5) % 24, AvgSeconds: 300},
+			{FromStopID: "s2", ToStopID: "s3", Hour: (recordedAt.Local().Hour() + 6) % 24, AvgSeconds: 500},
+		})
+
+		updates := predictArrivals(position, stops, times)
+		// half of the first segment is left, the second one has no
+		// history for the hour and takes the mean over the other hours
+		if got := updates[0].Arrival.Sub(recordedAt); got < 99*time.Second || got > 101*time.Second {
+			t.Errorf("Expected arrival at s2 in about 100s, got %v", got)
+		}
+		if got := updates[1].Arrival.Sub(updates[0].Arrival); got < 399*time.Second || got > 401*time.Second {
+			t.Errorf("Expected 400s between s2 and s3, got %v", got)
+		}
+	})
+
+	t.Run("Standing bus uses the average speed", func(t *testing.T) {
+		position := models.VehiclePosition{Lat: 55.70, Long: 37.60, Speed: 0, RecordedAt: recordedAt}
+
+		updates := predictArrivals(position, stops, nil)
+		want := time.Duration(distance(55.70, 37.60, 55.71, 37.60) / (defaultPredictionSpeed / 3.6) * float64(time.Second))
+		if got := updates[1].Arrival.Sub(recordedAt); math.Abs(float64(got-want)) > float64(time.Second) {
+			t.Errorf("Expected arrival at s2 in %v, got %v", want, got)
+		}
+	})
+}
+
+func TestStopsInDirection(t *testing.T) {
+	recordedAt := time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC)
+	stops := []models.BusStop{
+		{ID: "s1", Lat: 55.70, Long: 37.60},
+		{ID: "s2", Lat: 55.71, Long: 37.60},
+		{ID: "s3", Lat: 55.72, Long: 37.60},
+	}
+	passed := func(stopId string, ago time.Duration) *models.StopPassage {
+		return &models.StopPassage{StopID: stopId, PassedAt: recordedAt.Add(-ago)}
+	}
+	tests := []struct {
+		name      string
+		lat       float64
+		heading   float64
+		last      *models.StopPassage
+		wantFirst string
+	}{
+		{name: "Heading along the route", lat: 55.705, heading: 0, wantFirst: "s1"},
+		{name: "Heading back", lat: 55.705, heading: 180, wantFirst: "s3"},
+		{name: "Heading back at the terminal", lat: 55.72, heading: 175, wantFirst: "s3"},
+		{name: "Passed an earlier stop", lat: 55.709, heading: 180, last: passed("s1", time.Minute), wantFirst: "s1"},
+		{name: "Passed a later stop", lat: 55.711, heading: 0, last: passed("s3", time.Minute), wantFirst: "s3"},
+		{name: "Still at the passed stop", lat: 55.71, heading: 180, last: passed("s2", time.Minute), wantFirst: "s3"},
+		{name: "Old passage is ignored", lat: 55.711, heading: 0, last: passed("s3", time.Hour), wantFirst: "s1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			position := models.VehiclePosition{Lat: tt.lat, Long: 37.60, Heading: tt.heading, RecordedAt: recordedAt}
+
+			ordered := stopsInDirection(position, stops, tt.last)
+			if len(ordered) != len(stops) || ordered[0].ID != tt.wantFirst {
+				t.Errorf("Expected the stops to start at %s, got %v", tt.wantFirst, ordered)
+			}
+		})
+	}
+
+	t.Run("Predictions on the way back", func(t *testing.T) {
+		position := models.VehiclePosition{Lat: 55.712, Long: 37.60, Speed: 36, Heading: 180, RecordedAt: recordedAt}
+
+		updates := predictArrivals(position, stopsInDirection(position, stops, nil), nil)
+		if len(updates) != 2 || updates[0].StopID != "s2" || updates[1].StopID != "s1" {
+			t.Errorf("Expected arrivals at s2 and s1, got %v", updates)
+		}
+	})
+}
+
+func TestGtfsRealtimeService_GetFeed(t *testing.T) {
+	now := time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC)
+	route := models.Route{ID: "route-1", Number: "42"}
+	positions := []models.VehiclePosition{
+		{BusID: "bus-1", RecordedAt: now.Add(-time.Minute), Lat: 55.705, Long: 37.60, Speed: 30, Heading: 0},
+		{BusID: "bus-2", RecordedAt: now.Add(-time.Hour), Lat: 55.705, Long: 37.60},
+		{BusID: "bus-3", RecordedAt: now, Lat: 59.93, Long: 30.31},
+	}
+	routeRepo := &MockRouteRepository{
+		getAllResp:             []models.Route{route},
+		getAllBusesByIdResp:    []models.Bus{{ID: "bus-1"}, {ID: "bus-2"}},
+		getAllBusStopsByIdResp: []models.BusStop{{ID: "s1", Lat: 55.70, Long: 37.60}, {ID: "s2", Lat: 55.71, Long: 37.60}},
+	}
+	busRepo := &MockBusRepository{getAllResp: []models.Bus{{ID: "bus-1", RegisterNumber: "А123ВС77"}}}
+	trips := &MockTripRepository{getByBusIdResp: []models.Trip{{
+		ID:       "trip-1",
+		RouteID:  route.ID,
+		StartsAt: now.Add(-10 * time.Minute),
+		EndsAt:   now.Add(20 * time.Minute),
+		StopTimes: []models.TripStopTime{
+			{StopSequence: 1, StopID: "s1", Arrival: now.Add(-10 * time.Minute)},
+			{StopSequence: 2, StopID: "s2", Arrival: now.Add(20 * time.Minute)},
+		},
+	}}}
+
+	t.Run("On a trip", func(t *testing.T) {
+		service := NewGtfsRealtimeService(&MockPositionRepository{getAllLatestResp: positions}, routeRepo, busRepo, &MockSegmentRepository{}, trips)
+
+		feed, err := service.GetFeed(now)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(feed.Vehicles) != 2 || feed.Vehicles[0].BusID != "bus-1" || feed.Vehicles[1].BusID != "bus-3" {
+			t.Fatalf("Expected fresh vehicles bus-1 and bus-3, got %v", feed.Vehicles)
+		}
+		if feed.Vehicles[0].RouteID != route.ID || feed.Vehicles[0].TripID != "trip-1" || feed.Vehicles[0].Label != "А123ВС77" || feed.Vehicles[1].RouteID != "" {
+			t.Errorf("Expected bus-1 on trip-1 with its register number, got %v", feed.Vehicles)
+		}
+		if len(feed.TripUpdates) != 1 || feed.TripUpdates[0].TripID != "trip-1" || feed.TripUpdates[0].StopTimes[0].StopID != "s2" {
+			t.Errorf("Expected a trip update for trip-1 towards s2, got %v", feed.TripUpdates)
+		}
+	})
+
+	t.Run("On a return trip", func(t *testing.T) {
+		back := &MockTripRepository{getByBusIdResp: []models.Trip{{
+			ID:       "trip-2",
+			RouteID:  route.ID,
+			StartsAt: now.Add(-10 * time.Minute),
+			EndsAt:   now.Add(20 * time.Minute),
+			StopTimes: []models.TripStopTime{
+				{StopSequence: 1, StopID: "s2", Arrival: now.Add(-10 * time.Minute)},
+				{StopSequence: 2, StopID: "s1", Arrival: now.Add(20 * time.Minute)},
+			},
+		}}}
+		service := NewGtfsRealtimeService(&MockPositionRepository{getAllLatestResp: positions}, routeRepo, busRepo, &MockSegmentRepository{}, back)
+
+		feed, err := service.GetFeed(now)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(feed.TripUpdates) != 1 || len(feed.TripUpdates[0].StopTimes) != 1 {
+			t.Fatalf("Expected a trip update with one stop, got %v", feed.TripUpdates)
+		}
+		if stopTime := feed.TripUpdates[0].StopTimes[0]; stopTime.StopID != "s1" || stopTime.StopSequence != 2 {
+			t.Errorf("Expected the arrival at s1 as the second stop of the trip, got %v", stopTime)
+		}
+	})
+
+	t.Run("Without a trip", func(t *testing.T) {
+		service := NewGtfsRealtimeService(&MockPositionRepository{getAllLatestResp: positions}, routeRepo, busRepo, &MockSegmentRepository{}, &MockTripRepository{})
+
+		feed, err := service.GetFeed(now)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(feed.Vehicles) != 2 || feed.Vehicles[0].TripID != "" {
+			t.Errorf("Expected the vehicles without a trip, got %v", feed.Vehicles)
+		}
+		if len(feed.TripUpdates) != 0 {
+			t.Errorf("Expected no trip updates, got %v", feed.TripUpdates)
+		}
+	})
+}
+
+// readFields splits an encoded message into its fields by number.
+func readFields(t *testing.T, b []byte) map[protowire.Number][][]byte {
+	fields := make(map[protowire.Number][][]byte)
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatalf("Invalid tag: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			t.Fatalf("Invalid field %d: %v", num, protowire.ParseError(n))
+		}
+		value := b[:n]
+		if typ == protowire.BytesType {
+			value, _ = protowire.ConsumeBytes(value)
+		}
+		fields[num] = append(fields[num], value)
+		b = b[n:]
+	}
+	return fields
+}
+
+func TestEncodeGtfsFeed(t *testing.T) {
+	recordedAt := time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC)
+	feed := &models.GtfsFeed{
+		Timestamp: recordedAt,
+		Vehicles: []models.GtfsVehicle{{
+			BusID:    "bus-1",
+			Label:    "А123ВС77",
+			RouteID:  "route-1",
+			Position: models.VehiclePosition{BusID: "bus-1", RecordedAt: recordedAt, Lat: 55.75, Long: 37.61, Speed: 36, Heading: 90},
+		}},
+		TripUpdates: []models.GtfsTripUpdate{{
+			BusID:     "bus-1",
+			RouteID:   "route-1",
+			TripID:    "trip-1",
+			Timestamp: recordedAt,
+			StopTimes: []models.GtfsStopTimeUpdate{{StopID: "s2", StopSequence: 2, Arrival: recordedAt.Add(time.Minute)}},
+		}},
+	}
+
+	t.Run("Vehicle positions", func(t *testing.T) {
+		message := readFields(t, EncodeVehiclePositions(feed))
+		header := readFields(t, message[feedMessageHeader][0])
+		if string(header[feedHeaderVersion][0]) != "2.0" {
+			t.Errorf("Expected version 2.0, got %q", header[feedHeaderVersion][0])
+		}
+		entity := readFields(t, message[feedMessageEntity][0])
+		if string(entity[feedEntityId][0]) != "bus-1" {
+			t.Errorf("Expected entity id bus-1, got %q", entity[feedEntityId][0])
+		}
+		vehicle := readFields(t, entity[feedEntityVehicle][0])
+		trip := readFields(t, vehicle[vehiclePositionTrip][0])
+		if string(trip[tripDescriptorRouteId][0]) != "route-1" || len(trip[tripDescriptorTripId]) != 0 {
+			t.Errorf("Expected route id route-1 without a trip id, got %v", trip)
+		}
+		position := readFields(t, vehicle[vehiclePositionPosition][0])
+		lat, _ := protowire.ConsumeFixed32(position[positionLatitude][0])
+		speed, _ := protowire.ConsumeFixed32(position[positionSpeed][0])
+		if math.Float32frombits(lat) != float32(55.75) || math.Float32frombits(speed) != 10 {
+			t.Errorf("Expected latitude 55.75 and speed 10 m/s, got %v and %v", math.Float32frombits(lat), math.Float32frombits(speed))
+		}
+	})
+
+	t.Run("Trip updates", func(t *testing.T) {
+		message := readFields(t, EncodeTripUpdates(feed))
+		entity := readFields(t, message[feedMessageEntity][0])
+		update := readFields(t, entity[feedEntityTripUpdate][0])
+		trip := readFields(t, update[tripUpdateTrip][0])
+		if string(trip[tripDescriptorTripId][0]) != "trip-1" || string(trip[tripDescriptorRouteId][0]) != "route-1" {
+			t.Errorf("Expected trip-1 of route-1, got %v", trip)
+		}
+		stopTime := readFields(t, update[tripUpdateStopTimeUpdate][0])
+		if string(stopTime[stopTimeUpdateStopId][0]) != "s2" {
+			t.Errorf("Expected stop id s2, got %q", stopTime[stopTimeUpdateStopId][0])
+		}
+		arrival := readFields(t, stopTime[stopTimeUpdateArrival][0])
+		at, _ := protowire.ConsumeVarint(arrival[stopTimeEventTime][0])
+		if int64(at) != recordedAt.Add(time.Minute).Unix() {
+			t.Errorf("Expected arrival %v, got %v", recordedAt.Add(time.Minute).Unix(), at)
+		}
+	})
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type IAbsenceService interface {
+	GetById(id string) (*models.DriverAbsence, error)
+	Add(absence *models.DriverAbsence) error
+	DeleteById(id string) error
+	GetByDriverId(driverId string) ([]models.DriverAbsence, error)
+	GetAvailable(date time.Time) ([]models.Driver, error)
+	CheckAvailable(driverId string, from, to time.Time) error
+}
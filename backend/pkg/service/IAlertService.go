@@ -0,0 +1,15 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type IAlertService interface {
+	GetById(id string) (*models.ServiceAlert, error)
+	Publish(alert *models.ServiceAlert) error
+	End(id string) (*models.ServiceAlert, error)
+	GetActive(routeId, stopId string, at time.Time) ([]models.ServiceAlert, error)
+	AttachToRoutes(routes []models.Route, at time.Time) error
+	AttachToStops(stops []models.BusStop, at time.Time) error
+}
@@ -0,0 +1,11 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type IArrivalService interface {
+	Observe(positions []models.VehiclePosition) error
+	GetStopArrivals(stopId string, now time.Time) ([]models.RouteArrivals, error)
+}
@@ -0,0 +1,11 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type IComplianceService interface {
+	CheckDuty(duty models.Duty) ([]models.ComplianceViolation, error)
+	GetDriverReport(driverId string, from, to time.Time) (*models.ComplianceReport, error)
+}
@@ -0,0 +1,10 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type IDepartureService interface {
+	GetBoard(stopId string, now time.Time) (*models.DepartureBoard, error)
+}
@@ -0,0 +1,9 @@
+package service
+
+import "backend/pkg/models"
+
+type IDispatchService interface {
+	ProposeReplacements(busId string) (*models.ReplacementProposal, error)
+	ReportBreakdown(breakdown *models.Breakdown) (*models.BreakdownResult, error)
+	FinishRepair(busId string) (*models.BusRepair, error)
+}
@@ -9,4 +9,5 @@ type IDriverService interface {
 	DeleteById(id string) error
 	GetAll() []models.Driver
 	UpdateById(driver *models.Driver) error
+	Search(query string, page, pageSize int) (*models.DriverSearchPage, error)
 }
@@ -0,0 +1,10 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type IGtfsRealtimeService interface {
+	GetFeed(now time.Time) (*models.GtfsFeed, error)
+}
@@ -0,0 +1,10 @@
+package service
+
+import "backend/pkg/models"
+
+type IIncidentService interface {
+	GetById(id string) (*models.Incident, error)
+	Add(incident *models.Incident) error
+	ChangeStatus(id string, change models.IncidentStatusChange) (*models.Incident, error)
+	Find(filter models.IncidentFilter) ([]models.Incident, error)
+}
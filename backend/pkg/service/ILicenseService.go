@@ -0,0 +1,13 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type ILicenseService interface {
+	GetByDriverId(driverId string) (*models.DriverLicense, error)
+	Save(license *models.DriverLicense) error
+	GetExpiring(days int) ([]models.ExpiringLicense, error)
+	CheckDriverForVehicleClass(driverId, vehicleClass string, at time.Time) error
+}
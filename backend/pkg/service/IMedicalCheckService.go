@@ -0,0 +1,14 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type IMedicalCheckService interface {
+	GetById(id string) (*models.MedicalCheck, error)
+	Add(check *models.MedicalCheck) error
+	GetByDriverId(driverId string, from, to time.Time) ([]models.MedicalCheck, error)
+	CheckPassed(driverId string, at time.Time) error
+	GetPending(date time.Time) ([]models.PendingMedicalCheck, error)
+}
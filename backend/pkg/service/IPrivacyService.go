@@ -0,0 +1,8 @@
+package service
+
+import "backend/pkg/models"
+
+type IPrivacyService interface {
+	ProtectDriver(driver *models.Driver, userId string, roles []string, action string) error
+	ProtectDrivers(drivers []models.Driver, userId string, roles []string, action string) error
+}
@@ -0,0 +1,11 @@
+package service
+
+import "backend/pkg/models"
+
+type IQualificationService interface {
+	Save(qualification *models.DriverQualification) error
+	Delete(driverId, brand, busModel string) error
+	GetByDriverId(driverId string) ([]models.DriverQualification, error)
+	QualifiedDrivers(bus models.Bus) (map[string]bool, error)
+	GetMatrix() (*models.QualificationMatrix, error)
+}
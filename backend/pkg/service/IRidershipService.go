@@ -0,0 +1,11 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type IRidershipService interface {
+	Ingest(counts []models.PassengerCount) (*models.CountBatchResult, error)
+	GetReport(from, to time.Time, groupBy, granularity, routeId string) ([]models.RidershipRow, error)
+}
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type IRosterService interface {
+	GetById(id string) (*models.Duty, error)
+	Add(duty *models.Duty) (*models.DutyResult, error)
+	DeleteById(id string) error
+	UpdateById(duty *models.Duty) (*models.DutyResult, error)
+	StartDuty(id string, at time.Time) (*models.Duty, error)
+	GetDay(date time.Time) (*models.RosterDay, error)
+	GetWeek(date time.Time) ([]models.RosterDay, error)
+}
@@ -1,6 +1,9 @@
 package service
 
-import "backend/pkg/models"
+import (
+	"backend/pkg/models"
+	"time"
+)
 
 type IRouteService interface {
 	GetById(id string) (*models.Route, error)
@@ -9,14 +12,16 @@ type IRouteService interface {
 	DeleteById(id string) error
 	GetAll() ([]models.Route, error)
 	UpdateById(route *models.Route) error
-	AssignDriver(routeId, driverId string) error
+	AssignDriver(routeId, driverId string, from, to *time.Time) error
 	AssignBusStop(routeId, busStopId string) error
 	AssignBus(routeId, busId string) error
 	UnassignDriver(routeId, driverId string) error
 	UnassignBusStop(routeId, busStopId string) error
-	UnassignBus(routeId, busId string) error
+	UnassignBus(routeId, busId string, startsAt *time.Time) error
 	GetAllDriversById(routeId string) ([]models.Driver, error)
 	GetAllBusStopsById(routeId string) ([]models.BusStop, error)
 	GetAllBusesById(routeId string) ([]models.Bus, error)
+	AssignBusForPeriod(assignment *models.BusAssignment, force bool) (*models.BusAssignmentResult, error)
+	GetBusConflicts() ([]models.BusConflict, error)
 	// TODO: getall for all models, unassign
 }
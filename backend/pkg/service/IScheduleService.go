@@ -0,0 +1,15 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"time"
+)
+
+type IScheduleService interface {
+	GetTrip(id string) (*models.Trip, error)
+	AddTrip(trip *models.Trip) error
+	DeleteTrip(id string) error
+	GetTripsByDutyId(dutyId string) ([]models.Trip, error)
+	Observe(positions []models.VehiclePosition) error
+	GetPerformance(from, to time.Time, groupBy string) ([]models.PerformanceRow, error)
+}
@@ -0,0 +1,9 @@
+package service
+
+import "backend/pkg/models"
+
+type ITelemetryService interface {
+	Ingest(positions []models.VehiclePosition) (*models.PositionBatchResult, error)
+	GetLatest(busId string) (*models.VehiclePosition, error)
+	Subscribe(routeId string, box *models.BoundingBox) ([]models.VehiclePosition, <-chan []models.VehiclePosition, func(), error)
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/repository"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var incidentTypes = map[string]bool{
+	models.IncidentBreakdown: true,
+	models.IncidentAccident:  true,
+	models.IncidentComplaint: true,
+	models.IncidentDelay:     true,
+}
+
+var incidentSeverities = map[string]bool{
+	models.SeverityLow:      true,
+	models.SeverityMedium:   true,
+	models.SeverityHigh:     true,
+	models.SeverityCritical: true,
+}
+
+// incidentTransitions lists the statuses an incident may move to. A closed
+// incident stays closed.
+var incidentTransitions = map[string][]string{
+	models.IncidentOpen:          {models.IncidentInvestigating, models.IncidentClosed},
+	models.IncidentInvestigating: {models.IncidentClosed},
+}
+
+type IncidentService struct {
+	repo       repository.IIncidentRepository
+	busRepo    repository.IBusRepository
+	driverRepo repository.IDriverRepository
+	routeRepo  repository.IRouteRepository
+}
+
+func NewIncidentService(r repository.IIncidentRepository, busRepo repository.IBusRepository, driverRepo repository.IDriverRepository, routeRepo repository.IRouteRepository) *IncidentService {
+	s := &IncidentService{r, busRepo, driverRepo, routeRepo}
+	return s
+}
+
+func (s IncidentService) GetById(id string) (*models.Incident, error) {
+	incident, err := s.repo.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if incident == nil {
+		return nil, errors.New("Incident not found")
+	}
+	return incident, nil
+}
+
+// Add registers a new open incident. It has to be linked to at least one
+// bus, driver or route.
+func (s IncidentService) Add(incident *models.Incident) error {
+	incident.Type = strings.ToLower(strings.TrimSpace(incident.Type))
+	if !incidentTypes[incident.Type] {
+		return fmt.Errorf("Unknown incident type %s", incident.Type)
+	}
+	incident.Severity = strings.ToLower(strings.TrimSpace(incident.Severity))
+	if !incidentSeverities[incident.Severity] {
+		return fmt.Errorf("Unknown incident severity %s", incident.Severity)
+	}
+	if incident.OccurredAt.IsZero() {
+		return errors.New("Incident time is required")
+	}
+	if incident.OccurredAt.After(time.Now()) {
+		return errors.New("Incident time can't be in the future")
+	}
+	if (incident.Lat == nil) != (incident.Long == nil) {
+		return errors.New("Both latitude and longitude are required")
+	}
+	if incident.Lat != nil && (*incident.Lat < -90 || *incident.Lat > 90 || *incident.Long < -180 || *incident.Long > 180) {
+		return errors.New("Coordinates are out of range")
+	}
+	if incident.BusID == "" && incident.DriverID == "" && incident.RouteID == "" {
+		return errors.New("Incident must be linked to a bus, driver or route")
+	}
+	if incident.BusID != "" {
+		bus, _ := s.busRepo.GetById(incident.BusID)
+		if bus == nil {
+			return errors.New("Bus not found")
+		}
+	}
+	if incident.DriverID != "" {
+		driver, _ := s.driverRepo.GetById(incident.DriverID)
+		if driver == nil {
+			return errors.New("Driver not found")
+		}
+	}
+	if incident.RouteID != "" {
+		route, _ := s.routeRepo.GetById(incident.RouteID)
+		if route == nil {
+			return errors.New("Route not found")
+		}
+	}
+	incident.Status = models.IncidentOpen
+	incident.Resolution = ""
+	incident.ClosedAt = nil
+	return s.repo.Add(incident)
+}
+
+// ChangeStatus moves the incident along open, investigating, closed.
+// Closing needs a resolution. A change made by someone else in the meantime
+// makes it fail instead of being overwritten.
+func (s IncidentService) ChangeStatus(id string, change models.IncidentStatusChange) (*models.Incident, error) {
+	incident, err := s.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	status := strings.ToLower(strings.TrimSpace(change.Status))
+	allowed := false
+	for _, next := range incidentTransitions[incident.Status] {
+		if next == status {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf("Incident can't go from %s to %s", incident.Status, status)
+	}
+	resolution := strings.TrimSpace(change.Resolution)
+	var closedAt *time.Time
+	if status == models.IncidentClosed {
+		if resolution == "" {
+			return nil, errors.New("Resolution is required to close an incident")
+		}
+		now := time.Now()
+		closedAt = &now
+	}
+	err = s.repo.SetStatus(id, incident.Status, status, resolution, closedAt)
+	if err != nil {
+		return nil, err
+	}
+	incident.Status = status
+	incident.Resolution = resolution
+	incident.ClosedAt = closedAt
+	return incident, nil
+}
+
+func (s IncidentService) Find(filter models.IncidentFilter) ([]models.Incident, error) {
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.To.After(filter.From) {
+		return nil, errors.New("Period end must be after period start")
+	}
+	if filter.Type != "" && !incidentTypes[filter.Type] {
+		return nil, fmt.Errorf("Unknown incident type %s", filter.Type)
+	}
+	if filter.Status != "" && incidentTransitions[filter.Status] == nil && filter.Status != models.IncidentClosed {
+		return nil, fmt.Errorf("Unknown incident status %s", filter.Status)
+	}
+	return s.repo.Find(filter)
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+type MockIncidentRepository struct {
+	getByIdResp  *models.Incident
+	getByIdErr   error
+	addErr       error
+	setStatusErr error
+	expected     string
+	findResp     []models.Incident
+	findErr      error
+	filter       models.IncidentFilter
+}
+
+func (m *MockIncidentRepository) GetById(id string) (*models.Incident, error) {
+	return m.getByIdResp, m.getByIdErr
+}
+
+func (m *MockIncidentRepository) Add(incident *models.Incident) error {
+	return m.addErr
+}
+
+func (m *MockIncidentRepository) SetStatus(id, expected, status, resolution string, closedAt *time.Time) error {
+	m.expected = expected
+	return m.setStatusErr
+}
+
+func (m *MockIncidentRepository) Find(filter models.IncidentFilter) ([]models.Incident, error) {
+	m.filter = filter
+	return m.findResp, m.findErr
+}
+
+func TestIncidentService_Add(t *testing.T) {
+	bus := &models.Bus{ID: "bus-1"}
+	newIncident := func() *models.Incident {
+		return &models.Incident{
+			Type:       " Breakdown ",
+			Severity:   "HIGH",
+			OccurredAt: time.Now().Add(-time.Hour),
+			Location:   "Ленинский проспект, 12",
+			BusID:      bus.ID,
+			Status:     models.IncidentClosed,
+		}
+	}
+	newService := func() *IncidentService {
+		return NewIncidentService(&MockIncidentRepository{}, &MockBusRepository{getByIdResp: bus}, &MockDriverRepository{getByIdErr: errors.New("Driver not found")}, &MockRouteRepository{})
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		incident := newIncident()
+
+		err := newService().Add(incident)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if incident.Type != models.IncidentBreakdown || incident.Severity != models.SeverityHigh || incident.Status != models.IncidentOpen {
+			t.Errorf("Expected normalized open breakdown, got %v", incident)
+		}
+	})
+
+	t.Run("Unknown type", func(t *testing.T) {
+		incident := newIncident()
+		incident.Type = "fire"
+
+		err := newService().Add(incident)
+		if err == nil || err.Error() != "Unknown incident type fire" {
+			t.Errorf("Expected 'Unknown incident type fire' error, got %v", err)
+		}
+	})
+
+	t.Run("Not linked", func(t *testing.T) {
+		incident := newIncident()
+		incident.BusID = ""
+
+		err := newService().Add(incident)
+		if err == nil || err.Error() != "Incident must be linked to a bus, driver or route" {
+			t.Errorf("Expected 'Incident must be linked to a bus, driver or route' error, got %v", err)
+		}
+	})
+
+	t.Run("Unknown driver", func(t *testing.T) {
+		incident := newIncident()
+		incident.DriverID = "missing"
+
+		err := newService().Add(incident)
+		if err == nil || err.Error() != "Driver not found" {
+			t.Errorf("Expected 'Driver not found' error, got %v", err)
+		}
+	})
+
+	t.Run("Future time", func(t *testing.T) {
+		incident := newIncident()
+		incident.OccurredAt = time.Now().Add(time.Hour)
+
+		err := newService().Add(incident)
+		if err == nil || err.Error() != "Incident time can't be in the future" {
+			t.Errorf("Expected 'Incident time can't be in the future' error, got %v", err)
+		}
+	})
+
+	t.Run("Half of the coordinates", func(t *testing.T) {
+		incident := newIncident()
+		lat := 55.75
+		incident.Lat = &lat
+
+		err := newService().Add(incident)
+		if err == nil || err.Error() != "Both latitude and longitude are required" {
+			t.Errorf("Expected 'Both latitude and longitude are required' error, got %v", err)
+		}
+	})
+}
+
+func TestIncidentService_ChangeStatus(t *testing.T) {
+	incident := func(status string) *models.Incident {
+		return &models.Incident{ID: "incident-1", Type: models.IncidentDelay, Status: status}
+	}
+
+	t.Run("Investigate", func(t *testing.T) {
+		repo := &MockIncidentRepository{getByIdResp: incident(models.IncidentOpen)}
+		service := NewIncidentService(repo, &MockBusRepository{}, &MockDriverRepository{}, &MockRouteRepository{})
+
+		updated, err := service.ChangeStatus("incident-1", models.IncidentStatusChange{Status: "investigating"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if updated.Status != models.IncidentInvestigating || updated.ClosedAt != nil {
+			t.Errorf("Expected investigating incident, got %v", updated)
+		}
+		if repo.expected != models.IncidentOpen {
+			t.Errorf("Expected the update to require status open, got %q", repo.expected)
+		}
+	})
+
+	t.Run("Changed meanwhile", func(t *testing.T) {
+		repo := &MockIncidentRepository{getByIdResp: incident(models.IncidentInvestigating), setStatusErr: errors.New("Incident is no longer investigating, reload it and try again")}
+		service := NewIncidentService(repo, &MockBusRepository{}, &MockDriverRepository{}, &MockRouteRepository{})
+
+		_, err := service.ChangeStatus("incident-1", models.IncidentStatusChange{Status: "closed", Resolution: "Водитель проинструктирован"})
+		if err == nil || err.Error() != "Incident is no longer investigating, reload it and try again" {
+			t.Errorf("Expected the status conflict, got %v", err)
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		service := NewIncidentService(&MockIncidentRepository{getByIdResp: incident(models.IncidentInvestigating)}, &MockBusRepository{}, &MockDriverRepository{}, &MockRouteRepository{})
+
+		updated, err := service.ChangeStatus("incident-1", models.IncidentStatusChange{Status: "closed", Resolution: "Водитель проинструктирован"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if updated.Status != models.IncidentClosed || updated.ClosedAt == nil {
+			t.Errorf("Expected closed incident with close time, got %v", updated)
+		}
+	})
+
+	t.Run("Close without resolution", func(t *testing.T) {
+		service := NewIncidentService(&MockIncidentRepository{getByIdResp: incident(models.IncidentOpen)}, &MockBusRepository{}, &MockDriverRepository{}, &MockRouteRepository{})
+
+		_, err := service.ChangeStatus("incident-1", models.IncidentStatusChange{Status: "closed"})
+		if err == nil || err.Error() != "Resolution is required to close an incident" {
+			t.Errorf("Expected 'Resolution is required to close an incident' error, got %v", err)
+		}
+	})
+
+	t.Run("Reopen", func(t *testing.T) {
+		service := NewIncidentService(&MockIncidentRepository{getByIdResp: incident(models.IncidentClosed)}, &MockBusRepository{}, &MockDriverRepository{}, &MockRouteRepository{})
+
+		_, err := service.ChangeStatus("incident-1", models.IncidentStatusChange{Status: "open"})
+		if err == nil || err.Error() != "Incident can't go from closed to open" {
+			t.Errorf("Expected 'Incident can't go from closed to open' error, got %v", err)
+		}
+	})
+}
+
+func TestIncidentService_Find(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		repo := &MockIncidentRepository{findResp: []models.Incident{{ID: "incident-1"}}}
+		service := NewIncidentService(repo, &MockBusRepository{}, &MockDriverRepository{}, &MockRouteRepository{})
+
+		incidents, err := service.Find(models.IncidentFilter{From: from, To: from.AddDate(0, 1, 0), RouteID: "route-1", Status: models.IncidentOpen})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(incidents) != 1 || repo.filter.RouteID != "route-1" {
+			t.Errorf("Expected filtered incidents, got %v", incidents)
+		}
+	})
+
+	t.Run("Invalid period", func(t *testing.T) {
+		service := NewIncidentService(&MockIncidentRepository{}, &MockBusRepository{}, &MockDriverRepository{}, &MockRouteRepository{})
+
+		_, err := service.Find(models.IncidentFilter{From: from, To: from})
+		if err == nil || err.Error() != "Period end must be after period start" {
+			t.Errorf("Expected 'Period end must be after period start' error, got %v", err)
+		}
+	})
+
+	t.Run("Unknown status", func(t *testing.T) {
+		service := NewIncidentService(&MockIncidentRepository{}, &MockBusRepository{}, &MockDriverRepository{}, &MockRouteRepository{})
+
+		_, err := service.Find(models.IncidentFilter{Status: "done"})
+		if err == nil || err.Error() != "Unknown incident status done" {
+			t.Errorf("Expected 'Unknown incident status done' error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/repository"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+var licenseCategories = map[string]bool{
+	"A": true, "A1": true, "B": true, "B1": true, "BE": true,
+	"C": true, "C1": true, "CE": true, "C1E": true,
+	"D": true, "D1": true, "DE": true, "D1E": true,
+	"M": true, "TM": true, "TB": true,
+}
+
+// busVehicleClasses are the licence categories a bus can require.
+var busVehicleClasses = map[string]bool{"D": true, "D1": true, "DE": true, "D1E": true}
+
+// categoryCovers lists the subcategories a licence category also permits.
+var categoryCovers = map[string][]string{
+	"D":  {"D1"},
+	"DE": {"D1E"},
+}
+
+type LicenseService struct {
+	repo       repository.IDriverLicenseRepository
+	driverRepo repository.IDriverRepository
+}
+
+func NewLicenseService(r repository.IDriverLicenseRepository, driverRepo repository.IDriverRepository) *LicenseService {
+	s := &LicenseService{r, driverRepo}
+	return s
+}
+
+func (s LicenseService) GetByDriverId(driverId string) (*models.DriverLicense, error) {
+	license, err := s.repo.GetByDriverId(driverId)
+	if err != nil {
+		return nil, err
+	}
+	if license == nil {
+		return nil, errors.New("License not found")
+	}
+	return license, nil
+}
+
+func (s LicenseService) Save(license *models.DriverLicense) error {
+	driver, err := s.driverRepo.GetById(license.DriverID)
+	if driver == nil {
+		return errors.New("Driver not found")
+	}
+	if err != nil {
+		return err
+	}
+	if !license.ExpiryDate.After(license.IssueDate) {
+		return errors.New("License expiry date must be after its issue date")
+	}
+	seen := map[string]bool{}
+	for i := range license.Categories {
+		category := &license.Categories[i]
+		category.Category = strings.ToUpper(strings.TrimSpace(category.Category))
+		category.Restrictions = strings.TrimSpace(category.Restrictions)
+		if !licenseCategories[category.Category] {
+			return fmt.Errorf("Unknown license category %q", category.Category)
+		}
+		if seen[category.Category] {
+			return fmt.Errorf("License category %s is listed twice", category.Category)
+		}
+		seen[category.Category] = true
+		if !category.ExpiryDate.After(category.IssueDate) {
+			return fmt.Errorf("Expiry date of category %s must be after its issue date", category.Category)
+		}
+	}
+	return s.repo.Save(license)
+}
+
+// GetExpiring lists licences and categories that expire within the given
+// number of days, including ones that have already expired.
+func (s LicenseService) GetExpiring(days int) ([]models.ExpiringLicense, error) {
+	if days < 0 {
+		return nil, errors.New("Days must not be negative")
+	}
+	now := time.Now()
+	before := now.AddDate(0, 0, days)
+	licenses, err := s.repo.GetExpiring(before)
+	if err != nil {
+		return nil, err
+	}
+	var report []models.ExpiringLicense
+	for _, license := range licenses {
+		driver, err := s.driverRepo.GetById(license.DriverID)
+		if err != nil && err.Error() != "Driver not found" {
+			return nil, err
+		}
+		if driver == nil {
+			// Licences of drivers deleted before they were removed together
+			// with the driver are left out of the report.
+			continue
+		}
+		entry := models.ExpiringLicense{
+			DriverID:   driver.ID,
+			Name:       driver.Name,
+			Surname:    driver.Surname,
+			Patronymic: driver.Patronymic,
+		}
+		if license.ExpiryDate.Before(before) {
+			entry.ExpiryDate = license.ExpiryDate
+			entry.DaysLeft = daysUntil(now, license.ExpiryDate)
+			report = append(report, entry)
+		}
+		for _, category := range license.Categories {
+			if category.ExpiryDate.Before(before) {
+				entry.Category = category.Category
+				entry.ExpiryDate = category.ExpiryDate
+				entry.DaysLeft = daysUntil(now, category.ExpiryDate)
+				report = append(report, entry)
+			}
+		}
+	}
+	sort.SliceStable(report, func(i, j int) bool { return report[i].ExpiryDate.Before(report[j].ExpiryDate) })
+	return report, nil
+}
+
+// CheckDriverForVehicleClass returns an error if the driver has no licence
+// on record, the licence is expired at the given time, or it does not hold
+// a valid category for the vehicle class. An empty vehicle class only checks
+// the licence itself.
+func (s LicenseService) CheckDriverForVehicleClass(driverId, vehicleClass string, at time.Time) error {
+	license, err := s.repo.GetByDriverId(driverId)
+	if err != nil && err.Error() == "License not found" {
+		return errors.New("Driver has no license on record")
+	}
+	if err != nil {
+		return err
+	}
+	if license == nil {
+		return errors.New("Driver has no license on record")
+	}
+	if !at.Before(license.ExpiryDate) {
+		return fmt.Errorf("Driver license expired on %s", license.ExpiryDate.Format(time.DateOnly))
+	}
+	if vehicleClass == "" {
+		return nil
+	}
+	for _, category := range license.Categories {
+		if !categoryPermits(category.Category, vehicleClass) {
+			continue
+		}
+		if at.Before(category.ExpiryDate) {
+			return nil
+		}
+	}
+	return fmt.Errorf("Driver has no valid license category for vehicle class %s", vehicleClass)
+}
+
+func categoryPermits(held, required string) bool {
+	if held == required {
+		return true
+	}
+	for _, covered := range categoryCovers[held] {
+		if covered == required {
+			return true
+		}
+	}
+	return false
+}
+
+func daysUntil(now, t time.Time) int {
+	return int(startOfDay(t).Sub(startOfDay(now)).Hours() / 24)
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"errors"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+type MockDriverLicenseRepository struct {
+	getByDriverIdResp *models.DriverLicense
+	getByDriverIdErr  error
+	saveErr           error
+	getExpiringResp   []models.DriverLicense
+	getExpiringErr    error
+}
+
+func (m *MockDriverLicenseRepository) GetByDriverId(driverId string) (*models.DriverLicense, error) {
+	return m.getByDriverIdResp, m.getByDriverIdErr
+}
+
+func (m *MockDriverLicenseRepository) Save(license *models.DriverLicense) error {
+	return m.saveErr
+}
+
+func (m *MockDriverLicenseRepository) GetExpiring(before time.Time) ([]models.DriverLicense, error) {
+	return m.getExpiringResp, m.getExpiringErr
+}
+
+// newTestLicense returns a ten-year licence with a D category issued at the given time.
+func newTestLicense(driverID string, issued time.Time) *models.DriverLicense {
+	return &models.DriverLicense{
+		DriverID:   driverID,
+		IssueDate:  issued,
+		ExpiryDate: issued.AddDate(10, 0, 0),
+		Categories: []models.LicenseCategory{
+			{Category: "B", IssueDate: issued, ExpiryDate: issued.AddDate(10, 0, 0)},
+			{Category: "D", IssueDate: issued, ExpiryDate: issued.AddDate(10, 0, 0)},
+		},
+	}
+}
+
+func TestLicenseService_Save(t *testing.T) {
+	driver := &models.Driver{ID: uuid.New().String(), Name: "John", Surname: "Doe"}
+	issued := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		service := NewLicenseService(&MockDriverLicenseRepository{}, &MockDriverRepository{getByIdResp: driver})
+		license := newTestLicense(driver.ID, issued)
+		license.Categories[1].Category = " d1 "
+
+		err := service.Save(license)
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if license.Categories[1].Category != "D1" {
+			t.Errorf("Expected category to be normalized to D1, got %q", license.Categories[1].Category)
+		}
+	})
+
+	t.Run("Driver not found", func(t *testing.T) {
+		service := NewLicenseService(&MockDriverLicenseRepository{}, &MockDriverRepository{getByIdErr: errors.New("Driver not found")})
+
+		err := service.Save(newTestLicense(driver.ID, issued))
+		if err == nil || err.Error() != "Driver not found" {
+			t.Errorf("Expected 'Driver not found' error, got %v", err)
+		}
+	})
+
+	t.Run("Unknown category", func(t *testing.T) {
+		service := NewLicenseService(&MockDriverLicenseRepository{}, &MockDriverRepository{getByIdResp: driver})
+		license := newTestLicense(driver.ID, issued)
+		license.Categories[0].Category = "Z"
+
+		err := service.Save(license)
+		if err == nil || err.Error() != `Unknown license category "Z"` {
+			t.Errorf("Expected unknown category error, got %v", err)
+		}
+	})
+
+	t.Run("Expiry before issue", func(t *testing.T) {
+		service := NewLicenseService(&MockDriverLicenseRepository{}, &MockDriverRepository{getByIdResp: driver})
+		license := newTestLicense(driver.ID, issued)
+		license.ExpiryDate = issued.AddDate(-1, 0, 0)
+
+		err := service.Save(license)
+		if err == nil || err.Error() != "License expiry date must be after its issue date" {
+			t.Errorf("Expected expiry date error, got %v", err)
+		}
+	})
+}
+
+func TestLicenseService_CheckDriverForVehicleClass(t *testing.T) {
+	driverID := uuid.New().String()
+	issued := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := issued.AddDate(1, 0, 0)
+
+	t.Run("D covers D1", func(t *testing.T) {
+		service := NewLicenseService(&MockDriverLicenseRepository{getByDriverIdResp: newTestLicense(driverID, issued)}, nil)
+
+		for _, class := range []string{"", "D", "D1"} {
+			if err := service.CheckDriverForVehicleClass(driverID, class, at); err != nil {
+				t.Errorf("Expected no error for class %q, got %v", class, err)
+			}
+		}
+	})
+
+	t.Run("No license", func(t *testing.T) {
+		service := NewLicenseService(&MockDriverLicenseRepository{getByDriverIdErr: errors.New("License not found")}, nil)
+
+		err := service.CheckDriverForVehicleClass(driverID, "D", at)
+		if err == nil || err.Error() != "Driver has no license on record" {
+			t.Errorf("Expected 'Driver has no license on record' error, got %v", err)
+		}
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		service := NewLicenseService(&MockDriverLicenseRepository{getByDriverIdErr: errors.New("connection refused")}, nil)
+
+		err := service.CheckDriverForVehicleClass(driverID, "D", at)
+		if err == nil || err.Error() != "connection refused" {
+			t.Errorf("Expected the database error, got %v", err)
+		}
+	})
+
+	t.Run("Expired license", func(t *testing.T) {
+		service := NewLicenseService(&MockDriverLicenseRepository{getByDriverIdResp: newTestLicense(driverID, issued)}, nil)
+
+		err := service.CheckDriverForVehicleClass(driverID, "D", issued.AddDate(10, 0, 0))
+		if err == nil || err.Error() != "Driver license expired on 2030-01-01" {
+			t.Errorf("Expected expired license error, got %v", err)
+		}
+	})
+
+	t.Run("Expired category", func(t *testing.T) {
+		license := newTestLicense(driverID, issued)
+		license.Categories[1].ExpiryDate = issued.AddDate(0, 6, 0)
+		service := NewLicenseService(&MockDriverLicenseRepository{getByDriverIdResp: license}, nil)
+
+		err := service.CheckDriverForVehicleClass(driverID, "D", at)
+		if err == nil || err.Error() != "Driver has no valid license category for vehicle class D" {
+			t.Errorf("Expected missing category error, got %v", err)
+		}
+	})
+}
+
+func TestLicenseService_GetExpiring(t *testing.T) {
+	driver := &models.Driver{ID: uuid.New().String(), Name: "John", Surname: "Doe"}
+	now := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		license := models.DriverLicense{
+			DriverID:   driver.ID,
+			IssueDate:  now.AddDate(-10, 0, 0),
+			ExpiryDate: now.AddDate(0, 0, 20),
+			Categories: []models.LicenseCategory{
+				{Category: "D", IssueDate: now.AddDate(-5, 0, 0), ExpiryDate: now.AddDate(0, 0, 10)},
+				{Category: "B", IssueDate: now.AddDate(-10, 0, 0), ExpiryDate: now.AddDate(1, 0, 0)},
+			},
+		}
+		service := NewLicenseService(&MockDriverLicenseRepository{getExpiringResp: []models.DriverLicense{license}}, &MockDriverRepository{getByIdResp: driver})
+
+		report, err := service.GetExpiring(30)
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if len(report) != 2 {
+			t.Fatalf("Expected 2 entries, got %v", report)
+		}
+		if report[0].Category != "D" || report[0].DaysLeft != 10 || report[1].Category != "" || report[1].Surname != "Doe" {
+			t.Errorf("Unexpected report: %v", report)
+		}
+	})
+
+	t.Run("Deleted driver", func(t *testing.T) {
+		license := models.DriverLicense{
+			DriverID:   uuid.New().String(),
+			IssueDate:  now.AddDate(-10, 0, 0),
+			ExpiryDate: now.AddDate(0, 0, 20),
+		}
+		service := NewLicenseService(&MockDriverLicenseRepository{getExpiringResp: []models.DriverLicense{license}}, &MockDriverRepository{getByIdErr: errors.New("Driver not found")})
+
+		report, err := service.GetExpiring(30)
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if len(report) != 0 {
+			t.Errorf("Expected licences of deleted drivers to be skipped, got %v", report)
+		}
+	})
+
+	t.Run("Negative days", func(t *testing.T) {
+		service := NewLicenseService(&MockDriverLicenseRepository{}, nil)
+
+		_, err := service.GetExpiring(-1)
+		if err == nil || err.Error() != "Days must not be negative" {
+			t.Errorf("Expected 'Days must not be negative' error, got %v", err)
+		}
+	})
+}
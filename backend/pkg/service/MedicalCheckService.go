@@ -0,0 +1,149 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/repository"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type MedicalCheckService struct {
+	repo       repository.IMedicalCheckRepository
+	dutyRepo   repository.IDutyRepository
+	driverRepo repository.IDriverRepository
+}
+
+func NewMedicalCheckService(
+	r repository.IMedicalCheckRepository,
+	dutyRepo repository.IDutyRepository,
+	driverRepo repository.IDriverRepository,
+) *MedicalCheckService {
+	s := &MedicalCheckService{r, dutyRepo, driverRepo}
+	return s
+}
+
+func (s MedicalCheckService) GetById(id string) (*models.MedicalCheck, error) {
+	check, err := s.repo.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if check == nil {
+		return nil, errors.New("Medical check not found")
+	}
+	return check, nil
+}
+
+func (s MedicalCheckService) Add(check *models.MedicalCheck) error {
+	driver, err := s.driverRepo.GetById(check.DriverID)
+	if driver == nil {
+		return errors.New("Driver not found")
+	}
+	if err != nil {
+		return err
+	}
+	check.Examiner = strings.TrimSpace(check.Examiner)
+	if check.Examiner == "" {
+		return errors.New("Examiner is required")
+	}
+	if check.Systolic <= 0 || check.Diastolic <= 0 || check.Diastolic >= check.Systolic {
+		return errors.New("Invalid blood pressure")
+	}
+	if check.Alcohol < 0 {
+		return errors.New("Alcohol test result can't be negative")
+	}
+	if check.Passed && check.Alcohol > 0 {
+		return errors.New("Check with a positive alcohol test can't be passed")
+	}
+	if check.CheckedAt.IsZero() {
+		check.CheckedAt = time.Now()
+	}
+	return s.repo.Add(check)
+}
+
+func (s MedicalCheckService) GetByDriverId(driverId string, from, to time.Time) ([]models.MedicalCheck, error) {
+	if !to.After(from) {
+		return nil, errors.New("Period end must be after period start")
+	}
+	return s.repo.GetByDriverId(driverId, from, to)
+}
+
+// CheckPassed returns an error unless the latest check of the driver made
+// on the day of at, and not later than at, was passed.
+func (s MedicalCheckService) CheckPassed(driverId string, at time.Time) error {
+	checks, err := s.repo.GetByDriverId(driverId, startOfDay(at), at.Add(time.Second))
+	if err != nil {
+		return err
+	}
+	if len(checks) == 0 {
+		return errors.New("Driver has no medical check today")
+	}
+	latest := latestCheck(checks)
+	if !latest.Passed {
+		return fmt.Errorf("Driver failed the medical check at %s", latest.CheckedAt.Format("15:04"))
+	}
+	return nil
+}
+
+// GetPending returns drivers rostered on date whose latest check of that day
+// is missing or failed, ordered by their first shift start.
+func (s MedicalCheckService) GetPending(date time.Time) ([]models.PendingMedicalCheck, error) {
+	day := startOfDay(date)
+	duties, err := s.dutyRepo.GetByPeriod(day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	checks, err := s.repo.GetByPeriod(day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	byDriver := make(map[string][]models.MedicalCheck)
+	for _, check := range checks {
+		byDriver[check.DriverID] = append(byDriver[check.DriverID], check)
+	}
+
+	firstShift := make(map[string]time.Time)
+	for _, duty := range duties {
+		start, ok := firstShift[duty.DriverID]
+		if !ok || duty.ShiftStart.Before(start) {
+			firstShift[duty.DriverID] = duty.ShiftStart
+		}
+	}
+
+	pending := []models.PendingMedicalCheck{}
+	for driverId, shiftStart := range firstShift {
+		item := models.PendingMedicalCheck{DriverID: driverId, ShiftStart: shiftStart}
+		if driverChecks := byDriver[driverId]; len(driverChecks) > 0 {
+			if latestCheck(driverChecks).Passed {
+				continue
+			}
+			item.Failed = true
+		}
+		driver, err := s.driverRepo.GetById(driverId)
+		if err == nil && driver != nil {
+			item.Name = driver.Name
+			item.Surname = driver.Surname
+			item.Patronymic = driver.Patronymic
+		}
+		pending = append(pending, item)
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		if pending[i].ShiftStart.Equal(pending[j].ShiftStart) {
+			return pending[i].DriverID < pending[j].DriverID
+		}
+		return pending[i].ShiftStart.Before(pending[j].ShiftStart)
+	})
+	return pending, nil
+}
+
+func latestCheck(checks []models.MedicalCheck) models.MedicalCheck {
+	latest := checks[0]
+	for _, check := range checks[1:] {
+		if check.CheckedAt.After(latest.CheckedAt) {
+			latest = check
+		}
+	}
+	return latest
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"errors"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+type MockMedicalCheckRepository struct {
+	getByIdResp       *models.MedicalCheck
+	getByIdErr        error
+	addErr            error
+	getByDriverIdResp []models.MedicalCheck
+	getByDriverIdErr  error
+	getByPeriodResp   []models.MedicalCheck
+	getByPeriodErr    error
+}
+
+func (m *MockMedicalCheckRepository) GetById(id string) (*models.MedicalCheck, error) {
+	return m.getByIdResp, m.getByIdErr
+}
+
+func (m *MockMedicalCheckRepository) Add(check *models.MedicalCheck) error {
+	return m.addErr
+}
+
+func (m *MockMedicalCheckRepository) GetByDriverId(driverId string, from, to time.Time) ([]models.MedicalCheck, error) {
+	return m.getByDriverIdResp, m.getByDriverIdErr
+}
+
+func (m *MockMedicalCheckRepository) GetByPeriod(from, to time.Time) ([]models.MedicalCheck, error) {
+	return m.getByPeriodResp, m.getByPeriodErr
+}
+
+func newTestMedicalCheck(driverID string, checkedAt time.Time) *models.MedicalCheck {
+	return &models.MedicalCheck{
+		DriverID:  driverID,
+		CheckedAt: checkedAt,
+		Passed:    true,
+		Systolic:  120,
+		Diastolic: 80,
+		Examiner:  "Petrova A.V.",
+	}
+}
+
+func TestMedicalCheckService_Add(t *testing.T) {
+	driver := &models.Driver{ID: uuid.New().String(), Name: "John", Surname: "Doe"}
+	checkedAt := time.Date(2025, 3, 3, 5, 30, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		service := NewMedicalCheckService(&MockMedicalCheckRepository{}, &MockDutyRepository{}, &MockDriverRepository{getByIdResp: driver})
+
+		err := service.Add(newTestMedicalCheck(driver.ID, checkedAt))
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Driver not found", func(t *testing.T) {
+		service := NewMedicalCheckService(&MockMedicalCheckRepository{}, &MockDutyRepository{}, &MockDriverRepository{getByIdErr: errors.New("Driver not found")})
+
+		err := service.Add(newTestMedicalCheck(driver.ID, checkedAt))
+		if err == nil || err.Error() != "Driver not found" {
+			t.Errorf("Expected 'Driver not found' error, got %v", err)
+		}
+	})
+
+	invalid := []struct {
+		name   string
+		modify func(c *models.MedicalCheck)
+		err    string
+	}{
+		{"Missing examiner", func(c *models.MedicalCheck) { c.Examiner = " " }, "Examiner is required"},
+		{"Invalid blood pressure", func(c *models.MedicalCheck) { c.Diastolic = 130 }, "Invalid blood pressure"},
+		{"Negative alcohol", func(c *models.MedicalCheck) { c.Alcohol = -0.1 }, "Alcohol test result can't be negative"},
+		{"Passed with alcohol", func(c *models.MedicalCheck) { c.Alcohol = 0.2 }, "Check with a positive alcohol test can't be passed"},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			service := NewMedicalCheckService(&MockMedicalCheckRepository{}, &MockDutyRepository{}, &MockDriverRepository{getByIdResp: driver})
+			check := newTestMedicalCheck(driver.ID, checkedAt)
+			tc.modify(check)
+
+			err := service.Add(check)
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("Expected '%s', got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestMedicalCheckService_CheckPassed(t *testing.T) {
+	driverID := uuid.New().String()
+	at := time.Date(2025, 3, 3, 6, 0, 0, 0, time.UTC)
+
+	t.Run("Passed", func(t *testing.T) {
+		repo := &MockMedicalCheckRepository{getByDriverIdResp: []models.MedicalCheck{*newTestMedicalCheck(driverID, at.Add(-time.Hour))}}
+		service := NewMedicalCheckService(repo, &MockDutyRepository{}, nil)
+
+		err := service.CheckPassed(driverID, at)
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Failed after passing", func(t *testing.T) {
+		failed := newTestMedicalCheck(driverID, at.Add(-30*time.Minute))
+		failed.Passed = false
+		repo := &MockMedicalCheckRepository{getByDriverIdResp: []models.MedicalCheck{*failed, *newTestMedicalCheck(driverID, at.Add(-time.Hour))}}
+		service := NewMedicalCheckService(repo, &MockDutyRepository{}, nil)
+
+		err := service.CheckPassed(driverID, at)
+		if err == nil || err.Error() != "Driver failed the medical check at 05:30" {
+			t.Errorf("Expected 'Driver failed the medical check at 05:30' error, got %v", err)
+		}
+	})
+
+	t.Run("No check", func(t *testing.T) {
+		service := NewMedicalCheckService(&MockMedicalCheckRepository{}, &MockDutyRepository{}, nil)
+
+		err := service.CheckPassed(driverID, at)
+		if err == nil || err.Error() != "Driver has no medical check today" {
+			t.Errorf("Expected 'Driver has no medical check today' error, got %v", err)
+		}
+	})
+}
+
+func TestMedicalCheckService_GetPending(t *testing.T) {
+	day := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	checkedID := uuid.New().String()
+	failedID := uuid.New().String()
+	waitingID := uuid.New().String()
+	duties := []models.Duty{
+		{ID: uuid.New().String(), Date: day, DriverID: checkedID, ShiftStart: day.Add(5 * time.Hour)},
+		{ID: uuid.New().String(), Date: day, DriverID: waitingID, ShiftStart: day.Add(14 * time.Hour)},
+		{ID: uuid.New().String(), Date: day, DriverID: failedID, ShiftStart: day.Add(7 * time.Hour)},
+		{ID: uuid.New().String(), Date: day, DriverID: waitingID, ShiftStart: day.Add(9 * time.Hour)},
+	}
+	failed := newTestMedicalCheck(failedID, day.Add(6*time.Hour))
+	failed.Passed = false
+	checks := []models.MedicalCheck{*newTestMedicalCheck(checkedID, day.Add(4*time.Hour)), *failed}
+
+	service := NewMedicalCheckService(
+		&MockMedicalCheckRepository{getByPeriodResp: checks},
+		&MockDutyRepository{getByPeriodResp: duties},
+		&MockDriverRepository{getByIdResp: &models.Driver{Name: "John", Surname: "Doe"}},
+	)
+
+	pending, err := service.GetPending(day.Add(12 * time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("Expected 2 pending drivers, got %v", pending)
+	}
+	if pending[0].DriverID != failedID || !pending[0].Failed {
+		t.Errorf("Expected failed driver first, got %v", pending[0])
+	}
+	if pending[1].DriverID != waitingID || pending[1].Failed || !pending[1].ShiftStart.Equal(day.Add(9*time.Hour)) {
+		t.Errorf("Expected waiting driver with first shift at 09:00, got %v", pending[1])
+	}
+	if pending[1].Surname != "Doe" {
+		t.Errorf("Expected driver name to be filled, got %v", pending[1])
+	}
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"crypto/sha1"
+	"crypto/subtle"
+	"fmt"
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+)
+
+// hashPassword returns a bcrypt hash, which carries its own salt and cost
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// checkPassword compares the password with a stored hash. rehash is set when
+// the hash is a legacy one and should be replaced by hashPassword.
+func checkPassword(hash, password string) (ok bool, rehash bool) {
+	if isBcryptHash(hash) {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil, false
+	}
+	ok = subtle.ConstantTimeCompare([]byte(hash), []byte(legacyPasswordHash(password))) == 1
+	return ok, ok
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// legacyPasswordHash is the SHA-1 hash with the global salt that passwords
+// were stored with before bcrypt. It is only used to check old hashes.
+func legacyPasswordHash(password string) string {
+	salt := viper.GetString("salt")
+	hash := sha1.New()
+	hash.Write([]byte(password))
+	return fmt.Sprintf("%x", hash.Sum([]byte(salt)))
+}
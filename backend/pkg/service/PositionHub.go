@@ -0,0 +1,87 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"sync"
+)
+
+// subscriberBuffer is how many batches a slow subscriber may lag behind
+// before new batches are dropped for it.
+const subscriberBuffer = 16
+
+// PositionFilter selects the positions a subscriber receives. Empty fields
+// match everything.
+type PositionFilter struct {
+	BusIDs map[string]bool
+	Box    *models.BoundingBox
+}
+
+func (f PositionFilter) Match(position models.VehiclePosition) bool {
+	if f.BusIDs != nil && !f.BusIDs[position.BusID] {
+		return false
+	}
+	if f.Box != nil && !f.Box.Contains(position.Lat, position.Long) {
+		return false
+	}
+	return true
+}
+
+func (f PositionFilter) apply(positions []models.VehiclePosition) []models.VehiclePosition {
+	var matched []models.VehiclePosition
+	for _, position := range positions {
+		if f.Match(position) {
+			matched = append(matched, position)
+		}
+	}
+	return matched
+}
+
+type positionSubscriber struct {
+	filter PositionFilter
+	ch     chan []models.VehiclePosition
+}
+
+// PositionHub fans out ingested positions to the connected stream clients.
+type PositionHub struct {
+	mu          sync.Mutex
+	subscribers map[*positionSubscriber]struct{}
+}
+
+func NewPositionHub() *PositionHub {
+	return &PositionHub{subscribers: make(map[*positionSubscriber]struct{})}
+}
+
+// Subscribe registers a subscriber and returns its channel together with
+// the function that unregisters it and closes the channel.
+func (h *PositionHub) Subscribe(filter PositionFilter) (<-chan []models.VehiclePosition, func()) {
+	sub := &positionSubscriber{filter: filter, ch: make(chan []models.VehiclePosition, subscriberBuffer)}
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers, sub)
+			h.mu.Unlock()
+			close(sub.ch)
+		})
+	}
+}
+
+// Publish never blocks: a subscriber whose buffer is full misses the batch.
+func (h *PositionHub) Publish(positions []models.VehiclePosition) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subscribers {
+		matched := sub.filter.apply(positions)
+		if len(matched) == 0 {
+			continue
+		}
+		select {
+		case sub.ch <- matched:
+		default:
+		}
+	}
+}
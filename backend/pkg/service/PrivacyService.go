@@ -0,0 +1,66 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/repository"
+	"time"
+	"unicode"
+)
+
+type PrivacyService struct {
+	repo repository.IPersonalDataAccessRepository
+}
+
+func NewPrivacyService(r repository.IPersonalDataAccessRepository) *PrivacyService {
+	s := &PrivacyService{r}
+	return s
+}
+
+// ProtectDriver masks the identity documents of the driver unless the caller
+// holds the HR role, in which case the unmasked read is logged. Admin alone
+// still sees masked values.
+func (s PrivacyService) ProtectDriver(driver *models.Driver, userId string, roles []string, action string) error {
+	if !models.HoldsRole(roles, models.RoleHR) {
+		maskDriver(driver)
+		return nil
+	}
+	return s.repo.Add(&models.PersonalDataAccess{
+		UserID:     userId,
+		DriverID:   driver.ID,
+		Action:     action,
+		AccessedAt: time.Now(),
+	})
+}
+
+func (s PrivacyService) ProtectDrivers(drivers []models.Driver, userId string, roles []string, action string) error {
+	for i := range drivers {
+		err := s.ProtectDriver(&drivers[i], userId, roles, action)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func maskDriver(driver *models.Driver) {
+	driver.PassportSeries = maskValue(driver.PassportSeries, 3)
+	driver.Snils = maskValue(driver.Snils, 2)
+	driver.LicenseSeries = maskValue(driver.LicenseSeries, 3)
+}
+
+// maskValue hides every letter and digit but the last keep ones, leaving
+// separators in place so the value keeps its format.
+func maskValue(value string, keep int) string {
+	runes := []rune(value)
+	for i := len(runes) - 1; i >= 0; i-- {
+		if !unicode.IsLetter(runes[i]) && !unicode.IsDigit(runes[i]) {
+			continue
+		}
+		if keep > 0 {
+			keep--
+			continue
+		}
+		runes[i] = '*'
+	}
+	return string(runes)
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"errors"
+	"github.com/google/uuid"
+	"testing"
+)
+
+type MockPersonalDataAccessRepository struct {
+	addErr error
+	added  []models.PersonalDataAccess
+}
+
+func (m *MockPersonalDataAccessRepository) Add(access *models.PersonalDataAccess) error {
+	m.added = append(m.added, *access)
+	return m.addErr
+}
+
+func newTestDriverWithDocuments() models.Driver {
+	return models.Driver{
+		ID:             uuid.New().String(),
+		Name:           "John",
+		Surname:        "Doe",
+		PassportSeries: "4510 123456",
+		Snils:          "112-233-445 95",
+		LicenseSeries:  "99 АВ 123456",
+	}
+}
+
+func TestPrivacyService_ProtectDriver(t *testing.T) {
+	userId := uuid.New().String()
+
+	for _, role := range []string{models.RoleViewer, models.RoleDispatcher, models.RoleAdmin, ""} {
+		t.Run("Masked for role '"+role+"'", func(t *testing.T) {
+			repo := &MockPersonalDataAccessRepository{}
+			service := NewPrivacyService(repo)
+			driver := newTestDriverWithDocuments()
+
+			err := service.ProtectDriver(&driver, userId, []string{role}, "GET /api/drivers/id/:id")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if driver.PassportSeries != "**** ***456" {
+				t.Errorf("Expected masked passport '**** ***456', got '%s'", driver.PassportSeries)
+			}
+			if driver.Snils != "***-***-*** 95" {
+				t.Errorf("Expected masked SNILS '***-***-*** 95', got '%s'", driver.Snils)
+			}
+			if driver.LicenseSeries != "** ** ***456" {
+				t.Errorf("Expected masked license '** ** ***456', got '%s'", driver.LicenseSeries)
+			}
+			if len(repo.added) != 0 {
+				t.Errorf("Expected no access log entries, got %v", repo.added)
+			}
+		})
+	}
+
+	t.Run("HR sees full values and read is logged", func(t *testing.T) {
+		repo := &MockPersonalDataAccessRepository{}
+		service := NewPrivacyService(repo)
+		driver := newTestDriverWithDocuments()
+
+		err := service.ProtectDriver(&driver, userId, []string{models.RoleHR}, "GET /api/drivers/id/:id")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if driver.PassportSeries != "4510 123456" || driver.Snils != "112-233-445 95" {
+			t.Errorf("Expected unmasked documents, got %v", driver)
+		}
+		if len(repo.added) != 1 {
+			t.Fatalf("Expected 1 access log entry, got %d", len(repo.added))
+		}
+		entry := repo.added[0]
+		if entry.UserID != userId || entry.DriverID != driver.ID || entry.Action != "GET /api/drivers/id/:id" || entry.AccessedAt.IsZero() {
+			t.Errorf("Unexpected access log entry %v", entry)
+		}
+	})
+
+	t.Run("Access log error", func(t *testing.T) {
+		service := NewPrivacyService(&MockPersonalDataAccessRepository{addErr: errors.New("Database error")})
+		driver := newTestDriverWithDocuments()
+
+		err := service.ProtectDriver(&driver, userId, []string{models.RoleHR}, "GET /api/drivers/id/:id")
+		if err == nil || err.Error() != "Database error" {
+			t.Errorf("Expected 'Database error', got %v", err)
+		}
+	})
+}
+
+func TestPrivacyService_ProtectDrivers(t *testing.T) {
+	userId := uuid.New().String()
+	drivers := []models.Driver{newTestDriverWithDocuments(), newTestDriverWithDocuments()}
+
+	t.Run("Masks every driver", func(t *testing.T) {
+		masked := append([]models.Driver{}, drivers...)
+		service := NewPrivacyService(&MockPersonalDataAccessRepository{})
+
+		err := service.ProtectDrivers(masked, userId, []string{models.RoleViewer}, "GET /api/drivers/")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for _, driver := range masked {
+			if driver.PassportSeries != "**** ***456" {
+				t.Errorf("Expected masked passport, got '%s'", driver.PassportSeries)
+			}
+		}
+	})
+
+	t.Run("Logs every driver for HR", func(t *testing.T) {
+		repo := &MockPersonalDataAccessRepository{}
+		service := NewPrivacyService(repo)
+
+		err := service.ProtectDrivers(append([]models.Driver{}, drivers...), userId, []string{models.RoleHR}, "GET /api/drivers/")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(repo.added) != 2 {
+			t.Errorf("Expected 2 access log entries, got %d", len(repo.added))
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/repository"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type QualificationService struct {
+	repo       repository.IDriverQualificationRepository
+	driverRepo repository.IDriverRepository
+	busRepo    repository.IBusRepository
+}
+
+func NewQualificationService(
+	r repository.IDriverQualificationRepository,
+	driverRepo repository.IDriverRepository,
+	busRepo repository.IBusRepository,
+) *QualificationService {
+	s := &QualificationService{r, driverRepo, busRepo}
+	return s
+}
+
+func (s QualificationService) Save(qualification *models.DriverQualification) error {
+	driver, err := s.driverRepo.GetById(qualification.DriverID)
+	if driver == nil {
+		return errors.New("Driver not found")
+	}
+	if err != nil {
+		return err
+	}
+	qualification.Brand = strings.TrimSpace(qualification.Brand)
+	qualification.BusModel = strings.TrimSpace(qualification.BusModel)
+	qualification.Trainer = strings.TrimSpace(qualification.Trainer)
+	if qualification.Brand == "" || qualification.BusModel == "" {
+		return errors.New("Brand and bus model are required")
+	}
+	if qualification.Trainer == "" {
+		return errors.New("Trainer is required")
+	}
+	if qualification.TrainedOn.IsZero() || qualification.TrainedOn.After(time.Now()) {
+		return errors.New("Training date must be in the past")
+	}
+	qualification.TrainedOn = startOfDay(qualification.TrainedOn)
+	return s.repo.Save(qualification)
+}
+
+func (s QualificationService) Delete(driverId, brand, busModel string) error {
+	return s.repo.Delete(driverId, strings.TrimSpace(brand), strings.TrimSpace(busModel))
+}
+
+func (s QualificationService) GetByDriverId(driverId string) ([]models.DriverQualification, error) {
+	return s.repo.GetByDriverId(driverId)
+}
+
+// QualifiedDrivers returns the set of driver IDs trained on the bus's model.
+func (s QualificationService) QualifiedDrivers(bus models.Bus) (map[string]bool, error) {
+	qualifications, err := s.repo.GetByBusModel(bus.Brand, bus.BusModel)
+	if err != nil {
+		return nil, err
+	}
+	qualified := make(map[string]bool)
+	for _, qualification := range qualifications {
+		qualified[qualification.DriverID] = true
+	}
+	return qualified, nil
+}
+
+// GetMatrix returns every driver against every bus model in the fleet or in
+// the recorded qualifications, drivers ordered by surname and models by brand.
+func (s QualificationService) GetMatrix() (*models.QualificationMatrix, error) {
+	drivers, err := s.driverRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	buses, err := s.busRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	qualifications, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[models.BusModelKey]bool)
+	for _, bus := range buses {
+		keys[models.BusModelKey{Brand: bus.Brand, BusModel: bus.BusModel}] = true
+	}
+	qualified := make(map[string]bool)
+	for _, q := range qualifications {
+		keys[models.BusModelKey{Brand: q.Brand, BusModel: q.BusModel}] = true
+		qualified[qualificationKey(q.DriverID, q.Brand, q.BusModel)] = true
+	}
+	matrix := &models.QualificationMatrix{Models: []models.BusModelKey{}, Rows: []models.QualificationRow{}}
+	for key := range keys {
+		matrix.Models = append(matrix.Models, key)
+	}
+	sort.Slice(matrix.Models, func(i, j int) bool {
+		a, b := matrix.Models[i], matrix.Models[j]
+		if a.Brand != b.Brand {
+			return a.Brand < b.Brand
+		}
+		return a.BusModel < b.BusModel
+	})
+
+	sort.SliceStable(drivers, func(i, j int) bool {
+		if drivers[i].Surname != drivers[j].Surname {
+			return drivers[i].Surname < drivers[j].Surname
+		}
+		return drivers[i].Name < drivers[j].Name
+	})
+	for _, driver := range drivers {
+		row := models.QualificationRow{
+			DriverID:   driver.ID,
+			Name:       driver.Name,
+			Surname:    driver.Surname,
+			Patronymic: driver.Patronymic,
+			Qualified:  make([]bool, len(matrix.Models)),
+		}
+		for i, key := range matrix.Models {
+			row.Qualified[i] = qualified[qualificationKey(driver.ID, key.Brand, key.BusModel)]
+		}
+		matrix.Rows = append(matrix.Rows, row)
+	}
+	return matrix, nil
+}
+
+func qualificationKey(driverId, brand, busModel string) string {
+	return driverId + "\x00" + strings.ToLower(brand) + "\x00" + strings.ToLower(busModel)
+}
+
+func busModelName(bus models.Bus) string {
+	return fmt.Sprintf("%s %s", bus.Brand, bus.BusModel)
+}
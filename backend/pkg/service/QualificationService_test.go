@@ -0,0 +1,136 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"errors"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+type MockDriverQualificationRepository struct {
+	saveErr           error
+	deleteErr         error
+	getByDriverIdResp []models.DriverQualification
+	getByDriverIdErr  error
+	getByBusModelResp []models.DriverQualification
+	getByBusModelErr  error
+	getAllResp        []models.DriverQualification
+	getAllErr         error
+}
+
+func (m *MockDriverQualificationRepository) Save(qualification *models.DriverQualification) error {
+	return m.saveErr
+}
+
+func (m *MockDriverQualificationRepository) Delete(driverId, brand, busModel string) error {
+	return m.deleteErr
+}
+
+func (m *MockDriverQualificationRepository) GetByDriverId(driverId string) ([]models.DriverQualification, error) {
+	return m.getByDriverIdResp, m.getByDriverIdErr
+}
+
+func (m *MockDriverQualificationRepository) GetByBusModel(brand, busModel string) ([]models.DriverQualification, error) {
+	return m.getByBusModelResp, m.getByBusModelErr
+}
+
+func (m *MockDriverQualificationRepository) GetAll() ([]models.DriverQualification, error) {
+	return m.getAllResp, m.getAllErr
+}
+
+func TestQualificationService_Save(t *testing.T) {
+	driver := &models.Driver{ID: uuid.New().String(), Name: "John", Surname: "Doe"}
+	newQualification := func() *models.DriverQualification {
+		return &models.DriverQualification{
+			DriverID:  driver.ID,
+			Brand:     " ЛиАЗ ",
+			BusModel:  "5292",
+			TrainedOn: time.Date(2024, 5, 20, 14, 0, 0, 0, time.UTC),
+			Trainer:   "Сидоров И.П.",
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		service := NewQualificationService(&MockDriverQualificationRepository{}, &MockDriverRepository{getByIdResp: driver}, nil)
+		qualification := newQualification()
+
+		err := service.Save(qualification)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if qualification.Brand != "ЛиАЗ" || qualification.TrainedOn.Hour() != 0 {
+			t.Errorf("Expected normalized qualification, got %v", qualification)
+		}
+	})
+
+	invalid := []struct {
+		name   string
+		modify func(q *models.DriverQualification)
+		err    string
+	}{
+		{"Missing model", func(q *models.DriverQualification) { q.BusModel = "" }, "Brand and bus model are required"},
+		{"Missing trainer", func(q *models.DriverQualification) { q.Trainer = " " }, "Trainer is required"},
+		{"Training in the future", func(q *models.DriverQualification) { q.TrainedOn = time.Now().AddDate(0, 0, 1) }, "Training date must be in the past"},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			service := NewQualificationService(&MockDriverQualificationRepository{}, &MockDriverRepository{getByIdResp: driver}, nil)
+			qualification := newQualification()
+			tc.modify(qualification)
+
+			err := service.Save(qualification)
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("Expected '%s', got %v", tc.err, err)
+			}
+		})
+	}
+
+	t.Run("Driver not found", func(t *testing.T) {
+		service := NewQualificationService(&MockDriverQualificationRepository{}, &MockDriverRepository{getByIdErr: errors.New("Driver not found")}, nil)
+
+		err := service.Save(newQualification())
+		if err == nil || err.Error() != "Driver not found" {
+			t.Errorf("Expected 'Driver not found' error, got %v", err)
+		}
+	})
+}
+
+func TestQualificationService_GetMatrix(t *testing.T) {
+	drivers := []models.Driver{
+		{ID: "d1", Name: "Иван", Surname: "Петров"},
+		{ID: "d2", Name: "Пётр", Surname: "Иванов"},
+	}
+	buses := []models.Bus{
+		{ID: "b1", Brand: "ПАЗ", BusModel: "3205"},
+		{ID: "b2", Brand: "ЛиАЗ", BusModel: "5292"},
+		{ID: "b3", Brand: "ЛиАЗ", BusModel: "5292"},
+	}
+	qualifications := []models.DriverQualification{
+		{DriverID: "d1", Brand: "ЛиАЗ", BusModel: "5292"},
+		{DriverID: "d2", Brand: "ПАЗ", BusModel: "3205"},
+		{DriverID: "d2", Brand: "ЛиАЗ", BusModel: "5292"},
+	}
+	service := NewQualificationService(
+		&MockDriverQualificationRepository{getAllResp: qualifications},
+		&MockDriverRepository{getAllResp: drivers},
+		&MockBusRepository{getAllResp: buses},
+	)
+
+	matrix, err := service.GetMatrix()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(matrix.Models) != 2 || matrix.Models[0].Brand != "ЛиАЗ" || matrix.Models[1].Brand != "ПАЗ" {
+		t.Fatalf("Expected models ЛиАЗ 5292 and ПАЗ 3205, got %v", matrix.Models)
+	}
+	if len(matrix.Rows) != 2 || matrix.Rows[0].DriverID != "d2" {
+		t.Fatalf("Expected rows ordered by surname, got %v", matrix.Rows)
+	}
+	if !matrix.Rows[0].Qualified[0] || !matrix.Rows[0].Qualified[1] {
+		t.Errorf("Expected Иванов qualified for both models, got %v", matrix.Rows[0].Qualified)
+	}
+	if !matrix.Rows[1].Qualified[0] || matrix.Rows[1].Qualified[1] {
+		t.Errorf("Expected Петров qualified for ЛиАЗ only, got %v", matrix.Rows[1].Qualified)
+	}
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/repository"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	MaxCountBatch    = 1000
+	maxBucketMinutes = 60
+)
+
+type RidershipService struct {
+	repo        repository.IPassengerCountRepository
+	busRepo     repository.IBusRepository
+	routeRepo   repository.IRouteRepository
+	busStopRepo repository.IBusStopRepository
+	tripRepo    repository.ITripRepository
+}
+
+func NewRidershipService(r repository.IPassengerCountRepository, busRepo repository.IBusRepository, routeRepo repository.IRouteRepository, busStopRepo repository.IBusStopRepository, tripRepo repository.ITripRepository) *RidershipService {
+	s := &RidershipService{r, busRepo, routeRepo, busStopRepo, tripRepo}
+	return s
+}
+
+// Ingest stores the valid counts of the batch and reports the invalid ones.
+// A count without a route takes the route of its trip or of its bus.
+func (s RidershipService) Ingest(counts []models.PassengerCount) (*models.CountBatchResult, error) {
+	if len(counts) == 0 {
+		return nil, errors.New("Count batch is empty")
+	}
+	if len(counts) > MaxCountBatch {
+		return nil, errors.New("Count batch is too large")
+	}
+	result := &models.CountBatchResult{Rejected: []models.CountRejection{}}
+	buses := make(map[string]bool)
+	stops := make(map[string]bool)
+	trips := make(map[string]*models.Trip)
+	var network *routeNetwork
+	var accepted []models.PassengerCount
+	now := time.Now()
+	for i, count := range counts {
+		err := validateCount(count, now)
+		if err == nil {
+			exists, ok := buses[count.BusID]
+			if !ok {
+				bus, _ := s.busRepo.GetById(count.BusID)
+				exists = bus != nil
+				buses[count.BusID] = exists
+			}
+			if !exists {
+				err = errors.New("Bus not found")
+			}
+		}
+		if err == nil && count.StopID != "" {
+			exists, ok := stops[count.StopID]
+			if !ok {
+				stop, _ := s.busStopRepo.GetById(count.StopID)
+				exists = stop != nil
+				stops[count.StopID] = exists
+			}
+			if !exists {
+				err = errors.New("Bus stop not found")
+			}
+		}
+		if err == nil && count.TripID != "" {
+			trip, ok := trips[count.TripID]
+			if !ok {
+				trip, _ = s.tripRepo.GetById(count.TripID)
+				trips[count.TripID] = trip
+			}
+			if trip == nil {
+				err = errors.New("Trip not found")
+			} else if count.RouteID == "" {
+				count.RouteID = trip.RouteID
+			}
+		}
+		if err == nil && count.RouteID == "" {
+			if network == nil {
+				// only the bus placement is used, travel times are not needed
+				network, err = loadRouteNetwork(s.routeRepo, nil, time.Now())
+				if err != nil {
+					return nil, err
+				}
+			}
+			count.RouteID = network.busRoutes[count.BusID]
+			if count.RouteID == "" {
+				err = errors.New("Bus is not assigned to a route")
+			}
+		}
+		if err != nil {
+			result.Rejected = append(result.Rejected, models.CountRejection{Index: i, Error: err.Error()})
+			continue
+		}
+		count.Granularity = countGranularity(count)
+		accepted = append(accepted, count)
+	}
+	if len(accepted) > 0 {
+		err := s.repo.AddBatch(accepted)
+		if err != nil {
+			return nil, err
+		}
+	}
+	result.Accepted = len(accepted)
+	return result, nil
+}
+
+func validateCount(count models.PassengerCount, now time.Time) error {
+	if count.BusID == "" {
+		return errors.New("Bus ID is required")
+	}
+	if count.CountedAt.IsZero() {
+		return errors.New("Timestamp is required")
+	}
+	if count.CountedAt.After(now.Add(maxClockSkew)) {
+		return errors.New("Timestamp is in the future")
+	}
+	if count.Boardings < 0 || count.Alightings < 0 {
+		return errors.New("Counts can't be negative")
+	}
+	if count.BucketMinutes < 0 || count.BucketMinutes > maxBucketMinutes {
+		return fmt.Errorf("Bucket must be from 0 to %d minutes", maxBucketMinutes)
+	}
+	if count.StopID == "" && count.TripID == "" && count.BucketMinutes == 0 {
+		return errors.New("Count must be per stop, per trip or per time bucket")
+	}
+	return nil
+}
+
+// countGranularity tells what a valid count covers. A count at a stop may
+// name its trip, and a time bucket may name the trip it fell into, so the
+// stop goes first and a count with only a trip covers the whole trip.
+func countGranularity(count models.PassengerCount) string {
+	switch {
+	case count.StopID != "":
+		return models.CountPerStop
+	case count.BucketMinutes > 0:
+		return models.CountPerBucket
+	default:
+		return models.CountPerTrip
+	}
+}
+
+// GetReport sums the counts of one granularity, per stop by default. The
+// stop grouping needs per-stop counts.
+func (s RidershipService) GetReport(from, to time.Time, groupBy, granularity, routeId string) ([]models.RidershipRow, error) {
+	if groupBy != models.RidershipByRoute && groupBy != models.RidershipByStop && groupBy != models.RidershipByHour {
+		return nil, fmt.Errorf("Unknown report grouping %s", groupBy)
+	}
+	if granularity == "" {
+		granularity = models.CountPerStop
+	}
+	if granularity != models.CountPerStop && granularity != models.CountPerTrip && granularity != models.CountPerBucket {
+		return nil, fmt.Errorf("Unknown count granularity %s", granularity)
+	}
+	if groupBy == models.RidershipByStop && granularity != models.CountPerStop {
+		return nil, errors.New("Stop report needs per-stop counts")
+	}
+	if !to.After(from) {
+		return nil, errors.New("Period end must be after period start")
+	}
+	if routeId != "" {
+		route, _ := s.routeRepo.GetById(routeId)
+		if route == nil {
+			return nil, errors.New("Route not found")
+		}
+	}
+	rows, err := s.repo.Aggregate(from, to, groupBy, granularity, routeId)
+	if err != nil {
+		return nil, err
+	}
+	if rows == nil {
+		rows = []models.RidershipRow{}
+	}
+	return rows, nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+type MockPassengerCountRepository struct {
+	added         []models.PassengerCount
+	addBatchErr   error
+	aggregateResp []models.RidershipRow
+	aggregateErr  error
+	granularity   string
+}
+
+func (m *MockPassengerCountRepository) AddBatch(counts []models.PassengerCount) error {
+	m.added = append(m.added, counts...)
+	return m.addBatchErr
+}
+
+func (m *MockPassengerCountRepository) Aggregate(from, to time.Time, groupBy, granularity, routeId string) ([]models.RidershipRow, error) {
+	m.granularity = granularity
+	return m.aggregateResp, m.aggregateErr
+}
+
+func TestRidershipService_Ingest(t *testing.T) {
+	countedAt := time.Now().Add(-time.Hour)
+	routeRepo := &MockRouteRepository{
+		getAllResp:          []models.Route{{ID: "route-1", Number: "42"}},
+		getAllBusesByIdResp: []models.Bus{{ID: "bus-1"}},
+	}
+	newService := func(repo *MockPassengerCountRepository, tripRepo *MockTripRepository) *RidershipService {
+		return NewRidershipService(repo, &MockBusRepository{getByIdResp: &models.Bus{ID: "bus-1"}}, routeRepo,
+			&MockBusStopRepository{getByIdResp: &models.BusStop{ID: "s1"}}, tripRepo)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		repo := &MockPassengerCountRepository{}
+		service := newService(repo, &MockTripRepository{getByIdResp: &models.Trip{ID: "trip-1", RouteID: "route-2"}})
+
+		result, err := service.Ingest([]models.PassengerCount{
+			{BusID: "bus-1", CountedAt: countedAt, StopID: "s1", Boardings: 5, Alightings: 2},
+			{BusID: "bus-1", CountedAt: countedAt, TripID: "trip-1", Boardings: 40, Alightings: 40},
+			{BusID: "bus-1", CountedAt: countedAt, BucketMinutes: 15, Boardings: 12},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Accepted != 3 || len(result.Rejected) != 0 {
+			t.Fatalf("Expected 3 accepted counts, got %v", result)
+		}
+		if repo.added[0].RouteID != "route-1" || repo.added[1].RouteID != "route-2" {
+			t.Errorf("Expected routes from the bus and the trip, got %v", repo.added)
+		}
+		if repo.added[0].Granularity != models.CountPerStop || repo.added[1].Granularity != models.CountPerTrip || repo.added[2].Granularity != models.CountPerBucket {
+			t.Errorf("Expected stop, trip and bucket counts, got %v", repo.added)
+		}
+	})
+
+	t.Run("Invalid counts are rejected", func(t *testing.T) {
+		repo := &MockPassengerCountRepository{}
+		service := newService(repo, &MockTripRepository{getByIdErr: errors.New("Trip not found")})
+
+		result, err := service.Ingest([]models.PassengerCount{
+			{BusID: "bus-1", CountedAt: countedAt, StopID: "s1", Boardings: -1},
+			{BusID: "bus-1", CountedAt: countedAt},
+			{BusID: "bus-1", CountedAt: countedAt, TripID: "missing"},
+			{BusID: "bus-1", CountedAt: countedAt, BucketMinutes: 90},
+			{BusID: "bus-1", CountedAt: countedAt, StopID: "s1", Boardings: 1},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Accepted != 1 || len(result.Rejected) != 4 {
+			t.Fatalf("Expected 1 accepted and 4 rejected counts, got %v", result)
+		}
+		expected := []string{
+			"Counts can't be negative",
+			"Count must be per stop, per trip or per time bucket",
+			"Trip not found",
+			"Bucket must be from 0 to 60 minutes",
+		}
+		for i, message := range expected {
+			if result.Rejected[i].Error != message {
+				t.Errorf("Expected '%s' for item %d, got %v", message, result.Rejected[i].Index, result.Rejected[i].Error)
+			}
+		}
+	})
+
+	t.Run("Bus without a route", func(t *testing.T) {
+		service := NewRidershipService(&MockPassengerCountRepository{}, &MockBusRepository{getByIdResp: &models.Bus{ID: "bus-9"}}, &MockRouteRepository{},
+			&MockBusStopRepository{getByIdResp: &models.BusStop{ID: "s1"}}, &MockTripRepository{})
+
+		result, err := service.Ingest([]models.PassengerCount{{BusID: "bus-9", CountedAt: countedAt, StopID: "s1"}})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(result.Rejected) != 1 || result.Rejected[0].Error != "Bus is not assigned to a route" {
+			t.Errorf("Expected 'Bus is not assigned to a route', got %v", result)
+		}
+	})
+
+	t.Run("Empty batch", func(t *testing.T) {
+		_, err := newService(&MockPassengerCountRepository{}, &MockTripRepository{}).Ingest(nil)
+		if err == nil || err.Error() != "Count batch is empty" {
+			t.Errorf("Expected 'Count batch is empty' error, got %v", err)
+		}
+	})
+}
+
+func TestRidershipService_GetReport(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	t.Run("Success", func(t *testing.T) {
+		repo := &MockPassengerCountRepository{aggregateResp: []models.RidershipRow{{Key: "08", Boardings: 120, Alightings: 95}}}
+		service := NewRidershipService(repo, &MockBusRepository{}, &MockRouteRepository{getByIdResp: &models.Route{ID: "route-1"}}, &MockBusStopRepository{}, &MockTripRepository{})
+
+		rows, err := service.GetReport(from, to, models.RidershipByHour, "", "route-1")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(rows) != 1 || rows[0].Boardings != 120 {
+			t.Errorf("Expected the aggregated rows, got %v", rows)
+		}
+		if repo.granularity != models.CountPerStop {
+			t.Errorf("Expected per-stop counts by default, got %s", repo.granularity)
+		}
+	})
+
+	t.Run("Stop report of bucket counts", func(t *testing.T) {
+		service := NewRidershipService(&MockPassengerCountRepository{}, &MockBusRepository{}, &MockRouteRepository{}, &MockBusStopRepository{}, &MockTripRepository{})
+
+		_, err := service.GetReport(from, to, models.RidershipByStop, models.CountPerBucket, "")
+		if err == nil || err.Error() != "Stop report needs per-stop counts" {
+			t.Errorf("Expected 'Stop report needs per-stop counts' error, got %v", err)
+		}
+	})
+
+	t.Run("Unknown grouping", func(t *testing.T) {
+		service := NewRidershipService(&MockPassengerCountRepository{}, &MockBusRepository{}, &MockRouteRepository{}, &MockBusStopRepository{}, &MockTripRepository{})
+
+		_, err := service.GetReport(from, to, "driver", "", "")
+		if err == nil || err.Error() != "Unknown report grouping driver" {
+			t.Errorf("Expected 'Unknown report grouping driver' error, got %v", err)
+		}
+	})
+
+	t.Run("Route not found", func(t *testing.T) {
+		service := NewRidershipService(&MockPassengerCountRepository{}, &MockBusRepository{}, &MockRouteRepository{getByIdErr: errors.New("Route not found")}, &MockBusStopRepository{}, &MockTripRepository{})
+
+		_, err := service.GetReport(from, to, models.RidershipByRoute, "", "missing")
+		if err == nil || err.Error() != "Route not found" {
+			t.Errorf("Expected 'Route not found' error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/repository"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+type RosterService struct {
+	repo           repository.IDutyRepository
+	routeRepo      repository.IRouteRepository
+	driverRepo     repository.IDriverRepository
+	busRepo        repository.IBusRepository
+	compliance     IComplianceService
+	licenses       ILicenseService
+	medical        IMedicalCheckService
+	absences       IAbsenceService
+	qualifications IQualificationService
+}
+
+func NewRosterService(
+	r repository.IDutyRepository,
+	routeRepo repository.IRouteRepository,
+	driverRepo repository.IDriverRepository,
+	busRepo repository.IBusRepository,
+	compliance IComplianceService,
+	licenses ILicenseService,
+	medical IMedicalCheckService,
+	absences IAbsenceService,
+	qualifications IQualificationService,
+) *RosterService {
+	s := &RosterService{r, routeRepo, driverRepo, busRepo, compliance, licenses, medical, absences, qualifications}
+	return s
+}
+
+func (s RosterService) GetById(id string) (*models.Duty, error) {
+	duty, err := s.repo.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if duty == nil {
+		return nil, errors.New("Duty not found")
+	}
+	return duty, nil
+}
+
+func (s RosterService) Add(duty *models.Duty) (*models.DutyResult, error) {
+	err := s.validate(duty)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(duty.ID) == "" {
+		duty.ID = uuid.NewString()
+	}
+	violations, err := s.checkCompliance(duty)
+	if err != nil {
+		return nil, err
+	}
+	warnings, err := s.checkQualified(duty)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.Add(duty)
+	if err != nil {
+		return nil, err
+	}
+	return &models.DutyResult{Duty: *duty, Violations: violations, Warnings: warnings}, nil
+}
+
+func (s RosterService) DeleteById(id string) error {
+	err := s.repo.DeleteById(id)
+	return err
+}
+
+func (s RosterService) UpdateById(duty *models.Duty) (*models.DutyResult, error) {
+	_, err := s.GetById(duty.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.validate(duty)
+	if err != nil {
+		return nil, err
+	}
+	violations, err := s.checkCompliance(duty)
+	if err != nil {
+		return nil, err
+	}
+	warnings, err := s.checkQualified(duty)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.UpdateById(duty)
+	if err != nil {
+		return nil, err
+	}
+	return &models.DutyResult{Duty: *duty, Violations: violations, Warnings: warnings}, nil
+}
+
+// StartDuty marks the duty as started by its driver. The driver must have
+// passed a medical check on the day of the duty.
+func (s RosterService) StartDuty(id string, at time.Time) (*models.Duty, error) {
+	duty, err := s.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if duty.StartedAt != nil {
+		return nil, errors.New("Duty is already started")
+	}
+	if !sameDay(duty.Date, at) {
+		return nil, errors.New("Duty is not scheduled for today")
+	}
+	err = s.medical.CheckPassed(duty.DriverID, at)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.SetStarted(duty.ID, at)
+	if err != nil {
+		return nil, err
+	}
+	duty.StartedAt = &at
+	return duty, nil
+}
+
+func (s RosterService) GetDay(date time.Time) (*models.RosterDay, error) {
+	day := startOfDay(date)
+	duties, err := s.repo.GetByPeriod(day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	return &models.RosterDay{Date: day, Duties: duties}, nil
+}
+
+// GetWeek returns the roster for the Monday-to-Sunday week containing date.
+func (s RosterService) GetWeek(date time.Time) ([]models.RosterDay, error) {
+	monday := startOfWeek(date)
+	duties, err := s.repo.GetByPeriod(monday, monday.AddDate(0, 0, 7))
+	if err != nil {
+		return nil, err
+	}
+	week := make([]models.RosterDay, 7)
+	for i := range week {
+		week[i].Date = monday.AddDate(0, 0, i)
+	}
+	for _, duty := range duties {
+		for i := range week {
+			if sameDay(week[i].Date, duty.Date) {
+				week[i].Duties = append(week[i].Duties, duty)
+				break
+			}
+		}
+	}
+	return week, nil
+}
+
+func (s RosterService) validate(duty *models.Duty) error {
+	if !duty.ShiftEnd.After(duty.ShiftStart) {
+		return errors.New("Shift end must be after shift start")
+	}
+	if duty.Date.IsZero() {
+		duty.Date = startOfDay(duty.ShiftStart)
+	}
+	route, err := s.routeRepo.GetById(duty.RouteID)
+	if route == nil {
+		return errors.New("Route not found")
+	}
+	if err != nil {
+		return err
+	}
+	driver, err := s.driverRepo.GetById(duty.DriverID)
+	if driver == nil {
+		return errors.New("Driver not found")
+	}
+	if err != nil {
+		return err
+	}
+	bus, err := s.busRepo.GetById(duty.BusID)
+	if bus == nil {
+		return errors.New("Bus not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	drivers, err := s.routeRepo.GetAllDriversById(duty.RouteID)
+	if err != nil {
+		return err
+	}
+	if !containsDriver(drivers, duty.DriverID) {
+		return errors.New("Driver is not assigned to this route")
+	}
+	buses, err := s.routeRepo.GetAllBusesAt(duty.RouteID, duty.ShiftStart)
+	if err != nil {
+		return err
+	}
+	if !containsBus(buses, duty.BusID) {
+		return errors.New("Bus is not assigned to this route")
+	}
+	err = s.licenses.CheckDriverForVehicleClass(duty.DriverID, bus.VehicleClass, duty.ShiftEnd)
+	if err != nil {
+		return err
+	}
+	err = s.absences.CheckAvailable(duty.DriverID, duty.ShiftStart, duty.ShiftEnd)
+	if err != nil {
+		return err
+	}
+
+	overlapping, err := s.repo.GetOverlapping(duty.DriverID, duty.BusID, duty.ShiftStart, duty.ShiftEnd)
+	if err != nil {
+		return err
+	}
+	for _, other := range overlapping {
+		if other.ID == duty.ID {
+			continue
+		}
+		if other.DriverID == duty.DriverID {
+			return fmt.Errorf("Driver is already on duty from %s to %s",
+				other.ShiftStart.Format(time.RFC3339), other.ShiftEnd.Format(time.RFC3339))
+		}
+		return fmt.Errorf("Bus is already on duty from %s to %s",
+			other.ShiftStart.Format(time.RFC3339), other.ShiftEnd.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// checkCompliance rejects a duty that breaks working time rules unless the
+// duty carries an override, in which case the violations are returned as flags.
+func (s RosterService) checkCompliance(duty *models.Duty) ([]models.ComplianceViolation, error) {
+	violations, err := s.compliance.CheckDuty(*duty)
+	if err != nil {
+		return nil, err
+	}
+	if len(violations) > 0 && !duty.ComplianceOverride {
+		messages := make([]string, len(violations))
+		for i, violation := range violations {
+			messages[i] = violation.Message
+		}
+		return nil, fmt.Errorf("Duty violates working time rules: %s", strings.Join(messages, "; "))
+	}
+	return violations, nil
+}
+
+// checkQualified warns when the duty's driver isn't trained on the bus model.
+func (s RosterService) checkQualified(duty *models.Duty) ([]string, error) {
+	bus, err := s.busRepo.GetById(duty.BusID)
+	if err != nil {
+		return nil, err
+	}
+	qualified, err := s.qualifications.QualifiedDrivers(*bus)
+	if err != nil {
+		return nil, err
+	}
+	if qualified[duty.DriverID] {
+		return nil, nil
+	}
+	return []string{fmt.Sprintf("Driver is not qualified for bus model %s", busModelName(*bus))}, nil
+}
+
+func containsDriver(drivers []models.Driver, id string) bool {
+	for _, driver := range drivers {
+		if driver.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsBus(buses []models.Bus, id string) bool {
+	for _, bus := range buses {
+		if bus.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+func startOfWeek(t time.Time) time.Time {
+	day := startOfDay(t)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
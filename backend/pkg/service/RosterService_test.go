@@ -0,0 +1,416 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"errors"
+	"github.com/google/uuid"
+	"strings"
+	"testing"
+	"time"
+)
+
+type MockDutyRepository struct {
+	getByIdResp        *models.Duty
+	getByIdErr         error
+	addErr             error
+	deleteByIdErr      error
+	updateByIdErr      error
+	getByPeriodResp    []models.Duty
+	getByPeriodErr     error
+	getOverlappingResp []models.Duty
+	getOverlappingErr  error
+	getByDriverIdResp  []models.Duty
+	getByDriverIdErr   error
+	setStartedErr      error
+	startedId          string
+}
+
+func (m *MockDutyRepository) GetById(id string) (*models.Duty, error) {
+	return m.getByIdResp, m.getByIdErr
+}
+
+func (m *MockDutyRepository) Add(duty *models.Duty) error {
+	return m.addErr
+}
+
+func (m *MockDutyRepository) DeleteById(id string) error {
+	return m.deleteByIdErr
+}
+
+func (m *MockDutyRepository) UpdateById(duty *models.Duty) error {
+	return m.updateByIdErr
+}
+
+func (m *MockDutyRepository) SetStarted(id string, startedAt time.Time) error {
+	m.startedId = id
+	return m.setStartedErr
+}
+
+func (m *MockDutyRepository) GetByPeriod(from, to time.Time) ([]models.Duty, error) {
+	return m.getByPeriodResp, m.getByPeriodErr
+}
+
+func (m *MockDutyRepository) GetByDriverId(driverId string, from, to time.Time) ([]models.Duty, error) {
+	return m.getByDriverIdResp, m.getByDriverIdErr
+}
+
+func (m *MockDutyRepository) GetOverlapping(driverId, busId string, shiftStart, shiftEnd time.Time) ([]models.Duty, error) {
+	return m.getOverlappingResp, m.getOverlappingErr
+}
+
+type rosterFixture struct {
+	route          *models.Route
+	driver         *models.Driver
+	bus            *models.Bus
+	license        *models.DriverLicense
+	checks         []models.MedicalCheck
+	absences       []models.DriverAbsence
+	qualifications []models.DriverQualification
+}
+
+func newRosterFixture() rosterFixture {
+	driverID := uuid.New().String()
+	return rosterFixture{
+		route:          &models.Route{ID: uuid.New().String(), Number: "101"},
+		driver:         &models.Driver{ID: driverID, Name: "John", Surname: "Doe"},
+		bus:            &models.Bus{ID: uuid.New().String(), Brand: "ЛиАЗ", BusModel: "5292", VehicleClass: "D"},
+		license:        newTestLicense(driverID, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+		qualifications: []models.DriverQualification{{DriverID: driverID, Brand: "ЛиАЗ", BusModel: "5292"}},
+	}
+}
+
+func (f rosterFixture) duty() *models.Duty {
+	shiftStart := time.Date(2025, 3, 3, 6, 0, 0, 0, time.UTC)
+	return &models.Duty{
+		DriverID:     f.driver.ID,
+		BusID:        f.bus.ID,
+		RouteID:      f.route.ID,
+		ShiftStart:   shiftStart,
+		ShiftEnd:     shiftStart.Add(8 * time.Hour),
+		BreakMinutes: 45,
+	}
+}
+
+func (f rosterFixture) service(dutyRepo *MockDutyRepository) *RosterService {
+	routeRepo := &MockRouteRepository{
+		getByIdResp:           f.route,
+		getAllDriversByIdResp: []models.Driver{*f.driver},
+		getAllBusesByIdResp:   []models.Bus{*f.bus},
+	}
+	compliance := NewComplianceService(dutyRepo, DefaultWorkTimeRules())
+	licenses := NewLicenseService(&MockDriverLicenseRepository{getByDriverIdResp: f.license}, nil)
+	medical := NewMedicalCheckService(&MockMedicalCheckRepository{getByDriverIdResp: f.checks}, dutyRepo, nil)
+	absences := NewAbsenceService(&MockDriverAbsenceRepository{getOverlappingResp: f.absences}, nil, nil)
+	qualifications := NewQualificationService(&MockDriverQualificationRepository{getByBusModelResp: f.qualifications}, nil, nil)
+	return NewRosterService(dutyRepo, routeRepo, &MockDriverRepository{getByIdResp: f.driver}, &MockBusRepository{getByIdResp: f.bus}, compliance, licenses, medical, absences, qualifications)
+}
+
+func TestRosterService_Add(t *testing.T) {
+	f := newRosterFixture()
+
+	t.Run("Success", func(t *testing.T) {
+		service := f.service(&MockDutyRepository{})
+		duty := f.duty()
+
+		result, err := service.Add(duty)
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if result == nil || len(result.Violations) != 0 {
+			t.Errorf("Expected result without violations, got %v", result)
+		}
+		if !duty.Date.Equal(time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("Expected date to be derived from shift start, got %v", duty.Date)
+		}
+	})
+
+	t.Run("Shift end before start", func(t *testing.T) {
+		service := f.service(&MockDutyRepository{})
+		duty := f.duty()
+		duty.ShiftEnd = duty.ShiftStart.Add(-time.Hour)
+
+		_, err := service.Add(duty)
+		if err == nil || err.Error() != "Shift end must be after shift start" {
+			t.Errorf("Expected 'Shift end must be after shift start' error, got %v", err)
+		}
+	})
+
+	t.Run("Driver not assigned to route", func(t *testing.T) {
+		service := f.service(&MockDutyRepository{})
+		duty := f.duty()
+		duty.DriverID = uuid.New().String()
+
+		_, err := service.Add(duty)
+		if err == nil || err.Error() != "Driver is not assigned to this route" {
+			t.Errorf("Expected 'Driver is not assigned to this route' error, got %v", err)
+		}
+	})
+
+	t.Run("Bus not assigned to route", func(t *testing.T) {
+		service := f.service(&MockDutyRepository{})
+		duty := f.duty()
+		duty.BusID = uuid.New().String()
+
+		_, err := service.Add(duty)
+		if err == nil || err.Error() != "Bus is not assigned to this route" {
+			t.Errorf("Expected 'Bus is not assigned to this route' error, got %v", err)
+		}
+	})
+
+	t.Run("Driver double-booked", func(t *testing.T) {
+		other := f.duty()
+		other.ID = uuid.New().String()
+		other.BusID = uuid.New().String()
+		service := f.service(&MockDutyRepository{getOverlappingResp: []models.Duty{*other}})
+
+		_, err := service.Add(f.duty())
+		if err == nil || !strings.HasPrefix(err.Error(), "Driver is already on duty") {
+			t.Errorf("Expected driver double-booking error, got %v", err)
+		}
+	})
+
+	t.Run("Bus double-booked", func(t *testing.T) {
+		other := f.duty()
+		other.ID = uuid.New().String()
+		other.DriverID = uuid.New().String()
+		service := f.service(&MockDutyRepository{getOverlappingResp: []models.Duty{*other}})
+
+		_, err := service.Add(f.duty())
+		if err == nil || !strings.HasPrefix(err.Error(), "Bus is already on duty") {
+			t.Errorf("Expected bus double-booking error, got %v", err)
+		}
+	})
+
+	t.Run("Add with repo error", func(t *testing.T) {
+		service := f.service(&MockDutyRepository{addErr: errors.New("Database error")})
+
+		_, err := service.Add(f.duty())
+		if err == nil || err.Error() != "Database error" {
+			t.Errorf("Expected 'Database error', got %v", err)
+		}
+	})
+}
+
+func TestRosterService_AddCompliance(t *testing.T) {
+	f := newRosterFixture()
+	previous := f.duty()
+	previous.ID = uuid.New().String()
+	previous.Date = previous.Date.AddDate(0, 0, -1)
+	previous.ShiftStart = previous.ShiftStart.Add(-10 * time.Hour)
+	previous.ShiftEnd = previous.ShiftEnd.Add(-10 * time.Hour)
+
+	t.Run("Violation is rejected", func(t *testing.T) {
+		service := f.service(&MockDutyRepository{getByDriverIdResp: []models.Duty{*previous}})
+
+		_, err := service.Add(f.duty())
+		if err == nil || !strings.HasPrefix(err.Error(), "Duty violates working time rules") {
+			t.Errorf("Expected working time violation error, got %v", err)
+		}
+	})
+
+	t.Run("Violation is flagged with override", func(t *testing.T) {
+		service := f.service(&MockDutyRepository{getByDriverIdResp: []models.Duty{*previous}})
+		duty := f.duty()
+		duty.ComplianceOverride = true
+
+		result, err := service.Add(duty)
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if result == nil || len(result.Violations) != 1 || result.Violations[0].Rule != RuleMinDailyRest {
+			t.Errorf("Expected a daily rest violation, got %v", result)
+		}
+	})
+}
+
+func TestRosterService_AddLicense(t *testing.T) {
+	f := newRosterFixture()
+	f.bus.VehicleClass = "DE"
+
+	service := f.service(&MockDutyRepository{})
+
+	_, err := service.Add(f.duty())
+	if err == nil || err.Error() != "Driver has no valid license category for vehicle class DE" {
+		t.Errorf("Expected missing category error, got %v", err)
+	}
+}
+
+func TestRosterService_AddAbsence(t *testing.T) {
+	f := newRosterFixture()
+	f.absences = []models.DriverAbsence{{
+		DriverID: f.driver.ID,
+		Kind:     models.AbsenceSickLeave,
+		StartsOn: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		EndsOn:   time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC),
+	}}
+	service := f.service(&MockDutyRepository{})
+
+	_, err := service.Add(f.duty())
+	if err == nil || err.Error() != "Driver is on sick leave from 2025-03-01 to 2025-03-05" {
+		t.Errorf("Expected sick leave error, got %v", err)
+	}
+}
+
+func TestRosterService_AddQualification(t *testing.T) {
+	f := newRosterFixture()
+	f.qualifications = nil
+	service := f.service(&MockDutyRepository{})
+
+	result, err := service.Add(f.duty())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0] != "Driver is not qualified for bus model ЛиАЗ 5292" {
+		t.Errorf("Expected qualification warning, got %v", result.Warnings)
+	}
+}
+
+func TestRosterService_UpdateById(t *testing.T) {
+	f := newRosterFixture()
+
+	t.Run("Overlap with itself is ignored", func(t *testing.T) {
+		duty := f.duty()
+		duty.ID = uuid.New().String()
+		service := f.service(&MockDutyRepository{getByIdResp: duty, getOverlappingResp: []models.Duty{*duty}})
+
+		_, err := service.UpdateById(duty)
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Duty not found", func(t *testing.T) {
+		service := f.service(&MockDutyRepository{getByIdErr: errors.New("Duty not found")})
+
+		_, err := service.UpdateById(f.duty())
+		if err == nil || err.Error() != "Duty not found" {
+			t.Errorf("Expected 'Duty not found' error, got %v", err)
+		}
+	})
+}
+
+func TestRosterService_GetWeek(t *testing.T) {
+	f := newRosterFixture()
+	monday := f.duty()
+	monday.Date = time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	sunday := f.duty()
+	sunday.Date = time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	service := f.service(&MockDutyRepository{getByPeriodResp: []models.Duty{*monday, *sunday}})
+
+	week, err := service.GetWeek(time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(week) != 7 {
+		t.Fatalf("Expected 7 days, got %d", len(week))
+	}
+	if !week[0].Date.Equal(monday.Date) {
+		t.Errorf("Expected week to start on %v, got %v", monday.Date, week[0].Date)
+	}
+	if len(week[0].Duties) != 1 || len(week[6].Duties) != 1 || len(week[3].Duties) != 0 {
+		t.Errorf("Duties are grouped incorrectly: %v", week)
+	}
+}
+
+func TestRosterService_GetDay(t *testing.T) {
+	f := newRosterFixture()
+
+	t.Run("Success", func(t *testing.T) {
+		service := f.service(&MockDutyRepository{getByPeriodResp: []models.Duty{*f.duty()}})
+
+		day, err := service.GetDay(time.Date(2025, 3, 3, 15, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if day == nil || len(day.Duties) != 1 || day.Date.Hour() != 0 {
+			t.Errorf("Unexpected day roster: %v", day)
+		}
+	})
+
+	t.Run("Repo error", func(t *testing.T) {
+		service := f.service(&MockDutyRepository{getByPeriodErr: errors.New("Database error")})
+
+		_, err := service.GetDay(time.Now())
+		if err == nil || err.Error() != "Database error" {
+			t.Errorf("Expected 'Database error', got %v", err)
+		}
+	})
+}
+
+func TestRosterService_StartDuty(t *testing.T) {
+	f := newRosterFixture()
+	duty := f.duty()
+	duty.ID = uuid.New().String()
+	duty.Date = time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	at := duty.ShiftStart.Add(-10 * time.Minute)
+	passed := models.MedicalCheck{DriverID: f.driver.ID, CheckedAt: at.Add(-20 * time.Minute), Passed: true}
+	failed := models.MedicalCheck{DriverID: f.driver.ID, CheckedAt: at.Add(-5 * time.Minute), Passed: false}
+	scheduled := func() *models.Duty {
+		copied := *duty
+		return &copied
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		f.checks = []models.MedicalCheck{passed}
+		dutyRepo := &MockDutyRepository{getByIdResp: scheduled()}
+		service := f.service(dutyRepo)
+
+		result, err := service.StartDuty(duty.ID, at)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.StartedAt == nil || !result.StartedAt.Equal(at) {
+			t.Errorf("Expected duty started at %v, got %v", at, result.StartedAt)
+		}
+		if dutyRepo.startedId != duty.ID {
+			t.Errorf("Expected duty %s to be marked as started, got '%s'", duty.ID, dutyRepo.startedId)
+		}
+	})
+
+	t.Run("No medical check", func(t *testing.T) {
+		f.checks = nil
+		dutyRepo := &MockDutyRepository{getByIdResp: scheduled()}
+		service := f.service(dutyRepo)
+
+		_, err := service.StartDuty(duty.ID, at)
+		if err == nil || err.Error() != "Driver has no medical check today" {
+			t.Errorf("Expected 'Driver has no medical check today' error, got %v", err)
+		}
+		if dutyRepo.startedId != "" {
+			t.Error("Expected duty not to be started")
+		}
+	})
+
+	t.Run("Latest medical check failed", func(t *testing.T) {
+		f.checks = []models.MedicalCheck{passed, failed}
+		service := f.service(&MockDutyRepository{getByIdResp: scheduled()})
+
+		_, err := service.StartDuty(duty.ID, at)
+		if err == nil || !strings.HasPrefix(err.Error(), "Driver failed the medical check") {
+			t.Errorf("Expected 'Driver failed the medical check' error, got %v", err)
+		}
+	})
+
+	t.Run("Not scheduled for today", func(t *testing.T) {
+		f.checks = []models.MedicalCheck{passed}
+		service := f.service(&MockDutyRepository{getByIdResp: scheduled()})
+
+		_, err := service.StartDuty(duty.ID, at.AddDate(0, 0, 1))
+		if err == nil || err.Error() != "Duty is not scheduled for today" {
+			t.Errorf("Expected 'Duty is not scheduled for today' error, got %v", err)
+		}
+	})
+
+	t.Run("Already started", func(t *testing.T) {
+		f.checks = []models.MedicalCheck{passed}
+		started := *duty
+		started.StartedAt = &at
+		service := f.service(&MockDutyRepository{getByIdResp: &started})
+
+		_, err := service.StartDuty(duty.ID, at)
+		if err == nil || err.Error() != "Duty is already started" {
+			t.Errorf("Expected 'Duty is already started' error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/repository"
+	"sort"
+	"sync"
+	"time"
+)
+
+// placementTTL is how long telemetry ingestion reuses the bus placement
+// before it reads the route assignments again.
+const placementTTL = time.Minute
+
+// routeNetwork caches the routes, their stops and travel times for the
+// duration of one request.
+type routeNetwork struct {
+	routeRepo   repository.IRouteRepository
+	segmentRepo repository.ISegmentRepository
+	routes      []models.Route
+	busRoutes   map[string]string
+	stops       map[string][]models.BusStop
+	times       map[string]segmentTimes
+}
+
+// loadRouteNetwork places every bus on the route it is assigned to at the
+// given time. A bus assigned to several routes is placed on the one with the
+// lowest number.
+func loadRouteNetwork(routeRepo repository.IRouteRepository, segmentRepo repository.ISegmentRepository, at time.Time) (*routeNetwork, error) {
+	routes, err := routeRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].Number < routes[j].Number })
+	busRoutes := make(map[string]string)
+	for _, route := range routes {
+		routeBuses, err := routeRepo.GetAllBusesAt(route.ID, at)
+		if err != nil {
+			return nil, err
+		}
+		for _, bus := range routeBuses {
+			if _, ok := busRoutes[bus.ID]; !ok {
+				busRoutes[bus.ID] = route.ID
+			}
+		}
+	}
+	return &routeNetwork{
+		routeRepo:   routeRepo,
+		segmentRepo: segmentRepo,
+		routes:      routes,
+		busRoutes:   busRoutes,
+		stops:       make(map[string][]models.BusStop),
+		times:       make(map[string]segmentTimes),
+	}, nil
+}
+
+// placementCache keeps the bus placement between position batches, so that
+// on-board units reporting every few seconds don't reload every route.
+type placementCache struct {
+	mu       sync.Mutex
+	network  *routeNetwork
+	loadedAt time.Time
+}
+
+// load returns the cached placement while it is fresh. Stops and travel
+// times are cached per call only, as the returned network isn't shared.
+func (c *placementCache) load(routeRepo repository.IRouteRepository, segmentRepo repository.ISegmentRepository, now time.Time) (*routeNetwork, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.network == nil || now.Before(c.loadedAt) || now.Sub(c.loadedAt) >= placementTTL {
+		network, err := loadRouteNetwork(routeRepo, segmentRepo, now)
+		if err != nil {
+			return nil, err
+		}
+		c.network = network
+		c.loadedAt = now
+	}
+	network := *c.network
+	network.stops = make(map[string][]models.BusStop)
+	network.times = make(map[string]segmentTimes)
+	return &network, nil
+}
+
+func (n *routeNetwork) Stops(routeId string) ([]models.BusStop, error) {
+	stops, ok := n.stops[routeId]
+	if !ok {
+		var err error
+		stops, err = n.routeRepo.GetAllBusStopsById(routeId)
+		if err != nil {
+			return nil, err
+		}
+		n.stops[routeId] = stops
+	}
+	return stops, nil
+}
+
+func (n *routeNetwork) TravelTimes(routeId string) (segmentTimes, error) {
+	times, ok := n.times[routeId]
+	if !ok {
+		travelTimes, err := n.segmentRepo.GetTravelTimes(routeId)
+		if err != nil {
+			return nil, err
+		}
+		times = newSegmentTimes(travelTimes)
+		n.times[routeId] = times
+	}
+	return times, nil
+}
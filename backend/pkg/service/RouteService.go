@@ -3,15 +3,21 @@ package service
 import (
 	"backend/pkg/models"
 	"backend/pkg/repository"
+	"fmt"
+	"time"
 
 	"errors"
 )
 
 type RouteService struct {
-	repo        repository.IRouteRepository
-	driverRepo  repository.IDriverRepository
-	busRepo     repository.IBusRepository
-	busStopRepo repository.IBusStopRepository
+	repo           repository.IRouteRepository
+	driverRepo     repository.IDriverRepository
+	busRepo        repository.IBusRepository
+	busStopRepo    repository.IBusStopRepository
+	licenses       ILicenseService
+	absences       IAbsenceService
+	qualifications IQualificationService
+	repairRepo     repository.IBusRepairRepository
 }
 
 func NewRouteService(
@@ -19,8 +25,12 @@ func NewRouteService(
 	driverRepo repository.IDriverRepository,
 	busRepo repository.IBusRepository,
 	busStopRepo repository.IBusStopRepository,
+	licenses ILicenseService,
+	absences IAbsenceService,
+	qualifications IQualificationService,
+	repairRepo repository.IBusRepairRepository,
 ) *RouteService {
-	b := &RouteService{r, driverRepo, busRepo, busStopRepo}
+	b := &RouteService{r, driverRepo, busRepo, busStopRepo, licenses, absences, qualifications, repairRepo}
 	return b
 }
 
@@ -72,7 +82,11 @@ func (rs RouteService) UpdateById(route *models.Route) error {
 	return err
 }
 
-func (rs RouteService) AssignDriver(routeId, driverId string) error {
+// AssignDriver puts the driver on the route. The route keeps no period for
+// its drivers, so the caller names the days the driver is meant to work it,
+// today if from is nil and a single day if to is nil; the driver must not be
+// absent on any of them.
+func (rs RouteService) AssignDriver(routeId, driverId string, from, to *time.Time) error {
 	route, err := rs.GetById(routeId)
 	if route == nil {
 		return errors.New("Route not found")
@@ -88,6 +102,25 @@ func (rs RouteService) AssignDriver(routeId, driverId string) error {
 	if err != nil {
 		return err
 	}
+	err = rs.checkDriverLicense(routeId, driverId)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	if from != nil {
+		start = *from
+	}
+	end := start
+	if to != nil {
+		end = *to
+	}
+	if end.Before(start) {
+		return errors.New("Assignment can't end before it starts")
+	}
+	err = rs.absences.CheckAvailable(driverId, start, end)
+	if err != nil {
+		return err
+	}
 	err = rs.repo.AssignDriver(routeId, driverId)
 	if err != nil {
 		return err
@@ -134,6 +167,13 @@ func (rs RouteService) AssignBus(routeId, busId string) error {
 	if err != nil {
 		return err
 	}
+	conflicts, err := rs.findBusConflicts(models.BusAssignment{RouteID: routeId, BusID: busId, StartsAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return busConflictError(conflicts[0])
+	}
 	err = rs.repo.AssignBus(routeId, busId)
 	if err != nil {
 		return err
@@ -187,7 +227,9 @@ func (rs RouteService) UnassignBusStop(routeId, busStopId string) error {
 	return nil
 }
 
-func (rs RouteService) UnassignBus(routeId, busId string) error {
+// UnassignBus removes one assignment period of the bus on the route, the one
+// starting at startsAt or, without it, the one in effect now.
+func (rs RouteService) UnassignBus(routeId, busId string, startsAt *time.Time) error {
 	route, err := rs.GetById(routeId)
 	if route == nil {
 		return errors.New("Route not found")
@@ -203,13 +245,33 @@ func (rs RouteService) UnassignBus(routeId, busId string) error {
 	if err != nil {
 		return err
 	}
-	err = rs.repo.UnassignBus(routeId, busId)
+	if startsAt == nil {
+		current, err := rs.currentBusAssignment(routeId, busId, time.Now())
+		if err != nil {
+			return err
+		}
+		startsAt = &current.StartsAt
+	}
+	err = rs.repo.UnassignBus(routeId, busId, *startsAt)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+func (rs RouteService) currentBusAssignment(routeId, busId string, at time.Time) (*models.BusAssignment, error) {
+	assignments, err := rs.repo.GetBusAssignments(busId)
+	if err != nil {
+		return nil, err
+	}
+	for _, assignment := range assignments {
+		if assignment.RouteID == routeId && !assignment.StartsAt.After(at) && (assignment.EndsAt == nil || assignment.EndsAt.After(at)) {
+			return &assignment, nil
+		}
+	}
+	return nil, errors.New("Bus has no current assignment on this route")
+}
+
 func (rs RouteService) GetAllDriversById(routeId string) ([]models.Driver, error) {
 	route, err := rs.GetById(routeId)
 	if route == nil {
@@ -257,7 +319,7 @@ func (rs RouteService) GetAllBusesById(routeId string) ([]models.Bus, error) {
 		return nil, err
 	}
 
-	buses, err := rs.repo.GetAllBusesById(routeId)
+	buses, err := rs.repo.GetAllBusesAt(routeId, time.Now())
 	if err != nil {
 		return nil, err
 	}
@@ -266,3 +328,141 @@ func (rs RouteService) GetAllBusesById(routeId string) ([]models.Bus, error) {
 	}
 	return buses, nil
 }
+
+// AssignBusForPeriod assigns a bus to a route for a time window. An open
+// EndsAt means the assignment has no end. A bus in repair can't be assigned. Overlapping assignments of the same
+// bus on other routes are rejected unless force is set, in which case the
+// assignment is stored and the conflicts are returned to the caller.
+func (rs RouteService) AssignBusForPeriod(assignment *models.BusAssignment, force bool) (*models.BusAssignmentResult, error) {
+	if assignment.EndsAt != nil && !assignment.EndsAt.After(assignment.StartsAt) {
+		return nil, errors.New("Assignment end must be after its start")
+	}
+	route, err := rs.GetById(assignment.RouteID)
+	if route == nil {
+		return nil, errors.New("Route not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	bus, err := rs.busRepo.GetById(assignment.BusID)
+	if bus == nil {
+		return nil, errors.New("Bus not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	repair, err := rs.repairRepo.GetOpen(bus.ID)
+	if err != nil && err.Error() != "Repair not found" {
+		return nil, err
+	}
+	if repair != nil {
+		return nil, errors.New("Bus is in repair")
+	}
+	conflicts, err := rs.findBusConflicts(*assignment)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 && !force {
+		return nil, busConflictError(conflicts[0])
+	}
+	warnings, err := rs.checkDriversQualified(assignment.RouteID, *bus)
+	if err != nil {
+		return nil, err
+	}
+	err = rs.repo.AssignBusForPeriod(assignment, force)
+	if err != nil {
+		return nil, err
+	}
+	return &models.BusAssignmentResult{Assignment: *assignment, Conflicts: conflicts, Warnings: warnings}, nil
+}
+
+func (rs RouteService) GetBusConflicts() ([]models.BusConflict, error) {
+	conflicts, err := rs.repo.GetBusConflicts()
+	if err != nil {
+		return nil, err
+	}
+	return conflicts, nil
+}
+
+// checkDriverLicense requires a valid licence category for every vehicle class
+// currently serving the route, or just a valid licence if the route has no buses.
+func (rs RouteService) checkDriverLicense(routeId, driverId string) error {
+	now := time.Now()
+	buses, err := rs.repo.GetAllBusesAt(routeId, now)
+	if err != nil {
+		return err
+	}
+	if len(buses) == 0 {
+		return rs.licenses.CheckDriverForVehicleClass(driverId, "", now)
+	}
+	checked := map[string]bool{}
+	for _, bus := range buses {
+		if checked[bus.VehicleClass] {
+			continue
+		}
+		checked[bus.VehicleClass] = true
+		err = rs.licenses.CheckDriverForVehicleClass(driverId, bus.VehicleClass, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rs RouteService) checkDriversQualified(routeId string, bus models.Bus) ([]string, error) {
+	return checkDriversQualified(rs.repo, rs.qualifications, routeId, bus)
+}
+
+// checkDriversQualified warns when none of the route's drivers is trained
+// on the model of the bus.
+func checkDriversQualified(routeRepo repository.IRouteRepository, qualifications IQualificationService, routeId string, bus models.Bus) ([]string, error) {
+	drivers, err := routeRepo.GetAllDriversById(routeId)
+	if err != nil {
+		return nil, err
+	}
+	qualified, err := qualifications.QualifiedDrivers(bus)
+	if err != nil {
+		return nil, err
+	}
+	for _, driver := range drivers {
+		if qualified[driver.ID] {
+			return nil, nil
+		}
+	}
+	return []string{fmt.Sprintf("None of the route drivers is qualified for bus model %s", busModelName(bus))}, nil
+}
+
+func (rs RouteService) findBusConflicts(candidate models.BusAssignment) ([]models.BusConflict, error) {
+	assignments, err := rs.repo.GetBusAssignments(candidate.BusID)
+	if err != nil {
+		return nil, err
+	}
+	var conflicts []models.BusConflict
+	for _, assignment := range assignments {
+		if !assignmentsOverlap(assignment, candidate) {
+			continue
+		}
+		if assignment.RouteID == candidate.RouteID {
+			return nil, errors.New("Bus is already assigned to this route for an overlapping period")
+		}
+		conflicts = append(conflicts, models.BusConflict{
+			BusID:  candidate.BusID,
+			First:  assignment,
+			Second: candidate,
+		})
+	}
+	return conflicts, nil
+}
+
+// assignmentsOverlap treats a missing EndsAt as an assignment that never ends.
+func assignmentsOverlap(a, b models.BusAssignment) bool {
+	aStartsBeforeBEnds := b.EndsAt == nil || a.StartsAt.Before(*b.EndsAt)
+	bStartsBeforeAEnds := a.EndsAt == nil || b.StartsAt.Before(*a.EndsAt)
+	return aStartsBeforeBEnds && bStartsBeforeAEnds
+}
+
+func busConflictError(conflict models.BusConflict) error {
+	return fmt.Errorf("Bus is already assigned to route %s from %s",
+		conflict.First.RouteID, conflict.First.StartsAt.Format(time.RFC3339))
+}
@@ -4,6 +4,7 @@ import (
 	"backend/pkg/models"
 	"errors"
 	"github.com/google/uuid"
+	"strings"
 	"testing"
 	"time"
 )
@@ -30,6 +31,12 @@ type MockRouteRepository struct {
 	getAllBusStopsByIdErr  error
 	getAllBusesByIdResp    []models.Bus
 	getAllBusesByIdErr     error
+	assignBusForPeriodErr  error
+	getBusAssignmentsResp  []models.BusAssignment
+	getBusAssignmentsErr   error
+	busAssignments         map[string][]models.BusAssignment
+	getBusConflictsResp    []models.BusConflict
+	getBusConflictsErr     error
 }
 
 func (m *MockRouteRepository) GetById(id string) (*models.Route, error) {
@@ -72,7 +79,7 @@ func (m *MockRouteRepository) UnassignBusStop(routeId, busStopId string) error {
 	return m.unassignBusStopErr
 }
 
-func (m *MockRouteRepository) UnassignBus(routeId, busId string) error {
+func (m *MockRouteRepository) UnassignBus(routeId, busId string, startsAt time.Time) error {
 	return m.unassignBusErr
 }
 
@@ -88,10 +95,25 @@ func (m *MockRouteRepository) GetAllBusStopsById(routeId string) ([]models.BusSt
 	return m.getAllBusStopsByIdResp, m.getAllBusStopsByIdErr
 }
 
-func (m *MockRouteRepository) GetAllBusesById(routeId string) ([]models.Bus, error) {
+func (m *MockRouteRepository) GetAllBusesAt(routeId string, at time.Time) ([]models.Bus, error) {
 	return m.getAllBusesByIdResp, m.getAllBusesByIdErr
 }
 
+func (m *MockRouteRepository) AssignBusForPeriod(assignment *models.BusAssignment, force bool) error {
+	return m.assignBusForPeriodErr
+}
+
+func (m *MockRouteRepository) GetBusAssignments(busId string) ([]models.BusAssignment, error) {
+	if m.busAssignments != nil {
+		return m.busAssignments[busId], m.getBusAssignmentsErr
+	}
+	return m.getBusAssignmentsResp, m.getBusAssignmentsErr
+}
+
+func (m *MockRouteRepository) GetBusConflicts() ([]models.BusConflict, error) {
+	return m.getBusConflictsResp, m.getBusConflictsErr
+}
+
 type MockBusRepository struct {
 	getByIdResp     *models.Bus
 	getByIdErr      error
@@ -132,7 +154,7 @@ func TestRouteService_GetById(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := &MockRouteRepository{getByIdResp: route}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		result, err := service.GetById(route.ID)
 		if err != nil {
@@ -145,7 +167,7 @@ func TestRouteService_GetById(t *testing.T) {
 
 	t.Run("Route not found", func(t *testing.T) {
 		mockRepo := &MockRouteRepository{getByIdErr: errors.New("Route not found")}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		_, err := service.GetById(uuid.New().String())
 		if err == nil || err.Error() != "Route not found" {
@@ -159,7 +181,7 @@ func TestRouteService_GetByNumber(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := &MockRouteRepository{getByNumberResp: route}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		result, err := service.GetByNumber("101")
 		if err != nil {
@@ -172,7 +194,7 @@ func TestRouteService_GetByNumber(t *testing.T) {
 
 	t.Run("Route not found", func(t *testing.T) {
 		mockRepo := &MockRouteRepository{getByNumberErr: errors.New("Route not found")}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		_, err := service.GetByNumber("999")
 		if err == nil || err.Error() != "Route not found" {
@@ -186,7 +208,7 @@ func TestRouteService_Add(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := &MockRouteRepository{}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		err := service.Add(route)
 		if err != nil {
@@ -196,7 +218,7 @@ func TestRouteService_Add(t *testing.T) {
 
 	t.Run("Add with error", func(t *testing.T) {
 		mockRepo := &MockRouteRepository{addErr: errors.New("Database error")}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		err := service.Add(route)
 		if err == nil || err.Error() != "Database error" {
@@ -211,7 +233,7 @@ func TestRouteService_GetAll(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := &MockRouteRepository{getAllResp: []models.Route{route1, route2}}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		routes, _ := service.GetAll()
 		if len(routes) != 2 {
@@ -221,7 +243,7 @@ func TestRouteService_GetAll(t *testing.T) {
 
 	t.Run("Empty result", func(t *testing.T) {
 		mockRepo := &MockRouteRepository{getAllResp: []models.Route{}}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		routes, _ := service.GetAll()
 		if len(routes) != 0 {
@@ -233,7 +255,7 @@ func TestRouteService_GetAll(t *testing.T) {
 func TestRouteService_DeleteById(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := &MockRouteRepository{}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		err := service.DeleteById(uuid.New().String())
 		if err != nil {
@@ -243,7 +265,7 @@ func TestRouteService_DeleteById(t *testing.T) {
 
 	t.Run("Delete with error", func(t *testing.T) {
 		mockRepo := &MockRouteRepository{deleteByIdErr: errors.New("Database error")}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		err := service.DeleteById(uuid.New().String())
 		if err == nil || err.Error() != "Database error" {
@@ -257,7 +279,7 @@ func TestRouteService_UpdateById(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := &MockRouteRepository{}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		err := service.UpdateById(route)
 		if err != nil {
@@ -267,7 +289,7 @@ func TestRouteService_UpdateById(t *testing.T) {
 
 	t.Run("Update with error", func(t *testing.T) {
 		mockRepo := &MockRouteRepository{updateByIdErr: errors.New("Database error")}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		err := service.UpdateById(route)
 		if err == nil || err.Error() != "Database error" {
@@ -282,13 +304,15 @@ func TestRouteService_AssignDriver(t *testing.T) {
 	route := &models.Route{ID: routeID, Number: "101"}
 	birthDate := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
 	driver := &models.Driver{ID: driverID, Name: "John", Surname: "Doe", BirthDate: birthDate}
+	licenses := NewLicenseService(&MockDriverLicenseRepository{getByDriverIdResp: newTestLicense(driverID, time.Now())}, nil)
+	absences := NewAbsenceService(&MockDriverAbsenceRepository{}, nil, nil)
 
 	t.Run("Success", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdResp: route}
 		mockDriverRepo := &MockDriverRepository{getByIdResp: driver}
-		service := NewRouteService(mockRouteRepo, mockDriverRepo, nil, nil)
+		service := NewRouteService(mockRouteRepo, mockDriverRepo, nil, nil, licenses, absences, nil, nil)
 
-		err := service.AssignDriver(routeID, driverID)
+		err := service.AssignDriver(routeID, driverID, nil, nil)
 		if err != nil {
 			t.Errorf("Expected no error, got %v", err)
 		}
@@ -297,9 +321,9 @@ func TestRouteService_AssignDriver(t *testing.T) {
 	t.Run("Route not found", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdErr: errors.New("Route not found")}
 		mockDriverRepo := &MockDriverRepository{getByIdResp: driver}
-		service := NewRouteService(mockRouteRepo, mockDriverRepo, nil, nil)
+		service := NewRouteService(mockRouteRepo, mockDriverRepo, nil, nil, licenses, absences, nil, nil)
 
-		err := service.AssignDriver(uuid.New().String(), driverID)
+		err := service.AssignDriver(uuid.New().String(), driverID, nil, nil)
 		if err == nil || err.Error() != "Route not found" {
 			t.Errorf("Expected 'Route not found' error, got %v", err)
 		}
@@ -308,9 +332,9 @@ func TestRouteService_AssignDriver(t *testing.T) {
 	t.Run("Driver not found", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdResp: route}
 		mockDriverRepo := &MockDriverRepository{getByIdErr: errors.New("Driver not found")}
-		service := NewRouteService(mockRouteRepo, mockDriverRepo, nil, nil)
+		service := NewRouteService(mockRouteRepo, mockDriverRepo, nil, nil, licenses, absences, nil, nil)
 
-		err := service.AssignDriver(routeID, uuid.New().String())
+		err := service.AssignDriver(routeID, uuid.New().String(), nil, nil)
 		if err == nil || err.Error() != "Driver not found" {
 			t.Errorf("Expected 'Driver not found' error, got %v", err)
 		}
@@ -319,13 +343,70 @@ func TestRouteService_AssignDriver(t *testing.T) {
 	t.Run("Assign driver with repo error", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdResp: route, assignDriverErr: errors.New("Database error")}
 		mockDriverRepo := &MockDriverRepository{getByIdResp: driver}
-		service := NewRouteService(mockRouteRepo, mockDriverRepo, nil, nil)
+		service := NewRouteService(mockRouteRepo, mockDriverRepo, nil, nil, licenses, absences, nil, nil)
 
-		err := service.AssignDriver(routeID, driverID)
+		err := service.AssignDriver(routeID, driverID, nil, nil)
 		if err == nil || err.Error() != "Database error" {
 			t.Errorf("Expected 'Database error', got %v", err)
 		}
 	})
+
+	t.Run("Expired license", func(t *testing.T) {
+		mockRouteRepo := &MockRouteRepository{getByIdResp: route}
+		mockDriverRepo := &MockDriverRepository{getByIdResp: driver}
+		expired := NewLicenseService(&MockDriverLicenseRepository{getByDriverIdResp: newTestLicense(driverID, time.Now().AddDate(-10, 0, -1))}, nil)
+		service := NewRouteService(mockRouteRepo, mockDriverRepo, nil, nil, expired, absences, nil, nil)
+
+		err := service.AssignDriver(routeID, driverID, nil, nil)
+		if err == nil || !strings.HasPrefix(err.Error(), "Driver license expired") {
+			t.Errorf("Expected expired license error, got %v", err)
+		}
+	})
+
+	t.Run("Missing category for route bus", func(t *testing.T) {
+		mockRouteRepo := &MockRouteRepository{getByIdResp: route, getAllBusesByIdResp: []models.Bus{{ID: uuid.New().String(), VehicleClass: "DE"}}}
+		mockDriverRepo := &MockDriverRepository{getByIdResp: driver}
+		service := NewRouteService(mockRouteRepo, mockDriverRepo, nil, nil, licenses, absences, nil, nil)
+
+		err := service.AssignDriver(routeID, driverID, nil, nil)
+		if err == nil || err.Error() != "Driver has no valid license category for vehicle class DE" {
+			t.Errorf("Expected missing category error, got %v", err)
+		}
+	})
+
+	t.Run("Driver on vacation", func(t *testing.T) {
+		mockRouteRepo := &MockRouteRepository{getByIdResp: route}
+		mockDriverRepo := &MockDriverRepository{getByIdResp: driver}
+		vacation := models.DriverAbsence{DriverID: driverID, Kind: models.AbsenceVacation, StartsOn: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), EndsOn: time.Date(2025, 7, 14, 0, 0, 0, 0, time.UTC)}
+		onVacation := NewAbsenceService(&MockDriverAbsenceRepository{getOverlappingResp: []models.DriverAbsence{vacation}}, nil, nil)
+		service := NewRouteService(mockRouteRepo, mockDriverRepo, nil, nil, licenses, onVacation, nil, nil)
+
+		err := service.AssignDriver(routeID, driverID, nil, nil)
+		if err == nil || err.Error() != "Driver is on vacation from 2025-07-01 to 2025-07-14" {
+			t.Errorf("Expected 'Driver is on vacation from 2025-07-01 to 2025-07-14' error, got %v", err)
+		}
+	})
+	t.Run("Absences are checked over the whole period", func(t *testing.T) {
+		mockRouteRepo := &MockRouteRepository{getByIdResp: route}
+		mockDriverRepo := &MockDriverRepository{getByIdResp: driver}
+		absenceRepo := &MockDriverAbsenceRepository{}
+		service := NewRouteService(mockRouteRepo, mockDriverRepo, nil, nil, licenses, NewAbsenceService(absenceRepo, nil, nil), nil, nil)
+		from := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+
+		err := service.AssignDriver(routeID, driverID, &from, &to)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !absenceRepo.overlappingFrom.Equal(from) || !absenceRepo.overlappingTo.Equal(to) {
+			t.Errorf("Expected absences checked from %v to %v, got %v to %v", from, to, absenceRepo.overlappingFrom, absenceRepo.overlappingTo)
+		}
+
+		err = service.AssignDriver(routeID, driverID, &to, &from)
+		if err == nil || err.Error() != "Assignment can't end before it starts" {
+			t.Errorf("Expected 'Assignment can't end before it starts' error, got %v", err)
+		}
+	})
 }
 
 func TestRouteService_AssignBusStop(t *testing.T) {
@@ -337,7 +418,7 @@ func TestRouteService_AssignBusStop(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdResp: route}
 		mockBusStopRepo := &MockBusStopRepository{getByIdResp: busStop}
-		service := NewRouteService(mockRouteRepo, nil, nil, mockBusStopRepo)
+		service := NewRouteService(mockRouteRepo, nil, nil, mockBusStopRepo, nil, nil, nil, nil)
 
 		err := service.AssignBusStop(routeID, busStopID)
 		if err != nil {
@@ -348,7 +429,7 @@ func TestRouteService_AssignBusStop(t *testing.T) {
 	t.Run("Route not found", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdErr: errors.New("Route not found")}
 		mockBusStopRepo := &MockBusStopRepository{getByIdResp: busStop}
-		service := NewRouteService(mockRouteRepo, nil, nil, mockBusStopRepo)
+		service := NewRouteService(mockRouteRepo, nil, nil, mockBusStopRepo, nil, nil, nil, nil)
 
 		err := service.AssignBusStop(uuid.New().String(), busStopID)
 		if err == nil || err.Error() != "Route not found" {
@@ -359,7 +440,7 @@ func TestRouteService_AssignBusStop(t *testing.T) {
 	t.Run("Bus stop not found", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdResp: route}
 		mockBusStopRepo := &MockBusStopRepository{getByIdErr: errors.New("Bus stop not found")}
-		service := NewRouteService(mockRouteRepo, nil, nil, mockBusStopRepo)
+		service := NewRouteService(mockRouteRepo, nil, nil, mockBusStopRepo, nil, nil, nil, nil)
 
 		err := service.AssignBusStop(routeID, uuid.New().String())
 		if err == nil || err.Error() != "Bus stop not found" {
@@ -370,7 +451,7 @@ func TestRouteService_AssignBusStop(t *testing.T) {
 	t.Run("Assign bus stop with repo error", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdResp: route, assignBusStopErr: errors.New("Database error")}
 		mockBusStopRepo := &MockBusStopRepository{getByIdResp: busStop}
-		service := NewRouteService(mockRouteRepo, nil, nil, mockBusStopRepo)
+		service := NewRouteService(mockRouteRepo, nil, nil, mockBusStopRepo, nil, nil, nil, nil)
 
 		err := service.AssignBusStop(routeID, busStopID)
 		if err == nil || err.Error() != "Database error" {
@@ -395,7 +476,7 @@ func TestRouteService_AssignBus(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdResp: route}
 		mockBusRepo := &MockBusRepository{getByIdResp: bus}
-		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil)
+		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil, nil, nil, nil, nil)
 
 		err := service.AssignBus(routeID, busID)
 		if err != nil {
@@ -406,7 +487,7 @@ func TestRouteService_AssignBus(t *testing.T) {
 	t.Run("Route not found", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdErr: errors.New("Route not found")}
 		mockBusRepo := &MockBusRepository{getByIdResp: bus}
-		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil)
+		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil, nil, nil, nil, nil)
 
 		err := service.AssignBus(uuid.New().String(), busID)
 		if err == nil || err.Error() != "Route not found" {
@@ -417,7 +498,7 @@ func TestRouteService_AssignBus(t *testing.T) {
 	t.Run("Bus not found", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdResp: route}
 		mockBusRepo := &MockBusRepository{getByIdErr: errors.New("Bus not found")}
-		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil)
+		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil, nil, nil, nil, nil)
 
 		err := service.AssignBus(routeID, uuid.New().String())
 		if err == nil || err.Error() != "Bus not found" {
@@ -428,7 +509,7 @@ func TestRouteService_AssignBus(t *testing.T) {
 	t.Run("Assign bus with repo error", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdResp: route, assignBusErr: errors.New("Database error")}
 		mockBusRepo := &MockBusRepository{getByIdResp: bus}
-		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil)
+		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil, nil, nil, nil, nil)
 
 		err := service.AssignBus(routeID, busID)
 		if err == nil || err.Error() != "Database error" {
@@ -447,7 +528,7 @@ func TestRouteService_UnassignDriver(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdResp: route}
 		mockDriverRepo := &MockDriverRepository{getByIdResp: driver}
-		service := NewRouteService(mockRouteRepo, mockDriverRepo, nil, nil)
+		service := NewRouteService(mockRouteRepo, mockDriverRepo, nil, nil, nil, nil, nil, nil)
 
 		err := service.UnassignDriver(routeID, driverID)
 		if err != nil {
@@ -458,7 +539,7 @@ func TestRouteService_UnassignDriver(t *testing.T) {
 	t.Run("Route not found", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdErr: errors.New("Route not found")}
 		mockDriverRepo := &MockDriverRepository{getByIdResp: driver}
-		service := NewRouteService(mockRouteRepo, mockDriverRepo, nil, nil)
+		service := NewRouteService(mockRouteRepo, mockDriverRepo, nil, nil, nil, nil, nil, nil)
 
 		err := service.UnassignDriver(uuid.New().String(), driverID)
 		if err == nil || err.Error() != "Route not found" {
@@ -469,7 +550,7 @@ func TestRouteService_UnassignDriver(t *testing.T) {
 	t.Run("Driver not found", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdResp: route}
 		mockDriverRepo := &MockDriverRepository{getByIdErr: errors.New("Driver not found")}
-		service := NewRouteService(mockRouteRepo, mockDriverRepo, nil, nil)
+		service := NewRouteService(mockRouteRepo, mockDriverRepo, nil, nil, nil, nil, nil, nil)
 
 		err := service.UnassignDriver(routeID, uuid.New().String())
 		if err == nil || err.Error() != "Driver not found" {
@@ -480,7 +561,7 @@ func TestRouteService_UnassignDriver(t *testing.T) {
 	t.Run("Unassign driver with repo error", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdResp: route, unassignDriverErr: errors.New("Database error")}
 		mockDriverRepo := &MockDriverRepository{getByIdResp: driver}
-		service := NewRouteService(mockRouteRepo, mockDriverRepo, nil, nil)
+		service := NewRouteService(mockRouteRepo, mockDriverRepo, nil, nil, nil, nil, nil, nil)
 
 		err := service.UnassignDriver(routeID, driverID)
 		if err == nil || err.Error() != "Database error" {
@@ -498,7 +579,7 @@ func TestRouteService_UnassignBusStop(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdResp: route}
 		mockBusStopRepo := &MockBusStopRepository{getByIdResp: busStop}
-		service := NewRouteService(mockRouteRepo, nil, nil, mockBusStopRepo)
+		service := NewRouteService(mockRouteRepo, nil, nil, mockBusStopRepo, nil, nil, nil, nil)
 
 		err := service.UnassignBusStop(routeID, busStopID)
 		if err != nil {
@@ -509,7 +590,7 @@ func TestRouteService_UnassignBusStop(t *testing.T) {
 	t.Run("Route not found", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdErr: errors.New("Route not found")}
 		mockBusStopRepo := &MockBusStopRepository{getByIdResp: busStop}
-		service := NewRouteService(mockRouteRepo, nil, nil, mockBusStopRepo)
+		service := NewRouteService(mockRouteRepo, nil, nil, mockBusStopRepo, nil, nil, nil, nil)
 
 		err := service.UnassignBusStop(uuid.New().String(), busStopID)
 		if err == nil || err.Error() != "Route not found" {
@@ -520,7 +601,7 @@ func TestRouteService_UnassignBusStop(t *testing.T) {
 	t.Run("Bus stop not found", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdResp: route}
 		mockBusStopRepo := &MockBusStopRepository{getByIdErr: errors.New("Bus stop not found")}
-		service := NewRouteService(mockRouteRepo, nil, nil, mockBusStopRepo)
+		service := NewRouteService(mockRouteRepo, nil, nil, mockBusStopRepo, nil, nil, nil, nil)
 
 		err := service.UnassignBusStop(routeID, uuid.New().String())
 		if err == nil || err.Error() != "Bus stop not found" {
@@ -531,7 +612,7 @@ func TestRouteService_UnassignBusStop(t *testing.T) {
 	t.Run("Unassign bus stop with repo error", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdResp: route, unassignBusStopErr: errors.New("Database error")}
 		mockBusStopRepo := &MockBusStopRepository{getByIdResp: busStop}
-		service := NewRouteService(mockRouteRepo, nil, nil, mockBusStopRepo)
+		service := NewRouteService(mockRouteRepo, nil, nil, mockBusStopRepo, nil, nil, nil, nil)
 
 		err := service.UnassignBusStop(routeID, busStopID)
 		if err == nil || err.Error() != "Database error" {
@@ -553,12 +634,35 @@ func TestRouteService_UnassignBus(t *testing.T) {
 		LastRepairDate: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
 	}
 
+	startsAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
 	t.Run("Success", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdResp: route}
 		mockBusRepo := &MockBusRepository{getByIdResp: bus}
-		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil)
+		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil, nil, nil, nil, nil)
 
-		err := service.UnassignBus(routeID, busID)
+		err := service.UnassignBus(routeID, busID, &startsAt)
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Current period", func(t *testing.T) {
+		ended := startsAt.AddDate(0, 1, 0)
+		mockRouteRepo := &MockRouteRepository{getByIdResp: route, getBusAssignmentsResp: []models.BusAssignment{
+			{RouteID: routeID, BusID: busID, StartsAt: startsAt, EndsAt: &ended},
+			{RouteID: uuid.New().String(), BusID: busID, StartsAt: ended},
+		}}
+		mockBusRepo := &MockBusRepository{getByIdResp: bus}
+		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil, nil, nil, nil, nil)
+
+		err := service.UnassignBus(routeID, busID, nil)
+		if err == nil || err.Error() != "Bus has no current assignment on this route" {
+			t.Errorf("Expected no current assignment error, got %v", err)
+		}
+
+		mockRouteRepo.getBusAssignmentsResp = append(mockRouteRepo.getBusAssignmentsResp, models.BusAssignment{RouteID: routeID, BusID: busID, StartsAt: ended})
+		err = service.UnassignBus(routeID, busID, nil)
 		if err != nil {
 			t.Errorf("Expected no error, got %v", err)
 		}
@@ -567,9 +671,9 @@ func TestRouteService_UnassignBus(t *testing.T) {
 	t.Run("Route not found", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdErr: errors.New("Route not found")}
 		mockBusRepo := &MockBusRepository{getByIdResp: bus}
-		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil)
+		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil, nil, nil, nil, nil)
 
-		err := service.UnassignBus(uuid.New().String(), busID)
+		err := service.UnassignBus(uuid.New().String(), busID, &startsAt)
 		if err == nil || err.Error() != "Route not found" {
 			t.Errorf("Expected 'Route not found' error, got %v", err)
 		}
@@ -578,9 +682,9 @@ func TestRouteService_UnassignBus(t *testing.T) {
 	t.Run("Bus not found", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdResp: route}
 		mockBusRepo := &MockBusRepository{getByIdErr: errors.New("Bus not found")}
-		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil)
+		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil, nil, nil, nil, nil)
 
-		err := service.UnassignBus(routeID, uuid.New().String())
+		err := service.UnassignBus(routeID, uuid.New().String(), &startsAt)
 		if err == nil || err.Error() != "Bus not found" {
 			t.Errorf("Expected 'Bus not found' error, got %v", err)
 		}
@@ -589,9 +693,9 @@ func TestRouteService_UnassignBus(t *testing.T) {
 	t.Run("Unassign bus with repo error", func(t *testing.T) {
 		mockRouteRepo := &MockRouteRepository{getByIdResp: route, unassignBusErr: errors.New("Database error")}
 		mockBusRepo := &MockBusRepository{getByIdResp: bus}
-		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil)
+		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil, nil, nil, nil, nil)
 
-		err := service.UnassignBus(routeID, busID)
+		err := service.UnassignBus(routeID, busID, &startsAt)
 		if err == nil || err.Error() != "Database error" {
 			t.Errorf("Expected 'Database error', got %v", err)
 		}
@@ -609,7 +713,7 @@ func TestRouteService_GetAllDriversById(t *testing.T) {
 			getByIdResp:           &models.Route{ID: routeID, Number: "101"},
 			getAllDriversByIdResp: []models.Driver{driver1, driver2},
 		}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		drivers, err := service.GetAllDriversById(routeID)
 		if err != nil {
@@ -627,7 +731,7 @@ func TestRouteService_GetAllDriversById(t *testing.T) {
 		mockRepo := &MockRouteRepository{
 			getByIdErr: errors.New("Route not found"),
 		}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		_, err := service.GetAllDriversById(uuid.New().String())
 		if err == nil || err.Error() != "Route not found" {
@@ -640,7 +744,7 @@ func TestRouteService_GetAllDriversById(t *testing.T) {
 			getByIdResp:           &models.Route{ID: routeID, Number: "101"},
 			getAllDriversByIdResp: nil,
 		}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		drivers, err := service.GetAllDriversById(routeID)
 		if err == nil || err.Error() != "Drivers not found" {
@@ -656,7 +760,7 @@ func TestRouteService_GetAllDriversById(t *testing.T) {
 			getByIdResp:          &models.Route{ID: routeID, Number: "101"},
 			getAllDriversByIdErr: errors.New("Database error"),
 		}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		_, err := service.GetAllDriversById(routeID)
 		if err == nil || err.Error() != "Database error" {
@@ -675,7 +779,7 @@ func TestRouteService_GetAllBusStopsById(t *testing.T) {
 			getByIdResp:            &models.Route{ID: routeID, Number: "101"},
 			getAllBusStopsByIdResp: []models.BusStop{busStop1, busStop2},
 		}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		busStops, err := service.GetAllBusStopsById(routeID)
 		if err != nil {
@@ -693,7 +797,7 @@ func TestRouteService_GetAllBusStopsById(t *testing.T) {
 		mockRepo := &MockRouteRepository{
 			getByIdErr: errors.New("Route not found"),
 		}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		_, err := service.GetAllBusStopsById(uuid.New().String())
 		if err == nil || err.Error() != "Route not found" {
@@ -706,7 +810,7 @@ func TestRouteService_GetAllBusStopsById(t *testing.T) {
 			getByIdResp:            &models.Route{ID: routeID, Number: "101"},
 			getAllBusStopsByIdResp: nil,
 		}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		busStops, err := service.GetAllBusStopsById(routeID)
 		if err == nil || err.Error() != "Bus stops not found" {
@@ -722,7 +826,7 @@ func TestRouteService_GetAllBusStopsById(t *testing.T) {
 			getByIdResp:           &models.Route{ID: routeID, Number: "101"},
 			getAllBusStopsByIdErr: errors.New("Database error"),
 		}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		_, err := service.GetAllBusStopsById(routeID)
 		if err == nil || err.Error() != "Database error" {
@@ -757,7 +861,7 @@ func TestRouteService_GetAllBusesById(t *testing.T) {
 			getByIdResp:         &models.Route{ID: routeID, Number: "101"},
 			getAllBusesByIdResp: []models.Bus{bus1, bus2},
 		}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		buses, err := service.GetAllBusesById(routeID)
 		if err != nil {
@@ -775,7 +879,7 @@ func TestRouteService_GetAllBusesById(t *testing.T) {
 		mockRepo := &MockRouteRepository{
 			getByIdErr: errors.New("Route not found"),
 		}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		_, err := service.GetAllBusesById(uuid.New().String())
 		if err == nil || err.Error() != "Route not found" {
@@ -788,7 +892,7 @@ func TestRouteService_GetAllBusesById(t *testing.T) {
 			getByIdResp:         &models.Route{ID: routeID, Number: "101"},
 			getAllBusesByIdResp: nil,
 		}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		buses, err := service.GetAllBusesById(routeID)
 		if err == nil || err.Error() != "Buses not found" {
@@ -804,7 +908,7 @@ func TestRouteService_GetAllBusesById(t *testing.T) {
 			getByIdResp:        &models.Route{ID: routeID, Number: "101"},
 			getAllBusesByIdErr: errors.New("Database error"),
 		}
-		service := NewRouteService(mockRepo, nil, nil, nil)
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
 
 		_, err := service.GetAllBusesById(routeID)
 		if err == nil || err.Error() != "Database error" {
@@ -812,3 +916,151 @@ func TestRouteService_GetAllBusesById(t *testing.T) {
 		}
 	})
 }
+
+func TestRouteService_AssignBusForPeriod(t *testing.T) {
+	routeID := uuid.New().String()
+	otherRouteID := uuid.New().String()
+	busID := uuid.New().String()
+	route := &models.Route{ID: routeID, Number: "101"}
+	bus := &models.Bus{ID: busID, Brand: "Mercedes", BusModel: "Citaro", RegisterNumber: "X123YZ"}
+	start := time.Date(2025, 3, 1, 6, 0, 0, 0, time.UTC)
+	end := start.Add(8 * time.Hour)
+	otherEnd := start.Add(2 * time.Hour)
+	existing := []models.BusAssignment{{RouteID: otherRouteID, BusID: busID, StartsAt: start.Add(-time.Hour), EndsAt: &otherEnd}}
+	qualifications := NewQualificationService(&MockDriverQualificationRepository{}, nil, nil)
+	repairs := &MockBusRepairRepository{getOpenErr: errors.New("Repair not found")}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRouteRepo := &MockRouteRepository{getByIdResp: route}
+		mockBusRepo := &MockBusRepository{getByIdResp: bus}
+		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil, nil, nil, qualifications, repairs)
+
+		result, err := service.AssignBusForPeriod(&models.BusAssignment{RouteID: routeID, BusID: busID, StartsAt: start, EndsAt: &end}, false)
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if result == nil || len(result.Conflicts) != 0 {
+			t.Errorf("Expected result without conflicts, got %v", result)
+		}
+	})
+
+	t.Run("Warns when no route driver is qualified", func(t *testing.T) {
+		driver := models.Driver{ID: uuid.New().String()}
+		mockRouteRepo := &MockRouteRepository{getByIdResp: route, getAllDriversByIdResp: []models.Driver{driver}}
+		mockBusRepo := &MockBusRepository{getByIdResp: bus}
+		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil, nil, nil, qualifications, repairs)
+
+		result, err := service.AssignBusForPeriod(&models.BusAssignment{RouteID: routeID, BusID: busID, StartsAt: start, EndsAt: &end}, false)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(result.Warnings) != 1 || result.Warnings[0] != "None of the route drivers is qualified for bus model Mercedes Citaro" {
+			t.Errorf("Expected qualification warning, got %v", result.Warnings)
+		}
+
+		qualified := NewQualificationService(&MockDriverQualificationRepository{getByBusModelResp: []models.DriverQualification{{DriverID: driver.ID, Brand: "Mercedes", BusModel: "Citaro"}}}, nil, nil)
+		service = NewRouteService(mockRouteRepo, nil, mockBusRepo, nil, nil, nil, qualified, repairs)
+		result, err = service.AssignBusForPeriod(&models.BusAssignment{RouteID: routeID, BusID: busID, StartsAt: start, EndsAt: &end}, false)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(result.Warnings) != 0 {
+			t.Errorf("Expected no warnings, got %v", result.Warnings)
+		}
+	})
+
+	t.Run("Overlap on another route is rejected", func(t *testing.T) {
+		mockRouteRepo := &MockRouteRepository{getByIdResp: route, getBusAssignmentsResp: existing}
+		mockBusRepo := &MockBusRepository{getByIdResp: bus}
+		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil, nil, nil, qualifications, repairs)
+
+		_, err := service.AssignBusForPeriod(&models.BusAssignment{RouteID: routeID, BusID: busID, StartsAt: start, EndsAt: &end}, false)
+		if err == nil {
+			t.Error("Expected conflict error, got nil")
+		}
+	})
+
+	t.Run("Overlap on another route is flagged with force", func(t *testing.T) {
+		mockRouteRepo := &MockRouteRepository{getByIdResp: route, getBusAssignmentsResp: existing}
+		mockBusRepo := &MockBusRepository{getByIdResp: bus}
+		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil, nil, nil, qualifications, repairs)
+
+		result, err := service.AssignBusForPeriod(&models.BusAssignment{RouteID: routeID, BusID: busID, StartsAt: start, EndsAt: &end}, true)
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if result == nil || len(result.Conflicts) != 1 || result.Conflicts[0].First.RouteID != otherRouteID {
+			t.Errorf("Expected one conflict with route %s, got %v", otherRouteID, result)
+		}
+	})
+
+	t.Run("Adjacent periods do not conflict", func(t *testing.T) {
+		mockRouteRepo := &MockRouteRepository{getByIdResp: route, getBusAssignmentsResp: existing}
+		mockBusRepo := &MockBusRepository{getByIdResp: bus}
+		service := NewRouteService(mockRouteRepo, nil, mockBusRepo, nil, nil, nil, qualifications, repairs)
+
+		result, err := service.AssignBusForPeriod(&models.BusAssignment{RouteID: routeID, BusID: busID, StartsAt: otherEnd, EndsAt: &end}, false)
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if result == nil || len(result.Conflicts) != 0 {
+			t.Errorf("Expected result without conflicts, got %v", result)
+		}
+	})
+
+	t.Run("Bus in repair", func(t *testing.T) {
+		inRepair := &MockBusRepairRepository{getOpenResp: &models.BusRepair{ID: uuid.New().String(), BusID: busID}}
+		service := NewRouteService(&MockRouteRepository{getByIdResp: route}, nil, &MockBusRepository{getByIdResp: bus}, nil, nil, nil, qualifications, inRepair)
+
+		_, err := service.AssignBusForPeriod(&models.BusAssignment{RouteID: routeID, BusID: busID, StartsAt: start, EndsAt: &end}, false)
+		if err == nil || err.Error() != "Bus is in repair" {
+			t.Errorf("Expected 'Bus is in repair' error, got %v", err)
+		}
+	})
+
+	t.Run("Repair lookup fails", func(t *testing.T) {
+		failing := &MockBusRepairRepository{getOpenErr: errors.New("connection refused")}
+		service := NewRouteService(&MockRouteRepository{getByIdResp: route}, nil, &MockBusRepository{getByIdResp: bus}, nil, nil, nil, qualifications, failing)
+
+		_, err := service.AssignBusForPeriod(&models.BusAssignment{RouteID: routeID, BusID: busID, StartsAt: start, EndsAt: &end}, false)
+		if err == nil || err.Error() != "connection refused" {
+			t.Errorf("Expected 'connection refused' error, got %v", err)
+		}
+	})
+
+	t.Run("End before start", func(t *testing.T) {
+		service := NewRouteService(&MockRouteRepository{getByIdResp: route}, nil, &MockBusRepository{getByIdResp: bus}, nil, nil, nil, qualifications, repairs)
+
+		_, err := service.AssignBusForPeriod(&models.BusAssignment{RouteID: routeID, BusID: busID, StartsAt: end, EndsAt: &start}, false)
+		if err == nil || err.Error() != "Assignment end must be after its start" {
+			t.Errorf("Expected 'Assignment end must be after its start' error, got %v", err)
+		}
+	})
+}
+
+func TestRouteService_GetBusConflicts(t *testing.T) {
+	conflicts := []models.BusConflict{{BusID: uuid.New().String()}}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := &MockRouteRepository{getBusConflictsResp: conflicts}
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
+
+		result, err := service.GetBusConflicts()
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if len(result) != 1 {
+			t.Errorf("Expected 1 conflict, got %d", len(result))
+		}
+	})
+
+	t.Run("Repo error", func(t *testing.T) {
+		mockRepo := &MockRouteRepository{getBusConflictsErr: errors.New("Database error")}
+		service := NewRouteService(mockRepo, nil, nil, nil, nil, nil, nil, nil)
+
+		_, err := service.GetBusConflicts()
+		if err == nil || err.Error() != "Database error" {
+			t.Errorf("Expected 'Database error', got %v", err)
+		}
+	})
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/repository"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// positions this long before or after a trip still belong to it
+	tripMatchMargin = 30 * time.Minute
+	// a departure up to one minute early or five minutes late is on time
+	earlyTolerance = time.Minute
+	lateTolerance  = 5 * time.Minute
+)
+
+type ScheduleService struct {
+	repo      repository.ITripRepository
+	dutyRepo  repository.IDutyRepository
+	routeRepo repository.IRouteRepository
+}
+
+func NewScheduleService(r repository.ITripRepository, dutyRepo repository.IDutyRepository, routeRepo repository.IRouteRepository) *ScheduleService {
+	s := &ScheduleService{r, dutyRepo, routeRepo}
+	return s
+}
+
+func (s ScheduleService) GetTrip(id string) (*models.Trip, error) {
+	trip, err := s.repo.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if trip == nil {
+		return nil, errors.New("Trip not found")
+	}
+	return trip, nil
+}
+
+// AddTrip schedules a trip on the route of the duty. Stop times have to
+// follow the route stop order, either way along the route, and fit into the
+// shift.
+func (s ScheduleService) AddTrip(trip *models.Trip) error {
+	duty, _ := s.dutyRepo.GetById(trip.DutyID)
+	if duty == nil {
+		return errors.New("Duty not found")
+	}
+	if len(trip.StopTimes) == 0 {
+		return errors.New("Trip has no stop times")
+	}
+	trip.RouteID = duty.RouteID
+	stops, err := s.routeRepo.GetAllBusStopsById(trip.RouteID)
+	if err != nil {
+		return err
+	}
+	// a route lists every stop once, see AssignBusStop
+	position := make(map[string]int)
+	for i, stop := range stops {
+		position[stop.ID] = i
+	}
+
+	// the first two stops tell whether the trip runs along the route or back
+	direction := 0
+	for i := range trip.StopTimes {
+		stopTime := &trip.StopTimes[i]
+		pos, ok := position[stopTime.StopID]
+		if !ok {
+			return fmt.Errorf("Bus stop %s is not on the route", stopTime.StopID)
+		}
+		stopTime.StopSequence = i + 1
+		stopTime.ActualArrival = nil
+		stopTime.ActualDeparture = nil
+		if stopTime.Departure.IsZero() {
+			stopTime.Departure = stopTime.Arrival
+		}
+		if stopTime.Departure.Before(stopTime.Arrival) {
+			return errors.New("Departure can't be before arrival")
+		}
+		if i > 0 {
+			prev := trip.StopTimes[i-1]
+			step := pos - position[prev.StopID]
+			if i == 1 && step > 0 {
+				direction = 1
+			} else if i == 1 && step < 0 {
+				direction = -1
+			}
+			if step*direction <= 0 {
+				return errors.New("Stop times must follow the route stop order")
+			}
+			if stopTime.Arrival.Before(prev.Departure) {
+				return errors.New("Stop times must not go back in time")
+			}
+		}
+	}
+	trip.StartsAt = trip.StopTimes[0].Arrival
+	trip.EndsAt = trip.StopTimes[len(trip.StopTimes)-1].Departure
+	if trip.StartsAt.Before(duty.ShiftStart) || trip.EndsAt.After(duty.ShiftEnd) {
+		return errors.New("Trip must be within the duty shift")
+	}
+
+	trips, err := s.repo.GetByDutyId(duty.ID)
+	if err != nil {
+		return err
+	}
+	for _, other := range trips {
+		if trip.StartsAt.Before(other.EndsAt) && other.StartsAt.Before(trip.EndsAt) {
+			return errors.New("Trip overlaps another trip of the duty")
+		}
+	}
+	return s.repo.Add(trip)
+}
+
+func (s ScheduleService) DeleteTrip(id string) error {
+	return s.repo.DeleteById(id)
+}
+
+func (s ScheduleService) GetTripsByDutyId(dutyId string) ([]models.Trip, error) {
+	duty, _ := s.dutyRepo.GetById(dutyId)
+	if duty == nil {
+		return nil, errors.New("Duty not found")
+	}
+	return s.repo.GetByDutyId(dutyId)
+}
+
+// Observe records the actual arrival and departure at the stops of the
+// trips the buses are on. The first position at a stop is the arrival,
+// the last one the departure.
+func (s ScheduleService) Observe(positions []models.VehiclePosition) error {
+	byBus := make(map[string][]models.VehiclePosition)
+	for _, position := range positions {
+		byBus[position.BusID] = append(byBus[position.BusID], position)
+	}
+	routeStops := make(map[string]map[string]models.BusStop)
+	for busId, busPositions := range byBus {
+		sort.SliceStable(busPositions, func(i, j int) bool { return busPositions[i].RecordedAt.Before(busPositions[j].RecordedAt) })
+		from := busPositions[0].RecordedAt.Add(-tripMatchMargin)
+		to := busPositions[len(busPositions)-1].RecordedAt.Add(tripMatchMargin)
+		trips, err := s.repo.GetByBusId(busId, from, to)
+		if err != nil {
+			return err
+		}
+		if len(trips) == 0 {
+			continue
+		}
+		for _, position := range busPositions {
+			trip := matchTrip(trips, position.RecordedAt)
+			if trip == nil {
+				continue
+			}
+			stops, ok := routeStops[trip.RouteID]
+			if !ok {
+				routeStopList, err := s.routeRepo.GetAllBusStopsById(trip.RouteID)
+				if err != nil {
+					return err
+				}
+				stops = make(map[string]models.BusStop)
+				for _, stop := range routeStopList {
+					stops[stop.ID] = stop
+				}
+				routeStops[trip.RouteID] = stops
+			}
+			stopTime := matchStopTime(trip, stops, position)
+			if stopTime == nil {
+				continue
+			}
+			arrival, departure := position.RecordedAt, position.RecordedAt
+			if stopTime.ActualArrival != nil && stopTime.ActualArrival.Before(arrival) {
+				arrival = *stopTime.ActualArrival
+			}
+			if stopTime.ActualDeparture != nil && stopTime.ActualDeparture.After(departure) {
+				departure = *stopTime.ActualDeparture
+			}
+			if stopTime.ActualArrival != nil && stopTime.ActualDeparture != nil &&
+				arrival.Equal(*stopTime.ActualArrival) && departure.Equal(*stopTime.ActualDeparture) {
+				continue
+			}
+			err := s.repo.SetActual(trip.ID, stopTime.StopSequence, arrival, departure)
+			if err != nil {
+				return err
+			}
+			stopTime.ActualArrival = &arrival
+			stopTime.ActualDeparture = &departure
+		}
+	}
+	return nil
+}
+
+// matchTrip picks the trip the bus is on at the given time. A trip running
+// at that time wins; otherwise the closest trip within tripMatchMargin, so
+// the layover between back-to-back trips goes to the nearer one.
+func matchTrip(trips []models.Trip, at time.Time) *models.Trip {
+	var match *models.Trip
+	var best time.Duration
+	for i := range trips {
+		var gap time.Duration
+		if at.Before(trips[i].StartsAt) {
+			gap = trips[i].StartsAt.Sub(at)
+		} else if at.After(trips[i].EndsAt) {
+			gap = at.Sub(trips[i].EndsAt)
+		}
+		if gap > tripMatchMargin {
+			continue
+		}
+		if match == nil || gap < best {
+			match, best = &trips[i], gap
+		}
+	}
+	return match
+}
+
+// matchStopTime picks the stop time the bus is at. A route lists every stop
+// once, so several stop times only match when their stops are closer to each
+// other than stopRadius; the one with the closer scheduled arrival wins.
+func matchStopTime(trip *models.Trip, stops map[string]models.BusStop, position models.VehiclePosition) *models.TripStopTime {
+	var match *models.TripStopTime
+	var best time.Duration
+	for i := range trip.StopTimes {
+		stopTime := &trip.StopTimes[i]
+		stop, ok := stops[stopTime.StopID]
+		if !ok || distance(position.Lat, position.Long, stop.Lat, stop.Long) > stopRadius {
+			continue
+		}
+		gap := position.RecordedAt.Sub(stopTime.Arrival)
+		if gap < 0 {
+			gap = -gap
+		}
+		if match == nil || gap < best {
+			match, best = stopTime, gap
+		}
+	}
+	return match
+}
+
+// GetPerformance reports early, on-time and late stop times of trips
+// starting in [from, to). Departures are measured, except at the terminal
+// stop where the bus only arrives.
+func (s ScheduleService) GetPerformance(from, to time.Time, groupBy string) ([]models.PerformanceRow, error) {
+	if groupBy != models.PerformanceByRoute && groupBy != models.PerformanceByDriver && groupBy != models.PerformanceByDay {
+		return nil, fmt.Errorf("Unknown report grouping %s", groupBy)
+	}
+	if !to.After(from) {
+		return nil, errors.New("Period end must be after period start")
+	}
+	observed, err := s.repo.GetObserved(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make(map[string]*models.PerformanceRow)
+	for _, stopTime := range observed {
+		var key string
+		switch groupBy {
+		case models.PerformanceByRoute:
+			key = stopTime.RouteID
+		case models.PerformanceByDriver:
+			key = stopTime.DriverID
+		default:
+			key = stopTime.Arrival.Format("2006-01-02")
+		}
+		row, ok := rows[key]
+		if !ok {
+			row = &models.PerformanceRow{Key: key}
+			rows[key] = row
+		}
+
+		deviation := stopTime.ActualArrival.Sub(stopTime.Arrival)
+		if !stopTime.Terminal && stopTime.ActualDeparture != nil {
+			deviation = stopTime.ActualDeparture.Sub(stopTime.Departure)
+		}
+		row.Observed++
+		switch {
+		case deviation < -earlyTolerance:
+			row.Early++
+		case deviation > lateTolerance:
+			row.Late++
+		default:
+			row.OnTime++
+		}
+	}
+
+	result := []models.PerformanceRow{}
+	for _, row := range rows {
+		row.EarlyPercent = percent(row.Early, row.Observed)
+		row.OnTimePercent = percent(row.OnTime, row.Observed)
+		row.LatePercent = percent(row.Late, row.Observed)
+		result = append(result, *row)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result, nil
+}
+
+// percent rounds to one decimal place.
+func percent(part, total int) float64 {
+	return math.Round(float64(part)*1000/float64(total)) / 10
+}
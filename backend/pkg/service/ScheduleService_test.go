@@ -0,0 +1,373 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+type MockTripRepository struct {
+	getByIdResp     *models.Trip
+	getByIdErr      error
+	added           *models.Trip
+	addErr          error
+	deleteByIdErr   error
+	getByDutyIdResp []models.Trip
+	getByDutyIdErr  error
+	getByBusIdResp  []models.Trip
+	getByBusIdErr   error
+	actuals         map[int][2]time.Time
+	setActualErr    error
+	getObservedResp []models.ObservedStopTime
+	getObservedErr  error
+	departuresResp  []models.ScheduledDeparture
+	departuresErr   error
+}
+
+func (m *MockTripRepository) GetById(id string) (*models.Trip, error) {
+	return m.getByIdResp, m.getByIdErr
+}
+
+func (m *MockTripRepository) Add(trip *models.Trip) error {
+	m.added = trip
+	return m.addErr
+}
+
+func (m *MockTripRepository) DeleteById(id string) error {
+	return m.deleteByIdErr
+}
+
+func (m *MockTripRepository) GetByDutyId(dutyId string) ([]models.Trip, error) {
+	return m.getByDutyIdResp, m.getByDutyIdErr
+}
+
+func (m *MockTripRepository) GetByBusId(busId string, from, to time.Time) ([]models.Trip, error) {
+	return m.getByBusIdResp, m.getByBusIdErr
+}
+
+func (m *MockTripRepository) SetActual(tripId string, stopSequence int, arrival, departure time.Time) error {
+	if m.actuals == nil {
+		m.actuals = make(map[int][2]time.Time)
+	}
+	m.actuals[stopSequence] = [2]time.Time{arrival, departure}
+	return m.setActualErr
+}
+
+func (m *MockTripRepository) GetObserved(from, to time.Time) ([]models.ObservedStopTime, error) {
+	return m.getObservedResp, m.getObservedErr
+}
+
+func (m *MockTripRepository) GetStopDepartures(stopId string, from, to time.Time) ([]models.ScheduledDeparture, error) {
+	return m.departuresResp, m.departuresErr
+}
+
+type MockScheduleService struct {
+	observed   []models.VehiclePosition
+	observeErr error
+}
+
+func (m *MockScheduleService) GetTrip(id string) (*models.Trip, error) {
+	return nil, nil
+}
+
+func (m *MockScheduleService) AddTrip(trip *models.Trip) error {
+	return nil
+}
+
+func (m *MockScheduleService) DeleteTrip(id string) error {
+	return nil
+}
+
+func (m *MockScheduleService) GetTripsByDutyId(dutyId string) ([]models.Trip, error) {
+	return nil, nil
+}
+
+func (m *MockScheduleService) Observe(positions []models.VehiclePosition) error {
+	m.observed = append(m.observed, positions...)
+	return m.observeErr
+}
+
+func (m *MockScheduleService) GetPerformance(from, to time.Time, groupBy string) ([]models.PerformanceRow, error) {
+	return nil, nil
+}
+
+func newScheduleRouteRepo() *MockRouteRepository {
+	return &MockRouteRepository{
+		getAllBusStopsByIdResp: []models.BusStop{
+			{ID: "s1", Lat: 55.70, Long: 37.60},
+			{ID: "s2", Lat: 55.71, Long: 37.60},
+			{ID: "s3", Lat: 55.72, Long: 37.60},
+		},
+	}
+}
+
+func TestScheduleService_AddTrip(t *testing.T) {
+	shiftStart := time.Date(2025, 3, 3, 6, 0, 0, 0, time.UTC)
+	duty := &models.Duty{ID: "duty-1", RouteID: "route-1", BusID: "bus-1", ShiftStart: shiftStart, ShiftEnd: shiftStart.Add(8 * time.Hour)}
+	newTrip := func() *models.Trip {
+		return &models.Trip{
+			DutyID: duty.ID,
+			StopTimes: []models.TripStopTime{
+				{StopID: "s1", Arrival: shiftStart.Add(time.Hour), Departure: shiftStart.Add(time.Hour + time.Minute)},
+				{StopID: "s3", Arrival: shiftStart.Add(time.Hour + 10*time.Minute)},
+			},
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		repo := &MockTripRepository{}
+		service := NewScheduleService(repo, &MockDutyRepository{getByIdResp: duty}, newScheduleRouteRepo())
+		trip := newTrip()
+
+		err := service.AddTrip(trip)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if repo.added == nil || trip.RouteID != "route-1" || trip.StopTimes[1].StopSequence != 2 {
+			t.Errorf("Expected trip on the duty route with trip stop sequence, got %v", trip)
+		}
+		if !trip.StartsAt.Equal(shiftStart.Add(time.Hour)) || !trip.EndsAt.Equal(shiftStart.Add(time.Hour+10*time.Minute)) {
+			t.Errorf("Expected trip window from stop times, got %v - %v", trip.StartsAt, trip.EndsAt)
+		}
+	})
+
+	t.Run("Stop not on the route", func(t *testing.T) {
+		service := NewScheduleService(&MockTripRepository{}, &MockDutyRepository{getByIdResp: duty}, newScheduleRouteRepo())
+		trip := newTrip()
+		trip.StopTimes[1].StopID = "s9"
+
+		err := service.AddTrip(trip)
+		if err == nil || err.Error() != "Bus stop s9 is not on the route" {
+			t.Errorf("Expected 'Bus stop s9 is not on the route' error, got %v", err)
+		}
+	})
+
+	t.Run("Return direction", func(t *testing.T) {
+		repo := &MockTripRepository{}
+		service := NewScheduleService(repo, &MockDutyRepository{getByIdResp: duty}, newScheduleRouteRepo())
+		trip := newTrip()
+		trip.StopTimes[0].StopID, trip.StopTimes[1].StopID = "s3", "s1"
+
+		err := service.AddTrip(trip)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if repo.added == nil || trip.StopTimes[0].StopSequence != 1 || trip.StopTimes[1].StopSequence != 2 {
+			t.Errorf("Expected trip stop sequence along the trip, got %v", trip.StopTimes)
+		}
+	})
+
+	t.Run("Wrong stop order", func(t *testing.T) {
+		service := NewScheduleService(&MockTripRepository{}, &MockDutyRepository{getByIdResp: duty}, newScheduleRouteRepo())
+		trip := newTrip()
+		trip.StopTimes = append(trip.StopTimes, models.TripStopTime{StopID: "s2", Arrival: shiftStart.Add(time.Hour + 20*time.Minute)})
+
+		err := service.AddTrip(trip)
+		if err == nil || err.Error() != "Stop times must follow the route stop order" {
+			t.Errorf("Expected 'Stop times must follow the route stop order' error, got %v", err)
+		}
+	})
+
+	t.Run("Outside the shift", func(t *testing.T) {
+		service := NewScheduleService(&MockTripRepository{}, &MockDutyRepository{getByIdResp: duty}, newScheduleRouteRepo())
+		trip := newTrip()
+		trip.StopTimes[1].Arrival = shiftStart.Add(9 * time.Hour)
+
+		err := service.AddTrip(trip)
+		if err == nil || err.Error() != "Trip must be within the duty shift" {
+			t.Errorf("Expected 'Trip must be within the duty shift' error, got %v", err)
+		}
+	})
+
+	t.Run("Overlapping trip", func(t *testing.T) {
+		other := models.Trip{ID: "trip-0", StartsAt: shiftStart.Add(50 * time.Minute), EndsAt: shiftStart.Add(time.Hour + 5*time.Minute)}
+		service := NewScheduleService(&MockTripRepository{getByDutyIdResp: []models.Trip{other}}, &MockDutyRepository{getByIdResp: duty}, newScheduleRouteRepo())
+
+		err := service.AddTrip(newTrip())
+		if err == nil || err.Error() != "Trip overlaps another trip of the duty" {
+			t.Errorf("Expected 'Trip overlaps another trip of the duty' error, got %v", err)
+		}
+	})
+
+	t.Run("Duty not found", func(t *testing.T) {
+		service := NewScheduleService(&MockTripRepository{}, &MockDutyRepository{getByIdErr: errors.New("Duty not found")}, newScheduleRouteRepo())
+
+		err := service.AddTrip(newTrip())
+		if err == nil || err.Error() != "Duty not found" {
+			t.Errorf("Expected 'Duty not found' error, got %v", err)
+		}
+	})
+}
+
+func TestScheduleService_Observe(t *testing.T) {
+	start := time.Date(2025, 3, 3, 7, 0, 0, 0, time.UTC)
+	trip := models.Trip{
+		ID:       "trip-1",
+		RouteID:  "route-1",
+		StartsAt: start,
+		EndsAt:   start.Add(10 * time.Minute),
+		StopTimes: []models.TripStopTime{
+			{StopSequence: 1, StopID: "s1", Arrival: start, Departure: start},
+			{StopSequence: 2, StopID: "s2", Arrival: start.Add(5 * time.Minute), Departure: start.Add(5 * time.Minute)},
+		},
+	}
+	repo := &MockTripRepository{getByBusIdResp: []models.Trip{trip}}
+	service := NewScheduleService(repo, &MockDutyRepository{}, newScheduleRouteRepo())
+
+	err := service.Observe([]models.VehiclePosition{
+		{BusID: "bus-1", RecordedAt: start.Add(7*time.Minute + 30*time.Second), Lat: 55.7101, Long: 37.60},
+		{BusID: "bus-1", RecordedAt: start.Add(7 * time.Minute), Lat: 55.71, Long: 37.60},
+		{BusID: "bus-1", RecordedAt: start.Add(3 * time.Minute), Lat: 55.705, Long: 37.60},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(repo.actuals) != 1 {
+		t.Fatalf("Expected actual times at s2 only, got %v", repo.actuals)
+	}
+	actual := repo.actuals[2]
+	if !actual[0].Equal(start.Add(7*time.Minute)) || !actual[1].Equal(start.Add(7*time.Minute+30*time.Second)) {
+		t.Errorf("Expected arrival 07:07:00 and departure 07:07:30, got %v", actual)
+	}
+}
+
+func TestScheduleService_Observe_BackToBackTrips(t *testing.T) {
+	start := time.Date(2025, 3, 3, 7, 0, 0, 0, time.UTC)
+	out := models.Trip{
+		ID:       "trip-1",
+		RouteID:  "route-1",
+		StartsAt: start,
+		EndsAt:   start.Add(30 * time.Minute),
+		StopTimes: []models.TripStopTime{
+			{StopSequence: 1, StopID: "s1", Arrival: start, Departure: start},
+			{StopSequence: 2, StopID: "s3", Arrival: start.Add(30 * time.Minute), Departure: start.Add(30 * time.Minute)},
+		},
+	}
+	back := models.Trip{
+		ID:       "trip-2",
+		RouteID:  "route-1",
+		StartsAt: start.Add(35 * time.Minute),
+		EndsAt:   start.Add(time.Hour),
+		StopTimes: []models.TripStopTime{
+			{StopSequence: 1, StopID: "s3", Arrival: start.Add(35 * time.Minute), Departure: start.Add(35 * time.Minute)},
+			{StopSequence: 2, StopID: "s1", Arrival: start.Add(time.Hour), Departure: start.Add(time.Hour)},
+		},
+	}
+	repo := &MockTripRepository{getByBusIdResp: []models.Trip{out, back}}
+	service := NewScheduleService(repo, &MockDutyRepository{}, newScheduleRouteRepo())
+
+	// the bus waits at s3 for the return trip
+	err := service.Observe([]models.VehiclePosition{
+		{BusID: "bus-1", RecordedAt: start.Add(34 * time.Minute), Lat: 55.72, Long: 37.60},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, ok := repo.actuals[1]; !ok || len(repo.actuals) != 1 {
+		t.Errorf("Expected the departure of the return trip from s3, got %v", repo.actuals)
+	}
+}
+
+func TestMatchTrip(t *testing.T) {
+	start := time.Date(2025, 3, 3, 7, 0, 0, 0, time.UTC)
+	// back-to-back trips with a five minute layover between them
+	trips := []models.Trip{
+		{ID: "trip-1", StartsAt: start, EndsAt: start.Add(30 * time.Minute)},
+		{ID: "trip-2", StartsAt: start.Add(35 * time.Minute), EndsAt: start.Add(time.Hour)},
+	}
+	tests := []struct {
+		name string
+		at   time.Time
+		want string
+	}{
+		{name: "Before the first trip", at: start.Add(-10 * time.Minute), want: "trip-1"},
+		{name: "During the first trip", at: start.Add(10 * time.Minute), want: "trip-1"},
+		{name: "Early in the layover", at: start.Add(31 * time.Minute), want: "trip-1"},
+		{name: "Late in the layover", at: start.Add(34 * time.Minute), want: "trip-2"},
+		{name: "During the second trip", at: start.Add(40 * time.Minute), want: "trip-2"},
+		{name: "After the last trip", at: start.Add(80 * time.Minute), want: "trip-2"},
+		{name: "Far from any trip", at: start.Add(2 * time.Hour), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trip := matchTrip(trips, tt.at)
+			got := ""
+			if trip != nil {
+				got = trip.ID
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestScheduleService_GetPerformance(t *testing.T) {
+	day := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) *time.Time {
+		t := day.Add(8*time.Hour + time.Duration(minutes)*time.Minute)
+		return &t
+	}
+	scheduled := day.Add(8 * time.Hour)
+	stopTime := func(routeId, driverId string, departure *time.Time, terminal bool) models.ObservedStopTime {
+		return models.ObservedStopTime{
+			RouteID:  routeId,
+			DriverID: driverId,
+			Terminal: terminal,
+			TripStopTime: models.TripStopTime{
+				Arrival:         scheduled,
+				Departure:       scheduled,
+				ActualArrival:   at(0),
+				ActualDeparture: departure,
+			},
+		}
+	}
+	observed := []models.ObservedStopTime{
+		stopTime("route-1", "d1", at(-3), false),
+		stopTime("route-1", "d1", at(2), false),
+		stopTime("route-1", "d2", at(10), false),
+		// the terminal stop is measured at arrival, the layover does not count
+		stopTime("route-2", "d2", at(20), true),
+	}
+
+	t.Run("By route", func(t *testing.T) {
+		service := NewScheduleService(&MockTripRepository{getObservedResp: observed}, &MockDutyRepository{}, &MockRouteRepository{})
+
+		rows, err := service.GetPerformance(day, day.AddDate(0, 0, 1), models.PerformanceByRoute)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(rows) != 2 || rows[0].Key != "route-1" {
+			t.Fatalf("Expected rows for route-1 and route-2, got %v", rows)
+		}
+		if rows[0].Early != 1 || rows[0].OnTime != 1 || rows[0].Late != 1 || rows[0].OnTimePercent != 33.3 {
+			t.Errorf("Expected one early, on-time and late stop on route-1, got %v", rows[0])
+		}
+		if rows[1].OnTime != 1 || rows[1].OnTimePercent != 100 {
+			t.Errorf("Expected route-2 on time, got %v", rows[1])
+		}
+	})
+
+	t.Run("By day", func(t *testing.T) {
+		service := NewScheduleService(&MockTripRepository{getObservedResp: observed}, &MockDutyRepository{}, &MockRouteRepository{})
+
+		rows, err := service.GetPerformance(day, day.AddDate(0, 0, 1), models.PerformanceByDay)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(rows) != 1 || rows[0].Key != "2025-03-03" || rows[0].Observed != 4 || rows[0].LatePercent != 25 {
+			t.Errorf("Expected one row for 2025-03-03, got %v", rows)
+		}
+	})
+
+	t.Run("Unknown grouping", func(t *testing.T) {
+		service := NewScheduleService(&MockTripRepository{}, &MockDutyRepository{}, &MockRouteRepository{})
+
+		_, err := service.GetPerformance(day, day.AddDate(0, 0, 1), "bus")
+		if err == nil || err.Error() != "Unknown report grouping bus" {
+			t.Errorf("Expected 'Unknown report grouping bus' error, got %v", err)
+		}
+	})
+}
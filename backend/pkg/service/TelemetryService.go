@@ -0,0 +1,148 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"backend/pkg/repository"
+	"errors"
+	"log"
+	"time"
+)
+
+const (
+	MaxPositionBatch = 1000
+	// maxClockSkew is how far in the future an on-board unit clock may run.
+	maxClockSkew = 5 * time.Minute
+)
+
+type TelemetryService struct {
+	repo      repository.IPositionRepository
+	busRepo   repository.IBusRepository
+	routeRepo repository.IRouteRepository
+	hub       *PositionHub
+	arrivals  IArrivalService
+	schedule  IScheduleService
+}
+
+func NewTelemetryService(r repository.IPositionRepository, busRepo repository.IBusRepository, routeRepo repository.IRouteRepository, hub *PositionHub, arrivals IArrivalService, schedule IScheduleService) *TelemetryService {
+	s := &TelemetryService{r, busRepo, routeRepo, hub, arrivals, schedule}
+	return s
+}
+
+// Ingest stores the valid positions of the batch and reports the invalid
+// ones instead of failing the whole batch.
+func (s TelemetryService) Ingest(positions []models.VehiclePosition) (*models.PositionBatchResult, error) {
+	if len(positions) == 0 {
+		return nil, errors.New("Position batch is empty")
+	}
+	if len(positions) > MaxPositionBatch {
+		return nil, errors.New("Position batch is too large")
+	}
+	result := &models.PositionBatchResult{Rejected: []models.PositionRejection{}}
+	known := make(map[string]bool)
+	var accepted []models.VehiclePosition
+	now := time.Now()
+	for i, position := range positions {
+		err := validatePosition(position, now)
+		if err == nil {
+			exists, ok := known[position.BusID]
+			if !ok {
+				bus, err := s.busRepo.GetById(position.BusID)
+				if err != nil && err.Error() != "Bus not found" {
+					return nil, err
+				}
+				exists = bus != nil
+				known[position.BusID] = exists
+			}
+			if !exists {
+				err = errors.New("Bus not found")
+			}
+		}
+		if err != nil {
+			result.Rejected = append(result.Rejected, models.PositionRejection{Index: i, BusID: position.BusID, Error: err.Error()})
+			continue
+		}
+		accepted = append(accepted, position)
+	}
+	if len(accepted) > 0 {
+		err := s.repo.SaveBatch(accepted)
+		if err != nil {
+			return nil, err
+		}
+		// stop passages and actual stop times are derived from the stored
+		// positions, so failing to record them must not fail the batch
+		err = s.arrivals.Observe(accepted)
+		if err != nil {
+			log.Printf("observe stop passages: %v", err)
+		}
+		err = s.schedule.Observe(accepted)
+		if err != nil {
+			log.Printf("observe actual stop times: %v", err)
+		}
+		s.hub.Publish(accepted)
+	}
+	result.Accepted = len(accepted)
+	return result, nil
+}
+
+func (s TelemetryService) GetLatest(busId string) (*models.VehiclePosition, error) {
+	position, err := s.repo.GetLatest(busId)
+	if err != nil {
+		return nil, err
+	}
+	if position == nil {
+		return nil, errors.New("Position not found")
+	}
+	return position, nil
+}
+
+func validatePosition(position models.VehiclePosition, now time.Time) error {
+	if position.BusID == "" {
+		return errors.New("Bus ID is required")
+	}
+	if position.RecordedAt.IsZero() {
+		return errors.New("Timestamp is required")
+	}
+	if position.RecordedAt.After(now.Add(maxClockSkew)) {
+		return errors.New("Timestamp is in the future")
+	}
+	if position.Lat < -90 || position.Lat > 90 || position.Long < -180 || position.Long > 180 {
+		return errors.New("Coordinates are out of range")
+	}
+	if position.Speed < 0 {
+		return errors.New("Speed can't be negative")
+	}
+	if position.Heading < 0 || position.Heading >= 360 {
+		return errors.New("Heading must be in [0, 360)")
+	}
+	return nil
+}
+
+// Subscribe starts a position stream limited to the buses of the route
+// and/or the bounding box. The route buses are resolved once, when the
+// stream starts. The snapshot holds the latest known positions that match.
+func (s TelemetryService) Subscribe(routeId string, box *models.BoundingBox) ([]models.VehiclePosition, <-chan []models.VehiclePosition, func(), error) {
+	filter := PositionFilter{Box: box}
+	if box != nil && (box.MinLat > box.MaxLat || box.MinLong > box.MaxLong) {
+		return nil, nil, nil, errors.New("Invalid bounding box")
+	}
+	if routeId != "" {
+		route, _ := s.routeRepo.GetById(routeId)
+		if route == nil {
+			return nil, nil, nil, errors.New("Route not found")
+		}
+		buses, err := s.routeRepo.GetAllBusesAt(routeId, time.Now())
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		filter.BusIDs = make(map[string]bool)
+		for _, bus := range buses {
+			filter.BusIDs[bus.ID] = true
+		}
+	}
+	latest, err := s.repo.GetAllLatest()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ch, unsubscribe := s.hub.Subscribe(filter)
+	return filter.apply(latest), ch, unsubscribe, nil
+}
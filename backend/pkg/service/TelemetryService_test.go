@@ -0,0 +1,304 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+type MockPositionRepository struct {
+	saved            []models.VehiclePosition
+	saveBatchErr     error
+	getLatestResp    *models.VehiclePosition
+	getLatestErr     error
+	getAllLatestResp []models.VehiclePosition
+	getAllLatestErr  error
+	getHistoryResp   []models.VehiclePosition
+	getHistoryErr    error
+}
+
+func (m *MockPositionRepository) SaveBatch(positions []models.VehiclePosition) error {
+	m.saved = append(m.saved, positions...)
+	return m.saveBatchErr
+}
+
+func (m *MockPositionRepository) GetLatest(busId string) (*models.VehiclePosition, error) {
+	return m.getLatestResp, m.getLatestErr
+}
+
+func (m *MockPositionRepository) GetAllLatest() ([]models.VehiclePosition, error) {
+	return m.getAllLatestResp, m.getAllLatestErr
+}
+
+func (m *MockPositionRepository) GetHistory(busId string, from, to time.Time) ([]models.VehiclePosition, error) {
+	return m.getHistoryResp, m.getHistoryErr
+}
+
+type MockArrivalService struct {
+	observed     []models.VehiclePosition
+	observeErr   error
+	arrivalsResp []models.RouteArrivals
+	arrivalsErr  error
+}
+
+func (m *MockArrivalService) Observe(positions []models.VehiclePosition) error {
+	m.observed = append(m.observed, positions...)
+	return m.observeErr
+}
+
+func (m *MockArrivalService) GetStopArrivals(stopId string, now time.Time) ([]models.RouteArrivals, error) {
+	return m.arrivalsResp, m.arrivalsErr
+}
+
+func TestTelemetryService_Ingest(t *testing.T) {
+	bus := &models.Bus{ID: "bus-1", Brand: "ЛиАЗ", BusModel: "5292"}
+	position := func() models.VehiclePosition {
+		return models.VehiclePosition{
+			BusID:      bus.ID,
+			RecordedAt: time.Now().Add(-time.Minute),
+			Lat:        55.75,
+			Long:       37.61,
+			Speed:      30,
+			Heading:    90,
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		repo := &MockPositionRepository{}
+		service := NewTelemetryService(repo, &MockBusRepository{getByIdResp: bus}, &MockRouteRepository{}, NewPositionHub(), &MockArrivalService{}, &MockScheduleService{})
+
+		result, err := service.Ingest([]models.VehiclePosition{position(), position()})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Accepted != 2 || len(result.Rejected) != 0 || len(repo.saved) != 2 {
+			t.Errorf("Expected 2 accepted positions, got %v", result)
+		}
+	})
+
+	t.Run("Invalid positions are rejected", func(t *testing.T) {
+		repo := &MockPositionRepository{}
+		service := NewTelemetryService(repo, &MockBusRepository{getByIdResp: bus}, &MockRouteRepository{}, NewPositionHub(), &MockArrivalService{}, &MockScheduleService{})
+		badLat := position()
+		badLat.Lat = 91
+		badHeading := position()
+		badHeading.Heading = 360
+		future := position()
+		future.RecordedAt = time.Now().Add(time.Hour)
+
+		result, err := service.Ingest([]models.VehiclePosition{badLat, position(), badHeading, future})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Accepted != 1 || len(repo.saved) != 1 {
+			t.Errorf("Expected 1 accepted position, got %v", result)
+		}
+		if len(result.Rejected) != 3 || result.Rejected[0].Index != 0 || result.Rejected[1].Index != 2 || result.Rejected[2].Index != 3 {
+			t.Fatalf("Expected items 0, 2 and 3 rejected, got %v", result.Rejected)
+		}
+		if result.Rejected[2].Error != "Timestamp is in the future" {
+			t.Errorf("Expected 'Timestamp is in the future', got %v", result.Rejected[2].Error)
+		}
+	})
+
+	t.Run("Unknown bus", func(t *testing.T) {
+		repo := &MockPositionRepository{}
+		service := NewTelemetryService(repo, &MockBusRepository{getByIdErr: errors.New("Bus not found")}, &MockRouteRepository{}, NewPositionHub(), &MockArrivalService{}, &MockScheduleService{})
+
+		result, err := service.Ingest([]models.VehiclePosition{position()})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Accepted != 0 || len(result.Rejected) != 1 || result.Rejected[0].Error != "Bus not found" {
+			t.Errorf("Expected position of unknown bus rejected, got %v", result)
+		}
+		if len(repo.saved) != 0 {
+			t.Errorf("Expected nothing saved, got %v", repo.saved)
+		}
+	})
+
+	t.Run("Bus lookup fails", func(t *testing.T) {
+		repo := &MockPositionRepository{}
+		service := NewTelemetryService(repo, &MockBusRepository{getByIdErr: errors.New("connection refused")}, &MockRouteRepository{}, NewPositionHub(), &MockArrivalService{}, &MockScheduleService{})
+
+		_, err := service.Ingest([]models.VehiclePosition{position()})
+		if err == nil || err.Error() != "connection refused" {
+			t.Errorf("Expected the database error, got %v", err)
+		}
+		if len(repo.saved) != 0 {
+			t.Errorf("Expected nothing saved, got %v", repo.saved)
+		}
+	})
+
+	t.Run("Accepted positions are published", func(t *testing.T) {
+		hub := NewPositionHub()
+		service := NewTelemetryService(&MockPositionRepository{}, &MockBusRepository{getByIdResp: bus}, &MockRouteRepository{}, hub, &MockArrivalService{}, &MockScheduleService{})
+		updates, unsubscribe := hub.Subscribe(PositionFilter{})
+		defer unsubscribe()
+		bad := position()
+		bad.Speed = -1
+
+		_, err := service.Ingest([]models.VehiclePosition{position(), bad})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		select {
+		case positions := <-updates:
+			if len(positions) != 1 {
+				t.Errorf("Expected only the accepted position published, got %v", positions)
+			}
+		default:
+			t.Error("Expected a published batch")
+		}
+	})
+
+	t.Run("Stop passages don't fail the batch", func(t *testing.T) {
+		repo := &MockPositionRepository{}
+		arrivals := &MockArrivalService{observeErr: errors.New("db error")}
+		service := NewTelemetryService(repo, &MockBusRepository{getByIdResp: bus}, &MockRouteRepository{}, NewPositionHub(), arrivals, &MockScheduleService{})
+
+		result, err := service.Ingest([]models.VehiclePosition{position()})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Accepted != 1 || len(arrivals.observed) != 1 || len(repo.saved) != 1 {
+			t.Errorf("Expected saved and observed position, got %v and %v", repo.saved, arrivals.observed)
+		}
+	})
+
+	t.Run("Actual stop times don't fail the batch", func(t *testing.T) {
+		repo := &MockPositionRepository{}
+		schedule := &MockScheduleService{observeErr: errors.New("db error")}
+		service := NewTelemetryService(repo, &MockBusRepository{getByIdResp: bus}, &MockRouteRepository{}, NewPositionHub(), &MockArrivalService{}, schedule)
+
+		result, err := service.Ingest([]models.VehiclePosition{position()})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Accepted != 1 || len(schedule.observed) != 1 || len(repo.saved) != 1 {
+			t.Errorf("Expected saved and observed position, got %v and %v", repo.saved, schedule.observed)
+		}
+	})
+
+	t.Run("Unsaved positions are not observed", func(t *testing.T) {
+		arrivals := &MockArrivalService{}
+		schedule := &MockScheduleService{}
+		service := NewTelemetryService(&MockPositionRepository{saveBatchErr: errors.New("db error")}, &MockBusRepository{getByIdResp: bus}, &MockRouteRepository{}, NewPositionHub(), arrivals, schedule)
+
+		_, err := service.Ingest([]models.VehiclePosition{position()})
+		if err == nil || err.Error() != "db error" {
+			t.Errorf("Expected 'db error', got %v", err)
+		}
+		if len(arrivals.observed) != 0 || len(schedule.observed) != 0 {
+			t.Errorf("Expected no observed positions, got %v and %v", arrivals.observed, schedule.observed)
+		}
+	})
+
+	t.Run("Empty batch", func(t *testing.T) {
+		service := NewTelemetryService(&MockPositionRepository{}, &MockBusRepository{getByIdResp: bus}, &MockRouteRepository{}, NewPositionHub(), &MockArrivalService{}, &MockScheduleService{})
+
+		_, err := service.Ingest(nil)
+		if err == nil || err.Error() != "Position batch is empty" {
+			t.Errorf("Expected 'Position batch is empty' error, got %v", err)
+		}
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		repo := &MockPositionRepository{saveBatchErr: errors.New("db error")}
+		service := NewTelemetryService(repo, &MockBusRepository{getByIdResp: bus}, &MockRouteRepository{}, NewPositionHub(), &MockArrivalService{}, &MockScheduleService{})
+
+		_, err := service.Ingest([]models.VehiclePosition{position()})
+		if err == nil || err.Error() != "db error" {
+			t.Errorf("Expected 'db error', got %v", err)
+		}
+	})
+}
+
+func TestTelemetryService_Subscribe(t *testing.T) {
+	route := &models.Route{ID: "route-1", Number: "42"}
+	latest := []models.VehiclePosition{
+		{BusID: "bus-1", Lat: 55.75, Long: 37.61},
+		{BusID: "bus-2", Lat: 55.76, Long: 37.62},
+		{BusID: "bus-3", Lat: 59.93, Long: 30.31},
+	}
+
+	t.Run("Route filter", func(t *testing.T) {
+		routeRepo := &MockRouteRepository{getByIdResp: route, getAllBusesByIdResp: []models.Bus{{ID: "bus-2"}, {ID: "bus-3"}}}
+		hub := NewPositionHub()
+		service := NewTelemetryService(&MockPositionRepository{getAllLatestResp: latest}, &MockBusRepository{}, routeRepo, hub, &MockArrivalService{}, &MockScheduleService{})
+
+		snapshot, updates, unsubscribe, err := service.Subscribe(route.ID, nil)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer unsubscribe()
+		if len(snapshot) != 2 || snapshot[0].BusID != "bus-2" {
+			t.Errorf("Expected snapshot of route buses, got %v", snapshot)
+		}
+
+		hub.Publish([]models.VehiclePosition{latest[0], latest[1]})
+		positions := <-updates
+		if len(positions) != 1 || positions[0].BusID != "bus-2" {
+			t.Errorf("Expected only bus-2 published, got %v", positions)
+		}
+	})
+
+	t.Run("Bounding box filter", func(t *testing.T) {
+		service := NewTelemetryService(&MockPositionRepository{getAllLatestResp: latest}, &MockBusRepository{}, &MockRouteRepository{}, NewPositionHub(), &MockArrivalService{}, &MockScheduleService{})
+		box := &models.BoundingBox{MinLat: 55, MinLong: 37, MaxLat: 56, MaxLong: 38}
+
+		snapshot, _, unsubscribe, err := service.Subscribe("", box)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer unsubscribe()
+		if len(snapshot) != 2 {
+			t.Errorf("Expected 2 positions inside the box, got %v", snapshot)
+		}
+	})
+
+	t.Run("Invalid bounding box", func(t *testing.T) {
+		service := NewTelemetryService(&MockPositionRepository{}, &MockBusRepository{}, &MockRouteRepository{}, NewPositionHub(), &MockArrivalService{}, &MockScheduleService{})
+
+		_, _, _, err := service.Subscribe("", &models.BoundingBox{MinLat: 56, MaxLat: 55})
+		if err == nil || err.Error() != "Invalid bounding box" {
+			t.Errorf("Expected 'Invalid bounding box' error, got %v", err)
+		}
+	})
+
+	t.Run("Route not found", func(t *testing.T) {
+		service := NewTelemetryService(&MockPositionRepository{}, &MockBusRepository{}, &MockRouteRepository{getByIdErr: errors.New("Route not found")}, NewPositionHub(), &MockArrivalService{}, &MockScheduleService{})
+
+		_, _, _, err := service.Subscribe("missing", nil)
+		if err == nil || err.Error() != "Route not found" {
+			t.Errorf("Expected 'Route not found' error, got %v", err)
+		}
+	})
+}
+
+func TestPositionHub(t *testing.T) {
+	position := models.VehiclePosition{BusID: "bus-1", Lat: 55.75, Long: 37.61}
+
+	t.Run("Slow subscriber does not block", func(t *testing.T) {
+		hub := NewPositionHub()
+		_, unsubscribe := hub.Subscribe(PositionFilter{})
+		defer unsubscribe()
+
+		for i := 0; i < subscriberBuffer+5; i++ {
+			hub.Publish([]models.VehiclePosition{position})
+		}
+	})
+
+	t.Run("Unsubscribe closes the channel", func(t *testing.T) {
+		hub := NewPositionHub()
+		updates, unsubscribe := hub.Subscribe(PositionFilter{})
+		unsubscribe()
+		unsubscribe()
+
+		hub.Publish([]models.VehiclePosition{position})
+		if _, ok := <-updates; ok {
+			t.Error("Expected closed channel")
+		}
+	})
+}
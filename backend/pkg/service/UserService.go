@@ -3,81 +3,387 @@ package service
 import (
 	"backend/pkg/models"
 	"backend/pkg/repository"
-	"crypto/sha1"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"github.com/dgrijalva/jwt-go"
-	"github.com/spf13/viper"
+	"log"
 	"time"
 )
 
-const (
-	signingKey = "qrkjk#4#%35FSFJlja#4353KSFjH"
-	tokenTTL   = 12 * time.Hour
-)
+// MinSigningKeyLength is the shortest secret accepted for HS256 signing
+const MinSigningKeyLength = 32
+
+// SigningKey is an HMAC secret identified by the kid header of the tokens
+// it signs.
+type SigningKey struct {
+	ID     string
+	Secret []byte
+}
+
+// TokenSettings holds the active signing keys, oldest first. New tokens are
+// signed with the last key, the previous ones only verify tokens issued
+// before the rotation until they expire. TTL is the lifetime of access
+// tokens, RefreshTTL the time a session stays alive without a refresh.
+type TokenSettings struct {
+	Keys       []SigningKey
+	TTL        time.Duration
+	RefreshTTL time.Duration
+}
+
+// StreamTokenTTL is how long a stream token can be used to open a stream. A
+// stream outlives its token and is checked against the session instead.
+const StreamTokenTTL = time.Minute
+
+// streamAudience marks stream tokens. They travel in URLs, so they are not
+// accepted as access tokens.
+const streamAudience = "stream"
+
+func DefaultTokenTTL() time.Duration {
+	return 15 * time.Minute
+}
+
+func DefaultRefreshTTL() time.Duration {
+	return 30 * 24 * time.Hour
+}
 
 type tokenClaims struct {
 	jwt.StandardClaims
-	UserId string `json:"user_id"`
+	UserId    string   `json:"user_id"`
+	Roles     []string `json:"roles"`
+	SessionId string   `json:"sid"`
+}
+
+// Identity is the caller an access token was issued to
+type Identity struct {
+	UserId    string
+	Roles     []string
+	SessionId string
+}
+
+// userRoles are the roles an admin can grant
+var userRoles = map[string]bool{
+	models.RoleAdmin:      true,
+	models.RoleDispatcher: true,
+	models.RoleHR:         true,
+	models.RoleViewer:     true,
 }
 
 type UserService struct {
-	repo repository.IUserRepository
+	repo     repository.IUserRepository
+	sessions repository.ISessionRepository
+	tokens   TokenSettings
 }
 
-func NewUserService(r repository.IUserRepository) *UserService {
-	b := &UserService{repo: r}
+func NewUserService(r repository.IUserRepository, sessions repository.ISessionRepository, tokens TokenSettings) *UserService {
+	b := &UserService{repo: r, sessions: sessions, tokens: tokens}
 	return b
 }
 
+// CreateUser registers a viewer. Elevated roles are never granted on sign-up;
+// an admin grants them, and the first admin is made with cmd/grant-role.
 func (s UserService) CreateUser(user models.User) error {
-	user.Password = generatePasswordHash(user.Password)
-	err := s.repo.Add(&user)
+	user.Roles = []string{models.RoleViewer}
+	hash, err := hashPassword(user.Password)
+	if err != nil {
+		return err
+	}
+	user.Password = hash
+	err = s.repo.Add(&user)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s UserService) GenerateToken(username, password string) (string, error) {
-	user, err := s.repo.GetByUsername(username, generatePasswordHash(password))
+// GenerateToken signs in the user and starts a session. A legacy password
+// hash is replaced with a bcrypt one once the password is known to match.
+func (s UserService) GenerateToken(username, password string) (*models.Tokens, error) {
+	user, err := s.repo.GetByUsername(username)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	ok, rehash := checkPassword(user.Password, password)
+	if !ok {
+		return nil, errors.New("User not found")
+	}
+	if rehash {
+		hash, err := hashPassword(password)
+		if err != nil {
+			return nil, err
+		}
+		err = s.repo.UpdatePassword(user.ID, hash)
+		if err != nil {
+			return nil, err
+		}
+	}
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	session := &models.Session{
+		UserID:           user.ID,
+		RefreshTokenHash: hashRefreshToken(refreshToken),
+		CreatedAt:        now,
+		ExpiresAt:        now.Add(s.tokens.RefreshTTL),
+	}
+	err = s.sessions.Add(session)
+	if err != nil {
+		return nil, err
+	}
+	return s.issueTokens(user, session.ID, refreshToken, now)
+}
+
+// Refresh exchanges a refresh token for new access and refresh tokens. The
+// old refresh token stops working, and the access token gets the roles the
+// user has now. A refresh token that was already exchanged may have been
+// stolen, so presenting it again revokes the whole session.
+func (s UserService) Refresh(refreshToken string) (*models.Tokens, error) {
+	hash := hashRefreshToken(refreshToken)
+	now := time.Now()
+	session, err := s.sessions.GetByRefreshTokenHash(hash)
+	if err != nil {
+		s.revokeReused(hash, now)
+		return nil, errors.New("Invalid refresh token")
+	}
+	if session.RevokedAt != nil || !now.Before(session.ExpiresAt) {
+		return nil, errors.New("Invalid refresh token")
+	}
+	user, err := s.repo.GetById(session.UserID)
+	if err != nil {
+		return nil, err
+	}
+	next, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	err = s.sessions.Rotate(session.ID, hash, hashRefreshToken(next), now.Add(s.tokens.RefreshTTL))
+	if err != nil {
+		// another refresh exchanged the same token first
+		s.revokeReused(hash, now)
+		return nil, errors.New("Invalid refresh token")
+	}
+	return s.issueTokens(user, session.ID, next, now)
+}
+
+// revokeReused revokes the session that has already exchanged the refresh
+// token, if there is one
+func (s UserService) revokeReused(hash string, at time.Time) {
+	session, err := s.sessions.GetByRotatedRefreshTokenHash(hash)
+	if err != nil || session.RevokedAt != nil {
+		return
+	}
+	err = s.sessions.Revoke(session.ID, at)
+	if err != nil {
+		log.Printf("failed to revoke session %s after refresh token reuse: %v", session.ID, err)
+		return
+	}
+	log.Printf("refresh token of session %s was reused, session revoked", session.ID)
+}
+
+// Logout revokes the session. Its access tokens are rejected right away.
+func (s UserService) Logout(sessionId string) error {
+	return s.sessions.Revoke(sessionId, time.Now())
+}
+
+// LogoutAll revokes every session of the user.
+func (s UserService) LogoutAll(userId string) error {
+	_, err := s.repo.GetById(userId)
+	if err != nil {
+		return err
+	}
+	return s.sessions.RevokeByUserId(userId, time.Now())
+}
+
+func (s UserService) issueTokens(user *models.User, sessionId, refreshToken string, now time.Time) (*models.Tokens, error) {
+	expiresAt := now.Add(s.tokens.TTL)
+	accessToken, err := s.sign(&tokenClaims{
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: expiresAt.Unix(),
+			IssuedAt:  now.Unix(),
+		},
+		UserId:    user.ID,
+		Roles:     user.Roles,
+		SessionId: sessionId,
+	})
+	if err != nil {
+		return nil, err
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenClaims{
+	return &models.Tokens{AccessToken: accessToken, RefreshToken: refreshToken, ExpiresAt: expiresAt}, nil
+}
+
+// GenerateStreamToken issues a short-lived token that opens a position
+// stream, for clients such as EventSource that can't send the Authorization
+// header. It belongs to the session of the caller.
+func (s UserService) GenerateStreamToken(identity Identity) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(StreamTokenTTL)
+	token, err := s.sign(&tokenClaims{
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(tokenTTL).Unix(),
-			IssuedAt:  time.Now().Unix(),
+			Audience:  streamAudience,
+			ExpiresAt: expiresAt.Unix(),
+			IssuedAt:  now.Unix(),
 		},
-		UserId: user.ID,
+		UserId:    identity.UserId,
+		Roles:     identity.Roles,
+		SessionId: identity.SessionId,
 	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+func (s UserService) sign(claims *tokenClaims) (string, error) {
+	if len(s.tokens.Keys) == 0 {
+		return "", errors.New("no signing key configured")
+	}
+	key := s.tokens.Keys[len(s.tokens.Keys)-1]
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Secret)
+}
+
+// ParseToken returns who the token was issued to. Tokens of revoked
+// sessions are rejected.
+func (s *UserService) ParseToken(accessToken string) (*Identity, error) {
+	return s.parse(accessToken, "")
+}
+
+// ParseStreamToken is ParseToken for stream tokens.
+func (s *UserService) ParseStreamToken(streamToken string) (*Identity, error) {
+	return s.parse(streamToken, streamAudience)
+}
 
-	return token.SignedString([]byte(signingKey))
+// CheckSession returns an error once the session is revoked or has expired,
+// for connections that outlive the token they were opened with.
+func (s UserService) CheckSession(sessionId string) error {
+	session, err := s.sessions.GetById(sessionId)
+	if err != nil || session.RevokedAt != nil {
+		return errors.New("session is revoked")
+	}
+	if !time.Now().Before(session.ExpiresAt) {
+		return errors.New("session has expired")
+	}
+	return nil
 }
 
-func (s *UserService) ParseToken(accessToken string) (string, error) {
-	token, err := jwt.ParseWithClaims(accessToken, &tokenClaims{}, func(token *jwt.Token) (interface{}, error) {
+func (s *UserService) parse(tokenString, audience string) (*Identity, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &tokenClaims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("invalid signing method")
 		}
-
-		return []byte(signingKey), nil
+		kid, _ := token.Header["kid"].(string)
+		for _, key := range s.tokens.Keys {
+			if key.ID == kid {
+				return key.Secret, nil
+			}
+		}
+		return nil, errors.New("unknown signing key")
 	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	claims, ok := token.Claims.(*tokenClaims)
 	if !ok {
-		return "", errors.New("token claims are not of type *tokenClaims")
+		return nil, errors.New("token claims are not of type *tokenClaims")
+	}
+	if claims.Audience != audience {
+		return nil, errors.New("token is not meant for this use")
+	}
+	session, err := s.sessions.GetById(claims.SessionId)
+	if err != nil || session.RevokedAt != nil {
+		return nil, errors.New("session is revoked")
+	}
+
+	return &Identity{UserId: claims.UserId, Roles: claims.Roles, SessionId: claims.SessionId}, nil
+}
+
+// GetAll returns the users with their roles, without password hashes.
+func (s UserService) GetAll() ([]models.User, error) {
+	users, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
 	}
+	for i := range users {
+		users[i].Password = ""
+	}
+	return users, nil
+}
+
+// GrantRole gives the user the role. It takes effect with the next token
+// the user signs in for.
+func (s UserService) GrantRole(userId, role string) (*models.User, error) {
+	if !userRoles[role] {
+		return nil, fmt.Errorf("Unknown role %s", role)
+	}
+	_, err := s.repo.GetById(userId)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.GrantRole(userId, role)
+	if err != nil {
+		return nil, err
+	}
+	return s.withoutPassword(userId)
+}
+
+// RevokeRole takes the role away from the user and ends their sessions, so
+// the role is gone right away. The last admin keeps the role so that roles
+// can still be managed.
+func (s UserService) RevokeRole(userId, role string) (*models.User, error) {
+	if !userRoles[role] {
+		return nil, fmt.Errorf("Unknown role %s", role)
+	}
+	user, err := s.repo.GetById(userId)
+	if err != nil {
+		return nil, err
+	}
+	if role == models.RoleAdmin {
+		admins, err := s.repo.CountByRole(models.RoleAdmin)
+		if err != nil {
+			return nil, err
+		}
+		if admins <= 1 && containsId(user.Roles, models.RoleAdmin) {
+			return nil, errors.New("Can't revoke the role of the last admin")
+		}
+	}
+	err = s.repo.RevokeRole(userId, role)
+	if err != nil {
+		return nil, err
+	}
+	err = s.sessions.RevokeByUserId(userId, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return s.withoutPassword(userId)
+}
 
-	return claims.UserId, nil
+func (s UserService) withoutPassword(userId string) (*models.User, error) {
+	user, err := s.repo.GetById(userId)
+	if err != nil {
+		return nil, err
+	}
+	user.Password = ""
+	return user, nil
+}
+
+// newRefreshToken returns a random opaque token. Only its hash is stored.
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
 }
 
-func generatePasswordHash(password string) string {
-	salt := viper.GetString("salt")
-	hash := sha1.New()
-	hash.Write([]byte(password))
-	return fmt.Sprintf("%x", hash.Sum([]byte(salt)))
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
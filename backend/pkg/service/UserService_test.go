@@ -0,0 +1,499 @@
+package service
+
+import (
+	"backend/pkg/models"
+	"errors"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"testing"
+	"time"
+)
+
+type MockUserRepository struct {
+	users map[string]*models.User
+}
+
+func newMockUserRepository(users ...models.User) *MockUserRepository {
+	m := &MockUserRepository{users: map[string]*models.User{}}
+	for i := range users {
+		m.users[users[i].ID] = &users[i]
+	}
+	return m
+}
+
+func (m *MockUserRepository) GetById(id string) (*models.User, error) {
+	user, ok := m.users[id]
+	if !ok {
+		return nil, errors.New("User not found")
+	}
+	copied := *user
+	copied.Roles = append([]string{}, user.Roles...)
+	return &copied, nil
+}
+
+func (m *MockUserRepository) GetByUsername(username string) (*models.User, error) {
+	for _, user := range m.users {
+		if user.Username == username {
+			return m.GetById(user.ID)
+		}
+	}
+	return nil, errors.New("User not found")
+}
+
+func (m *MockUserRepository) Add(user *models.User) error {
+	user.ID = user.Username
+	copied := *user
+	m.users[user.ID] = &copied
+	return nil
+}
+
+func (m *MockUserRepository) UpdatePassword(userId, passwordHash string) error {
+	m.users[userId].Password = passwordHash
+	return nil
+}
+
+func (m *MockUserRepository) GetAll() ([]models.User, error) {
+	var users []models.User
+	for id := range m.users {
+		user, _ := m.GetById(id)
+		users = append(users, *user)
+	}
+	return users, nil
+}
+
+func (m *MockUserRepository) GrantRole(userId, role string) error {
+	user := m.users[userId]
+	if !containsId(user.Roles, role) {
+		user.Roles = append(user.Roles, role)
+	}
+	return nil
+}
+
+func (m *MockUserRepository) RevokeRole(userId, role string) error {
+	user := m.users[userId]
+	for i, r := range user.Roles {
+		if r == role {
+			user.Roles = append(user.Roles[:i], user.Roles[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("Role not found")
+}
+
+func (m *MockUserRepository) CountByRole(role string) (int, error) {
+	count := 0
+	for _, user := range m.users {
+		if containsId(user.Roles, role) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+type MockSessionRepository struct {
+	sessions map[string]*models.Session
+	rotated  map[string]string
+}
+
+func newMockSessionRepository() *MockSessionRepository {
+	return &MockSessionRepository{sessions: map[string]*models.Session{}, rotated: map[string]string{}}
+}
+
+func (m *MockSessionRepository) GetById(id string) (*models.Session, error) {
+	session, ok := m.sessions[id]
+	if !ok {
+		return nil, errors.New("Session not found")
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (m *MockSessionRepository) GetByRefreshTokenHash(hash string) (*models.Session, error) {
+	for _, session := range m.sessions {
+		if session.RefreshTokenHash == hash {
+			return m.GetById(session.ID)
+		}
+	}
+	return nil, errors.New("Session not found")
+}
+
+func (m *MockSessionRepository) Add(session *models.Session) error {
+	session.ID = fmt.Sprintf("session-%d", len(m.sessions)+1)
+	copied := *session
+	m.sessions[session.ID] = &copied
+	return nil
+}
+
+func (m *MockSessionRepository) GetByRotatedRefreshTokenHash(hash string) (*models.Session, error) {
+	id, ok := m.rotated[hash]
+	if !ok {
+		return nil, errors.New("Session not found")
+	}
+	return m.GetById(id)
+}
+
+func (m *MockSessionRepository) Rotate(id, oldHash, newHash string, expiresAt time.Time) error {
+	session, ok := m.sessions[id]
+	if !ok || session.RefreshTokenHash != oldHash || session.RevokedAt != nil {
+		return errors.New("Session not found")
+	}
+	m.rotated[oldHash] = id
+	session.RefreshTokenHash = newHash
+	session.ExpiresAt = expiresAt
+	return nil
+}
+
+func (m *MockSessionRepository) Revoke(id string, at time.Time) error {
+	session, ok := m.sessions[id]
+	if !ok || session.RevokedAt != nil {
+		return errors.New("Session not found")
+	}
+	session.RevokedAt = &at
+	return nil
+}
+
+func (m *MockSessionRepository) RevokeByUserId(userId string, at time.Time) error {
+	for _, session := range m.sessions {
+		if session.UserID == userId && session.RevokedAt == nil {
+			session.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+var testTokenSettings = TokenSettings{
+	Keys:       []SigningKey{{ID: "test-1", Secret: []byte("test-secret-of-thirty-two-bytes!")}},
+	TTL:        DefaultTokenTTL(),
+	RefreshTTL: DefaultRefreshTTL(),
+}
+
+func TestUserService_Roles(t *testing.T) {
+	newRepo := func() *MockUserRepository {
+		return newMockUserRepository(
+			models.User{ID: "admin", Username: "admin", Password: "hash", Roles: []string{models.RoleViewer, models.RoleAdmin}},
+			models.User{ID: "user", Username: "user", Password: "hash", Roles: []string{models.RoleViewer}},
+		)
+	}
+
+	t.Run("Sign up as viewer", func(t *testing.T) {
+		repo := newRepo()
+		err := NewUserService(repo, newMockSessionRepository(), testTokenSettings).CreateUser(models.User{Username: "new", Password: "secret", Roles: []string{models.RoleAdmin}})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if roles := repo.users["new"].Roles; len(roles) != 1 || roles[0] != models.RoleViewer {
+			t.Errorf("Expected only the viewer role, got %v", roles)
+		}
+	})
+
+	t.Run("Grant role", func(t *testing.T) {
+		user, err := NewUserService(newRepo(), newMockSessionRepository(), testTokenSettings).GrantRole("user", models.RoleDispatcher)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !containsId(user.Roles, models.RoleDispatcher) {
+			t.Errorf("Expected dispatcher role, got %v", user.Roles)
+		}
+		if user.Password != "" {
+			t.Errorf("Expected password to be hidden, got '%s'", user.Password)
+		}
+	})
+
+	t.Run("Unknown role", func(t *testing.T) {
+		_, err := NewUserService(newRepo(), newMockSessionRepository(), testTokenSettings).GrantRole("user", "driver")
+		if err == nil || err.Error() != "Unknown role driver" {
+			t.Errorf("Expected unknown role error, got %v", err)
+		}
+	})
+
+	t.Run("Revoke role", func(t *testing.T) {
+		user, err := NewUserService(newRepo(), newMockSessionRepository(), testTokenSettings).RevokeRole("user", models.RoleViewer)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(user.Roles) != 0 {
+			t.Errorf("Expected no roles, got %v", user.Roles)
+		}
+	})
+
+	t.Run("Keep the last admin", func(t *testing.T) {
+		_, err := NewUserService(newRepo(), newMockSessionRepository(), testTokenSettings).RevokeRole("admin", models.RoleAdmin)
+		if err == nil || err.Error() != "Can't revoke the role of the last admin" {
+			t.Errorf("Expected last admin error, got %v", err)
+		}
+	})
+
+	t.Run("Roles in token", func(t *testing.T) {
+		repo := newRepo()
+		service := NewUserService(repo, newMockSessionRepository(), testTokenSettings)
+		repo.users["admin"].Password, _ = hashPassword("secret")
+		tokens, err := service.GenerateToken("admin", "secret")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		identity, err := service.ParseToken(tokens.AccessToken)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if identity.UserId != "admin" || !models.HasRole(identity.Roles, models.RoleDispatcher) {
+			t.Errorf("Expected admin with every role, got '%s' %v", identity.UserId, identity.Roles)
+		}
+	})
+}
+
+func TestUserService_GenerateToken(t *testing.T) {
+	t.Run("Sign in with bcrypt hash", func(t *testing.T) {
+		repo := newMockUserRepository()
+		service := NewUserService(repo, newMockSessionRepository(), testTokenSettings)
+		err := service.CreateUser(models.User{Username: "user", Password: "secret"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !isBcryptHash(repo.users["user"].Password) {
+			t.Fatalf("Expected bcrypt hash, got '%s'", repo.users["user"].Password)
+		}
+		_, err = service.GenerateToken("user", "secret")
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Rehash legacy password", func(t *testing.T) {
+		repo := newMockUserRepository(models.User{ID: "user", Username: "user", Password: legacyPasswordHash("secret")})
+		_, err := NewUserService(repo, newMockSessionRepository(), testTokenSettings).GenerateToken("user", "secret")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		hash := repo.users["user"].Password
+		if ok, rehash := checkPassword(hash, "secret"); !ok || rehash {
+			t.Errorf("Expected password to be rehashed with bcrypt, got '%s'", hash)
+		}
+	})
+
+	t.Run("Wrong password", func(t *testing.T) {
+		legacy := legacyPasswordHash("secret")
+		repo := newMockUserRepository(models.User{ID: "user", Username: "user", Password: legacy})
+		_, err := NewUserService(repo, newMockSessionRepository(), testTokenSettings).GenerateToken("user", "wrong")
+		if err == nil || err.Error() != "User not found" {
+			t.Errorf("Expected user not found error, got %v", err)
+		}
+		if repo.users["user"].Password != legacy {
+			t.Error("Expected legacy hash to stay until a successful sign-in")
+		}
+	})
+}
+
+func TestUserService_ParseToken(t *testing.T) {
+	oldKey := SigningKey{ID: "2025-01", Secret: []byte("old-secret-of-thirty-two-bytes!!")}
+	newKey := SigningKey{ID: "2025-07", Secret: []byte("new-secret-of-thirty-two-bytes!!")}
+	settings := func(keys ...SigningKey) TokenSettings {
+		return TokenSettings{Keys: keys, TTL: time.Hour, RefreshTTL: time.Hour}
+	}
+	signIn := func(keys ...SigningKey) (*MockUserRepository, *MockSessionRepository, *models.Tokens) {
+		repo := newMockUserRepository()
+		sessions := newMockSessionRepository()
+		service := NewUserService(repo, sessions, settings(keys...))
+		err := service.CreateUser(models.User{Username: "user", Password: "secret"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		tokens, err := service.GenerateToken("user", "secret")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return repo, sessions, tokens
+	}
+
+	t.Run("Sign with the newest key", func(t *testing.T) {
+		_, _, tokens := signIn(oldKey, newKey)
+		parsed, _, err := new(jwt.Parser).ParseUnverified(tokens.AccessToken, &tokenClaims{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if kid := parsed.Header["kid"]; kid != newKey.ID {
+			t.Errorf("Expected kid '%s', got '%v'", newKey.ID, kid)
+		}
+	})
+
+	t.Run("Accept tokens of a previous key", func(t *testing.T) {
+		repo, sessions, tokens := signIn(oldKey)
+		rotated := NewUserService(repo, sessions, settings(oldKey, newKey))
+		identity, err := rotated.ParseToken(tokens.AccessToken)
+		if err != nil || identity.UserId != "user" {
+			t.Errorf("Expected token of user 'user' to stay valid, got %v %v", identity, err)
+		}
+	})
+
+	t.Run("Reject tokens of a dropped key", func(t *testing.T) {
+		repo, sessions, tokens := signIn(oldKey)
+		rotated := NewUserService(repo, sessions, settings(newKey))
+		_, err := rotated.ParseToken(tokens.AccessToken)
+		if err == nil {
+			t.Error("Expected error for a token signed with a dropped key")
+		}
+	})
+
+	t.Run("Reject a secret under a known kid", func(t *testing.T) {
+		repo, sessions, tokens := signIn(newKey)
+		service := NewUserService(repo, sessions, settings(newKey))
+		identity, _ := service.ParseToken(tokens.AccessToken)
+		forged := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenClaims{UserId: "user", Roles: []string{models.RoleAdmin}, SessionId: identity.SessionId})
+		forged.Header["kid"] = newKey.ID
+		token, _ := forged.SignedString([]byte("qrkjk#4#%35FSFJlja#4353KSFjH"))
+		_, err := service.ParseToken(token)
+		if err == nil {
+			t.Error("Expected error for a forged token")
+		}
+	})
+}
+
+func TestUserService_Sessions(t *testing.T) {
+	signIn := func() (*UserService, *MockUserRepository, *models.Tokens) {
+		repo := newMockUserRepository()
+		service := NewUserService(repo, newMockSessionRepository(), testTokenSettings)
+		err := service.CreateUser(models.User{Username: "user", Password: "secret"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		tokens, err := service.GenerateToken("user", "secret")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return service, repo, tokens
+	}
+
+	t.Run("Refresh rotates the refresh token", func(t *testing.T) {
+		service, repo, tokens := signIn()
+		repo.users["user"].Roles = append(repo.users["user"].Roles, models.RoleDispatcher)
+		refreshed, err := service.Refresh(tokens.RefreshToken)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if refreshed.RefreshToken == tokens.RefreshToken {
+			t.Error("Expected a new refresh token")
+		}
+		identity, err := service.ParseToken(refreshed.AccessToken)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !containsId(identity.Roles, models.RoleDispatcher) {
+			t.Errorf("Expected refreshed token to carry the granted role, got %v", identity.Roles)
+		}
+		_, err = service.Refresh(tokens.RefreshToken)
+		if err == nil || err.Error() != "Invalid refresh token" {
+			t.Errorf("Expected used refresh token to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("Reused refresh token revokes the session", func(t *testing.T) {
+		service, _, tokens := signIn()
+		first, err := service.Refresh(tokens.RefreshToken)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		second, err := service.Refresh(first.RefreshToken)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		_, err = service.Refresh(tokens.RefreshToken)
+		if err == nil || err.Error() != "Invalid refresh token" {
+			t.Errorf("Expected reused refresh token to be rejected, got %v", err)
+		}
+		_, err = service.Refresh(second.RefreshToken)
+		if err == nil {
+			t.Error("Expected the current refresh token to stop working after reuse")
+		}
+		_, err = service.ParseToken(second.AccessToken)
+		if err == nil || err.Error() != "session is revoked" {
+			t.Errorf("Expected revoked session error, got %v", err)
+		}
+	})
+
+	t.Run("Logout", func(t *testing.T) {
+		service, _, tokens := signIn()
+		identity, err := service.ParseToken(tokens.AccessToken)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		err = service.Logout(identity.SessionId)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		_, err = service.ParseToken(tokens.AccessToken)
+		if err == nil || err.Error() != "session is revoked" {
+			t.Errorf("Expected revoked session error, got %v", err)
+		}
+		_, err = service.Refresh(tokens.RefreshToken)
+		if err == nil {
+			t.Error("Expected refresh of a revoked session to fail")
+		}
+	})
+
+	t.Run("Logout all sessions", func(t *testing.T) {
+		service, _, first := signIn()
+		second, err := service.GenerateToken("user", "secret")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		err = service.LogoutAll("user")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for _, tokens := range []*models.Tokens{first, second} {
+			if _, err := service.ParseToken(tokens.AccessToken); err == nil {
+				t.Error("Expected every session to be revoked")
+			}
+		}
+	})
+
+	t.Run("Stream token", func(t *testing.T) {
+		service, _, tokens := signIn()
+		identity, err := service.ParseToken(tokens.AccessToken)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		streamToken, _, err := service.GenerateStreamToken(*identity)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		streamIdentity, err := service.ParseStreamToken(streamToken)
+		if err != nil || streamIdentity.SessionId != identity.SessionId {
+			t.Errorf("Expected the stream token of the session, got %v and %v", streamIdentity, err)
+		}
+		if _, err := service.ParseToken(streamToken); err == nil {
+			t.Error("Expected a stream token not to pass as an access token")
+		}
+		if _, err := service.ParseStreamToken(tokens.AccessToken); err == nil {
+			t.Error("Expected an access token not to pass as a stream token")
+		}
+	})
+
+	t.Run("Check session", func(t *testing.T) {
+		service, _, tokens := signIn()
+		identity, err := service.ParseToken(tokens.AccessToken)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := service.CheckSession(identity.SessionId); err != nil {
+			t.Errorf("Expected a live session, got %v", err)
+		}
+		err = service.Logout(identity.SessionId)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := service.CheckSession(identity.SessionId); err == nil || err.Error() != "session is revoked" {
+			t.Errorf("Expected revoked session error, got %v", err)
+		}
+	})
+
+	t.Run("Revoking a role ends the sessions", func(t *testing.T) {
+		service, _, tokens := signIn()
+		_, err := service.RevokeRole("user", models.RoleViewer)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, err := service.ParseToken(tokens.AccessToken); err == nil {
+			t.Error("Expected the session to be revoked")
+		}
+	})
+}